@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/migrator"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/repository/mongodb"
+	"log/slog"
+	"os"
+)
+
+const (
+	cmdUp     = "up"
+	cmdDown   = "down"
+	cmdStatus = "status"
+)
+
+// Usage: migrate --config=path/to/config.yaml [--dry-run] [--to=N] [--steps=N] up|down|status
+func main() {
+	dryRun := flag.Bool("dry-run", false, "only print the migrations that would be applied or reverted")
+	target := flag.Int("to", 0, "version to migrate up to, all pending migrations by default")
+	steps := flag.Int("steps", 1, "number of migrations to revert")
+
+	cfg := config.MustLoad()
+
+	log := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	repo, err := mongodb.InitMongoRepository(&cfg.Mongo, cfg.IDGenerator, log)
+	if err != nil {
+		panic(fmt.Errorf("failed to initialize repository: %w", err))
+	}
+	defer func() {
+		_ = repo.Db.Disconnect(context.Background())
+	}()
+
+	m := migrator.New(log, repo.Db, cfg, migrator.All(), *dryRun)
+
+	ctx := context.Background()
+
+	switch cmd := flag.Arg(0); cmd {
+	case cmdUp:
+		err = m.Up(ctx, *target)
+	case cmdDown:
+		err = m.Down(ctx, *steps)
+	case cmdStatus, "":
+		err = m.Status(ctx)
+	default:
+		err = fmt.Errorf("unknown command %q, expected one of: up, down, status", cmd)
+	}
+
+	if err != nil {
+		log.Error("migration failed", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/email"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/phone"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/pii"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/repository/mongodb"
+	"log/slog"
+	"os"
+)
+
+// rotate-keys re-encrypts the personal data of every user, including the emails of
+// a pending email change, with a new data key wrapped by the current master key and
+// recomputes the email and phone number blind indexes. Users stored before encryption was enabled are encrypted for the
+// first time. Users changed concurrently are skipped and can be handled by running
+// the command again.
+//
+// Usage: rotate-keys --config=path/to/config.yaml [--dry-run]
+func main() {
+	dryRun := flag.Bool("dry-run", false, "only count the users that would be re-encrypted")
+
+	cfg := config.MustLoad()
+
+	log := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	cipher, err := pii.New(cfg.Encryption)
+	if err != nil {
+		panic(fmt.Errorf("failed to initialize pii encryption: %w", err))
+	}
+
+	repo, err := mongodb.InitMongoRepository(&cfg.Mongo, cfg.IDGenerator, log)
+	if err != nil {
+		panic(fmt.Errorf("failed to initialize repository: %w", err))
+	}
+	defer func() {
+		_ = repo.Db.Disconnect(context.Background())
+	}()
+
+	emails := email.NewNormalizer(cfg.Email.ProviderRules)
+	phones := phone.NewNormalizer(cfg.Phone.DefaultRegion)
+
+	var rotated, skipped int
+
+	err = repo.IterateUsers(context.Background(), func(user *models.User) error {
+		previous := *user
+
+		if err := cipher.Reencrypt(user); err != nil {
+			return fmt.Errorf("failed to re-encrypt user %d: %w", user.ID, err)
+		}
+
+		plain, err := decrypted(cipher, user)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt user %d: %w", user.ID, err)
+		}
+		user.EmailNormalized = cipher.BlindIndex(emails.Normalize(plain.Email))
+		if phoneNumber, err := phones.Normalize(plain.PhoneNumber); err == nil {
+			user.PhoneNormalized = cipher.BlindIndex(phoneNumber)
+		}
+		if change := plain.EmailChange; change != nil {
+			user.EmailChange.EmailNormalized = cipher.BlindIndex(emails.Normalize(change.Email))
+			if change.PreviousEmail != "" {
+				user.EmailChange.PreviousEmailNormalized = cipher.BlindIndex(emails.Normalize(change.PreviousEmail))
+			}
+		}
+
+		if *dryRun {
+			rotated++
+			return nil
+		}
+
+		ok, err := repo.ReplaceUserPII(context.Background(), &previous, user)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			skipped++
+			log.Warn("user changed concurrently, skipped", slog.Int64("user_id", user.ID))
+			return nil
+		}
+
+		rotated++
+		return nil
+	})
+	if err != nil {
+		log.Error("key rotation failed", slog.String("error", err.Error()),
+			slog.Int("rotated", rotated), slog.Int("skipped", skipped))
+		os.Exit(1)
+	}
+
+	log.Info("key rotation finished", slog.Bool("dry_run", *dryRun),
+		slog.Int("rotated", rotated), slog.Int("skipped", skipped))
+}
+
+// decrypted returns a copy of the encrypted user with plaintext personal data and
+// email change.
+func decrypted(cipher *pii.Cipher, user *models.User) (models.User, error) {
+	plain := *user
+	if plain.EmailChange != nil {
+		change := *plain.EmailChange
+		plain.EmailChange = &change
+	}
+
+	if err := cipher.Decrypt(&plain); err != nil {
+		return models.User{}, err
+	}
+	if err := cipher.DecryptEmailChange(&plain); err != nil {
+		return models.User{}, err
+	}
+
+	return plain, nil
+}
@@ -25,6 +25,7 @@ func main() {
 	application := app.New(log, cfg, cfg.TokenTTL)
 
 	go application.GRPCApp.MustRun()
+	go application.PurgeApp.Run()
 
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGTERM, syscall.SIGINT)
@@ -32,7 +33,7 @@ func main() {
 
 	log.Info("trying to shut down the application")
 
-	application.GRPCApp.Stop()
+	application.Stop()
 
 	log.Info("grpc server shut down")
 }
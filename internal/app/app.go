@@ -4,20 +4,51 @@ import (
 	"context"
 	"fmt"
 	grpcapp "github.com/Stanislau-Senkevich/GRPC_SSO/internal/app/grpc"
+	purgeapp "github.com/Stanislau-Senkevich/GRPC_SSO/internal/app/purge"
 	grpcclient "github.com/Stanislau-Senkevich/GRPC_SSO/internal/client/family/grpc"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/attributes"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/avatar"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/clients"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/email"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/hasher"
 	jwtmanager "github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/jwt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/meta"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/password"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/pepper"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/phone"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/pii"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/migrator"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/repository"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/repository/cache"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/repository/encrypted"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/repository/mongodb"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services/accesstoken"
+	attributeservice "github.com/Stanislau-Senkevich/GRPC_SSO/internal/services/attributes"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services/auth"
+	avatarservice "github.com/Stanislau-Senkevich/GRPC_SSO/internal/services/avatar"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services/emailchange"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services/export"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services/family"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services/impersonation"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services/introspection"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services/loginhistory"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services/permissions"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services/phoneverification"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services/session"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services/userinfo"
+	"github.com/redis/go-redis/v9"
 	"log/slog"
 	"time"
 )
 
 type App struct {
-	GRPCApp *grpcapp.App
+	GRPCApp  *grpcapp.App
+	PurgeApp *purgeapp.App
+	log      *slog.Logger
+	cache    *cache.CachedRepository
+	redis    *redis.Client
 }
 
 // New creates a new instance of the application with the provided configuration and dependencies.
@@ -26,14 +57,91 @@ func New(
 	cfg *config.Config,
 	tokenTTL time.Duration,
 ) *App {
-	repo, err := mongodb.InitMongoRepository(&cfg.Mongo, log)
+	mongoRepo, err := mongodb.InitMongoRepository(&cfg.Mongo, cfg.IDGenerator, log)
 	if err != nil {
 		panic(fmt.Errorf("failed to initialize repository: %w", err))
 	}
 
-	jwtManager := jwtmanager.New([]byte(cfg.SigningKey), tokenTTL)
+	if err = migrator.New(log, mongoRepo.Db, cfg, migrator.All(), false).
+		CheckUpToDate(context.Background()); err != nil {
+		panic(fmt.Errorf("failed to check migrations: %w", err))
+	}
+
+	var repo repository.Repository = mongoRepo
+
+	var (
+		cachedRepo  *cache.CachedRepository
+		redisClient *redis.Client
+	)
+
+	if cfg.Cache.Enabled {
+		cachedRepo, redisClient = newCachedRepository(log, &cfg.Cache, mongoRepo)
+		repo = cachedRepo
+		log.Info("user cache initialized")
+	}
+
+	if cfg.Encryption.Enabled {
+		cipher, err := pii.New(cfg.Encryption)
+		if err != nil {
+			panic(fmt.Errorf("failed to initialize pii encryption: %w", err))
+		}
+
+		repo = encrypted.New(log, repo, cipher)
+		log.Info("pii encryption initialized")
+	}
+
+	jwtManager := jwtmanager.New([]byte(cfg.SigningKey), tokenTTL, cfg.Token.Issuer)
 	log.Info("jwt-manager initialized")
 
+	passwordHasher, err := hasher.New(cfg.PasswordHash)
+	if err != nil {
+		panic(fmt.Errorf("failed to initialize password hasher: %w", err))
+	}
+
+	peppers, err := pepper.New(cfg.Pepper)
+	if err != nil {
+		panic(fmt.Errorf("failed to initialize peppers: %w", err))
+	}
+
+	passwordPolicy, err := password.NewPolicy(cfg.Policy)
+	if err != nil {
+		panic(fmt.Errorf("failed to initialize password policy: %w", err))
+	}
+
+	emailNormalizer := email.NewNormalizer(cfg.Email.ProviderRules)
+	phoneNormalizer := phone.NewNormalizer(cfg.Phone.DefaultRegion)
+	var emailSender email.Sender = email.NewLogSender(log)
+	if cfg.Email.SMTP.Host != "" {
+		emailSender, err = email.NewSMTPSender(cfg.Email.SMTP)
+		if err != nil {
+			panic(fmt.Errorf("failed to initialize email sender: %w", err))
+		}
+		log.Info("smtp email sender initialized")
+	} else {
+		log.Warn("smtp host is not configured, emails are only logged")
+	}
+
+	var smsSender phone.SMSSender = phone.NewLogSMSSender(log)
+	if cfg.Phone.SMS.WebhookURL != "" {
+		smsSender, err = phone.NewWebhookSMSSender(cfg.Phone.SMS)
+		if err != nil {
+			panic(fmt.Errorf("failed to initialize sms sender: %w", err))
+		}
+		log.Info("sms sender initialized")
+	} else {
+		log.Warn("sms webhook is not configured, text messages are only logged")
+	}
+
+	attributeRegistry, err := attributes.NewRegistry(cfg.Attributes)
+	if err != nil {
+		panic(fmt.Errorf("failed to initialize attribute registry: %w", err))
+	}
+
+	clientRegistry, err := clients.NewRegistry(&cfg.Token, cfg.Clients)
+	if err != nil {
+		panic(fmt.Errorf("failed to initialize client registry: %w", err))
+	}
+
 	familyClient, err := grpcclient.New(
 		context.Background(), log,
 		cfg.ClientsConfig.Family.Address,
@@ -44,15 +152,52 @@ func New(
 	}
 	log.Info("family client initialized")
 
-	authService := auth.New(log, repo, jwtManager, cfg.HashSalt)
+	authService := auth.New(log, repo, repo, repo, jwtManager, passwordHasher, peppers, passwordPolicy,
+		emailNormalizer, phoneNormalizer, emailSender, &cfg.SignUp, attributeRegistry, clientRegistry)
 	log.Info("auth service initialized")
 
+	reportPepperUsage(log, authService)
+
 	permService := permissions.New(log, repo)
 	log.Info("permissions service initialized")
 
-	userInfoService := userinfo.New(log, repo, jwtManager, cfg.HashSalt)
+	userInfoService := userinfo.New(log, repo, repo, jwtManager, passwordHasher, peppers, passwordPolicy, phoneNormalizer, cfg.Deletion.GracePeriod)
 	log.Info("userinfo service initialized")
 
+	emailChangeService := emailchange.New(
+		log, repo, repo, repo, repo,
+		jwtManager, emailNormalizer,
+		emailSender,
+		&cfg.Email)
+	log.Info("email change service initialized")
+
+	accessTokenService := accesstoken.New(log, repo, repo, jwtManager, clientRegistry, &cfg.AccessTokens)
+	log.Info("access token service initialized")
+
+	sessionService := session.New(log, repo, jwtManager)
+	log.Info("session service initialized")
+
+	exportService := export.New(log, repo, repo, repo, jwtManager, export.DefaultChunkSize)
+	log.Info("export service initialized")
+
+	loginHistoryService := loginhistory.New(log, repo, jwtManager)
+	log.Info("login history service initialized")
+
+	phoneVerificationService := phoneverification.New(log, repo, repo, jwtManager, smsSender, &cfg.Phone)
+	log.Info("phone verification service initialized")
+
+	attributeService := attributeservice.New(log, repo, repo, jwtManager, attributeRegistry)
+	log.Info("attribute service initialized")
+
+	avatarService := avatarservice.New(log, repo, repo, jwtManager, avatar.NewProcessor(&cfg.Avatar))
+	log.Info("avatar service initialized")
+
+	impersonationService := impersonation.New(log, repo, repo, repo, jwtManager, clientRegistry, &cfg.Impersonation)
+	log.Info("impersonation service initialized")
+
+	introspectionService := introspection.New(log, repo, repo, jwtManager, clientRegistry, &cfg.Introspection)
+	log.Info("introspection service initialized")
+
 	familyService := family.New(
 		familyClient,
 		jwtManager,
@@ -69,16 +214,207 @@ func New(
 		"/userinfo.UserInfo/AddFamily":       {"admin"},
 		"/userinfo.UserInfo/DeleteFamily":    {"admin"},
 		"/userinfo.UserInfo/DeleteUser":      {"admin"},
+		"/userinfo.UserInfo/RestoreUser":     {"admin"},
+		"/userinfo.UserInfo/SetUserStatus":   {"admin"},
+
+		"/session.Sessions/ListSessions":           {"user", "admin"},
+		"/session.Sessions/RevokeSession":          {"user", "admin"},
+		"/session.Sessions/RevokeAllOtherSessions": {"user", "admin"},
+
+		"/export.Export/ExportMyData":   {"user", "admin"},
+		"/export.Export/ExportUserData": {"admin"},
+
+		"/loginhistory.LoginHistory/GetLoginHistory":     {"user", "admin"},
+		"/loginhistory.LoginHistory/GetUserLoginHistory": {"admin"},
+
+		"/phoneverification.PhoneVerification/SendPhoneCode": {"user", "admin"},
+		"/phoneverification.PhoneVerification/VerifyPhone":   {"user", "admin"},
+
+		"/attributes.Attributes/GetAttributes": {"user", "admin"},
+		"/attributes.Attributes/SetAttributes": {"user", "admin"},
+
+		"/avatar.Avatars/UploadAvatar": {"user", "admin"},
+		"/avatar.Avatars/GetAvatar":    {"user", "admin"},
+		"/avatar.Avatars/DeleteAvatar": {"user", "admin"},
+
+		"/impersonation.Impersonation/Impersonate": {"admin"},
+
+		"/accesstoken.AccessTokens/CreateToken": {"user", "admin"},
+		"/accesstoken.AccessTokens/ListTokens":  {"user", "admin"},
+		"/accesstoken.AccessTokens/RevokeToken": {"user", "admin"},
+	}
+
+	// Scopes the token has to carry to call the method, on top of the role.
+	requiredScopes := map[string][]string{
+		"/permissions.Permissions/IsAdmin":   {"users:read"},
+		"/userinfo.UserInfo/GetUserInfo":     {"profile:read"},
+		"/userinfo.UserInfo/UpdateUserInfo":  {"profile:write"},
+		"/userinfo.UserInfo/ChangePassword":  {"password:write"},
+		"/userinfo.UserInfo/GetUserInfoByID": {"users:read"},
+		"/userinfo.UserInfo/AddFamily":       {"users:write"},
+		"/userinfo.UserInfo/DeleteFamily":    {"users:write"},
+		"/userinfo.UserInfo/DeleteUser":      {"users:write"},
+		"/userinfo.UserInfo/RestoreUser":     {"users:write"},
+		"/userinfo.UserInfo/SetUserStatus":   {"users:write"},
+
+		"/session.Sessions/ListSessions":           {"profile:read"},
+		"/session.Sessions/RevokeSession":          {"profile:write"},
+		"/session.Sessions/RevokeAllOtherSessions": {"profile:write"},
+
+		"/export.Export/ExportMyData":   {"profile:read"},
+		"/export.Export/ExportUserData": {"users:read"},
+
+		"/loginhistory.LoginHistory/GetLoginHistory":     {"profile:read"},
+		"/loginhistory.LoginHistory/GetUserLoginHistory": {"users:read"},
+
+		"/phoneverification.PhoneVerification/SendPhoneCode": {"profile:write"},
+		"/phoneverification.PhoneVerification/VerifyPhone":   {"profile:write"},
+
+		"/attributes.Attributes/GetAttributes": {"profile:read"},
+		"/attributes.Attributes/SetAttributes": {"profile:write"},
+
+		"/avatar.Avatars/UploadAvatar": {"profile:write"},
+		"/avatar.Avatars/GetAvatar":    {"profile:read"},
+		"/avatar.Avatars/DeleteAvatar": {"profile:write"},
+
+		"/impersonation.Impersonation/Impersonate": {"users:write"},
+
+		"/accesstoken.AccessTokens/CreateToken": {"profile:write"},
+		"/accesstoken.AccessTokens/ListTokens":  {"profile:read"},
+		"/accesstoken.AccessTokens/RevokeToken": {"profile:write"},
+	}
+
+	// Methods that cannot be called with the token of an admin impersonating a user.
+	impersonationBlocked := map[string]bool{
+		"/userinfo.UserInfo/ChangePassword": true,
+		"/userinfo.UserInfo/DeleteUser":     true,
+		"/userinfo.UserInfo/UpdateUserInfo": true,
+
+		"/phoneverification.PhoneVerification/SendPhoneCode": true,
+		"/phoneverification.PhoneVerification/VerifyPhone":   true,
+
+		"/attributes.Attributes/SetAttributes": true,
+
+		"/avatar.Avatars/UploadAvatar": true,
+		"/avatar.Avatars/DeleteAvatar": true,
+
+		"/impersonation.Impersonation/Impersonate": true,
+
+		"/accesstoken.AccessTokens/CreateToken": true,
+		"/accesstoken.AccessTokens/RevokeToken": true,
+	}
+
+	// Methods that cannot be called with a personal access token: DeleteUser calls
+	// the family service on behalf of the caller and the token is not accepted there,
+	// and the tokens are not bound to a session.
+	accessTokenBlocked := map[string]bool{
+		"/userinfo.UserInfo/DeleteUser": true,
+
+		"/session.Sessions/RevokeSession":          true,
+		"/session.Sessions/RevokeAllOtherSessions": true,
+
+		"/impersonation.Impersonation/Impersonate": true,
+
+		"/accesstoken.AccessTokens/CreateToken": true,
+	}
+
+	trustedProxies, err := meta.ParseTrustedProxies(cfg.GRPC.TrustedProxies)
+	if err != nil {
+		panic(fmt.Errorf("failed to parse trusted proxies: %w", err))
 	}
 
 	grpcApp := grpcapp.New(
 		log, &cfg.GRPC,
 		authService, permService,
-		userInfoService, familyService,
-		accessibleRoles, jwtManager,
+		userInfoService, familyService, emailChangeService, accessTokenService, sessionService,
+		exportService, loginHistoryService, phoneVerificationService, attributeService, avatarService,
+		impersonationService, introspectionService,
+		cfg.Token.Audience, accessibleRoles, requiredScopes, impersonationBlocked, accessTokenBlocked,
+		trustedProxies, cfg.Sessions.TouchInterval,
+		jwtManager, phoneNormalizer, repo, repo, repo,
 	)
 
+	purgeApp := purgeapp.New(log, repo, &cfg.Deletion)
+
 	return &App{
-		GRPCApp: grpcApp,
+		GRPCApp:  grpcApp,
+		PurgeApp: purgeApp,
+		log:      log,
+		cache:    cachedRepo,
+		redis:    redisClient,
+	}
+}
+
+// Stop gracefully stops the gRPC server and the purge job, reports the cache metrics and closes
+// the connection to Redis.
+func (a *App) Stop() {
+	a.GRPCApp.Stop()
+	a.PurgeApp.Stop()
+
+	if a.cache != nil {
+		stats := a.cache.Stats()
+		a.log.Info("user cache stats",
+			slog.Int64("local_hits", stats.LocalHits),
+			slog.Int64("remote_hits", stats.RemoteHits),
+			slog.Int64("misses", stats.Misses),
+			slog.Int64("invalidations", stats.Invalidations),
+			slog.Int64("errors", stats.Errors))
+	}
+
+	if a.redis != nil {
+		if err := a.redis.Close(); err != nil {
+			a.log.Warn("failed to close redis client", sl.Err(err))
+		}
+	}
+}
+
+// newCachedRepository wraps the repository with the user cache. The Redis tier is
+// added only if its address is configured; if Redis is unreachable at start-up the
+// cache still works and falls back to the repository on every remote miss.
+func newCachedRepository(
+	log *slog.Logger,
+	cfg *config.CacheConfig,
+	repo repository.Repository,
+) (*cache.CachedRepository, *redis.Client) {
+	var (
+		remote      cache.Store
+		redisClient *redis.Client
+	)
+
+	if cfg.Redis.Address != "" {
+		redisClient = redis.NewClient(&redis.Options{
+			Addr:     cfg.Redis.Address,
+			Password: cfg.Redis.Password,
+			DB:       cfg.Redis.DB,
+		})
+
+		if err := redisClient.Ping(context.Background()).Err(); err != nil {
+			log.Warn("failed to ping redis", sl.Err(err))
+		}
+
+		remote = cache.NewRedisStore(redisClient, cfg.Redis.KeyPrefix)
+	}
+
+	cachedRepo := cache.New(
+		log, repo,
+		cache.NewLRUStore(cfg.LRUSize), cfg.LocalTTL,
+		remote, cfg.Redis.TTL,
+	)
+
+	return cachedRepo, redisClient
+}
+
+// reportPepperUsage logs how many users still have password hashes created with
+// an outdated pepper version.
+func reportPepperUsage(log *slog.Logger, authService *auth.AuthService) {
+	usage, err := authService.OutdatedPepperUsage(context.Background())
+	if err != nil {
+		log.Warn("failed to count users by pepper version", sl.Err(err))
+		return
+	}
+
+	for version, count := range usage {
+		log.Warn("users with outdated pepper version",
+			slog.Int("pepper_version", version), slog.Int64("users", count))
 	}
 }
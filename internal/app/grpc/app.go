@@ -3,14 +3,28 @@ package grpcapp
 import (
 	"fmt"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/grpc/accesstoken"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/grpc/attributes"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/grpc/auth"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/grpc/avatar"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/grpc/emailchange"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/grpc/export"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/grpc/impersonation"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/grpc/introspection"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/grpc/loginhistory"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/grpc/permissions"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/grpc/phoneverification"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/grpc/session"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/grpc/userinfo"
 	jwtmanager "github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/jwt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/meta"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/phone"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/repository"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services"
 	"google.golang.org/grpc"
 	"log/slog"
 	"net"
+	"time"
 )
 
 type App struct {
@@ -28,10 +42,31 @@ func New(
 	permService services.Permissions,
 	userInfoService services.UserInfo,
 	familyService services.Family,
+	emailChangeService services.EmailChange,
+	accessTokenService services.AccessTokens,
+	sessionService services.Sessions,
+	exportService services.Export,
+	loginHistoryService services.LoginHistory,
+	phoneVerificationService services.PhoneVerification,
+	attributeService services.Attributes,
+	avatarService services.Avatars,
+	impersonationService services.Impersonation,
+	introspectionService services.Introspection,
+	audience string,
 	accessibleRoles map[string][]string,
+	requiredScopes map[string][]string,
+	impersonationBlocked map[string]bool,
+	accessTokenBlocked map[string]bool,
+	trustedProxies meta.TrustedProxies,
+	sessionTouchInterval time.Duration,
 	jwtManager *jwtmanager.Manager,
+	phones *phone.Normalizer,
+	sessionRepo repository.SessionRepository,
+	userRepo repository.UserInfoRepository,
+	auditRepo repository.AuditRepository,
 ) *App {
-	interceptor := NewJWTInterceptor(jwtManager, accessibleRoles)
+	interceptor := NewJWTInterceptor(jwtManager, sessionRepo, userRepo, auditRepo, accessTokenService,
+		audience, accessibleRoles, requiredScopes, impersonationBlocked, accessTokenBlocked, trustedProxies, sessionTouchInterval)
 
 	gRPCServer := grpc.NewServer(
 		grpc.UnaryInterceptor(interceptor.Unary()),
@@ -39,9 +74,19 @@ func New(
 		grpc.ConnectionTimeout(gRPCConfig.Timeout),
 	)
 
-	auth.Register(gRPCServer, log, authService)
+	auth.Register(gRPCServer, log, authService, phones)
 	permissions.Register(gRPCServer, log, permService)
-	userinfo.Register(gRPCServer, log, userInfoService, familyService)
+	userinfo.Register(gRPCServer, log, userInfoService, familyService, emailChangeService)
+	session.Register(gRPCServer, log, sessionService)
+	emailchange.Register(gRPCServer, log, emailChangeService)
+	export.Register(gRPCServer, log, exportService)
+	loginhistory.Register(gRPCServer, log, loginHistoryService)
+	phoneverification.Register(gRPCServer, log, phoneVerificationService)
+	attributes.Register(gRPCServer, log, attributeService)
+	avatar.Register(gRPCServer, log, avatarService)
+	impersonation.Register(gRPCServer, log, impersonationService)
+	introspection.Register(gRPCServer, log, introspectionService)
+	accesstoken.Register(gRPCServer, log, accessTokenService)
 
 	return &App{log, gRPCServer, gRPCConfig}
 }
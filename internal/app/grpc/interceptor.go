@@ -2,64 +2,289 @@ package grpcapp
 
 import (
 	"context"
+	"errors"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
 	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/jwt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/meta"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/repository"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"strings"
+	"time"
 )
 
 type JWTInterceptor struct {
-	manager         *jwt.Manager
-	accessibleRoles map[string][]string
+	manager              *jwt.Manager
+	sessions             repository.SessionRepository
+	users                repository.UserInfoRepository
+	audit                repository.AuditRepository
+	accessTokens         services.AccessTokens
+	audience             string
+	accessibleRoles      map[string][]string
+	requiredScopes       map[string][]string
+	impersonationBlocked map[string]bool
+	accessTokenBlocked   map[string]bool
+	trustedProxies       meta.TrustedProxies
+	sessionTouchInterval time.Duration
 }
 
-// NewJWTInterceptor creates a new instance of JWTInterceptor with the provided JWT manager and accessibleRoles map.
-// The JWTInterceptor is used as a gRPC server interceptor to validate JWT tokens and enforce role-based access control.
+// NewJWTInterceptor creates a new instance of JWTInterceptor with the provided JWT manager, session, user and
+// audit repositories, access token service, the audience of the service, accessibleRoles and requiredScopes maps,
+// the methods blocked under impersonation and for personal access tokens, the trusted proxies and the interval the last-seen time of sessions is
+// stored at. The JWTInterceptor is used as a gRPC server
+// interceptor to resolve the client IP address, validate JWT tokens and personal access tokens, reject tokens issued
+// for other audiences, of revoked sessions and of inactive users, enforce role- and scope-based access control and
+// audit the calls made with impersonation tokens.
 func NewJWTInterceptor(
 	manager *jwt.Manager,
+	sessions repository.SessionRepository,
+	users repository.UserInfoRepository,
+	audit repository.AuditRepository,
+	accessTokens services.AccessTokens,
+	audience string,
 	accessibleRoles map[string][]string,
+	requiredScopes map[string][]string,
+	impersonationBlocked map[string]bool,
+	accessTokenBlocked map[string]bool,
+	trustedProxies meta.TrustedProxies,
+	sessionTouchInterval time.Duration,
 ) *JWTInterceptor {
-	return &JWTInterceptor{manager: manager, accessibleRoles: accessibleRoles}
+	return &JWTInterceptor{
+		manager:              manager,
+		sessions:             sessions,
+		users:                users,
+		audit:                audit,
+		accessTokens:         accessTokens,
+		audience:             audience,
+		accessibleRoles:      accessibleRoles,
+		requiredScopes:       requiredScopes,
+		impersonationBlocked: impersonationBlocked,
+		accessTokenBlocked:   accessTokenBlocked,
+		trustedProxies:       trustedProxies,
+		sessionTouchInterval: sessionTouchInterval,
+	}
 }
 
-// authorize checks whether the user is authorized to access a specific gRPC method based on JWT token claims and accessible roles.
-func (i *JWTInterceptor) authorize(ctx context.Context, method string) error {
+// authorize checks whether the user is authorized to access a specific gRPC method based on JWT token claims,
+// accessible roles and required scopes. The issuer of the token is verified by ParseToken. Requests made with a
+// personal access token are checked by authorizeAccessToken. It returns the context the method is called with.
+func (i *JWTInterceptor) authorize(ctx context.Context, method string) (context.Context, error) {
 	_, ok := i.accessibleRoles[method]
 	if !ok {
 		// everyone can access
-		return nil
+		return ctx, nil
 	}
 
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
-		return status.Errorf(codes.Unauthenticated, "metadata is not provided")
+		return nil, status.Errorf(codes.Unauthenticated, "metadata is not provided")
 	}
 
 	values := md["authorization"]
 	if len(values) == 0 {
-		return status.Errorf(codes.Unauthenticated, grpcerror.ErrNoToken.Error())
+		return nil, status.Errorf(codes.Unauthenticated, grpcerror.ErrNoToken.Error())
 	}
 
 	if parts := strings.Fields(values[0]); len(parts) < 2 {
-		return status.Error(codes.Unauthenticated, grpcerror.ErrInvalidToken.Error())
+		return nil, status.Error(codes.Unauthenticated, grpcerror.ErrInvalidToken.Error())
 	}
 
 	accessToken := strings.Fields(values[0])[1]
+	if strings.HasPrefix(accessToken, models.AccessTokenPrefix) {
+		return i.authorizeAccessToken(ctx, method, accessToken)
+	}
+
 	claims, err := i.manager.ParseToken(accessToken)
 	if err != nil {
-		return status.Errorf(codes.Unauthenticated, grpcerror.ErrInvalidToken.Error())
+		return nil, status.Errorf(codes.Unauthenticated, grpcerror.ErrInvalidToken.Error())
+	}
+
+	if !jwt.HasAudience(claims, i.audience) {
+		return nil, status.Error(codes.Unauthenticated, grpcerror.ErrInvalidToken.Error())
+	}
+
+	sessionID, err := jwt.SessionIDFromClaims(claims)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, grpcerror.ErrInvalidToken.Error())
+	}
+
+	if err = i.checkSession(ctx, sessionID); err != nil {
+		return nil, err
+	}
+
+	userID, err := jwt.UserIDFromClaims(claims)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, grpcerror.ErrInvalidToken.Error())
+	}
+
+	if _, err = i.checkUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if !i.hasRole(method, claims["role"]) || !i.hasScopes(method, jwt.ScopesFromClaims(claims)) {
+		return nil, status.Errorf(codes.PermissionDenied, grpcerror.ErrForbidden.Error())
+	}
+
+	actorID, impersonated, err := jwt.ActorIDFromClaims(claims)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, grpcerror.ErrInvalidToken.Error())
+	}
+	if impersonated {
+		if err = i.checkImpersonation(ctx, method, actorID, userID, sessionID); err != nil {
+			return nil, err
+		}
+	}
+
+	return ctx, nil
+}
+
+// authorizeAccessToken checks a request made with a personal access token. Methods blocked for access tokens are
+// rejected, so that the token is never forwarded to other services. The role is taken from the stored user, so
+// that a demoted admin loses access at once. The returned context carries the claims of the token for
+// the services reading the user from the context.
+func (i *JWTInterceptor) authorizeAccessToken(
+	ctx context.Context,
+	method string,
+	secret string) (context.Context, error) {
+	if i.accessTokenBlocked[method] {
+		return nil, status.Error(codes.PermissionDenied, grpcerror.ErrForbidden.Error())
+	}
+
+	accessToken, err := i.accessTokens.Authenticate(ctx, secret)
+	if errors.Is(err, grpcerror.ErrInvalidToken) {
+		return nil, status.Error(codes.Unauthenticated, grpcerror.ErrInvalidToken.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, grpcerror.ErrInternalError.Error())
 	}
 
-	for _, role := range i.accessibleRoles[method] {
-		if role == claims["role"] {
-			return nil
+	user, err := i.checkUser(ctx, accessToken.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := jwt.AccessTokenClaims(user, accessToken.ID, accessToken.Scopes)
+
+	if !i.hasRole(method, claims["role"]) || !i.hasScopes(method, accessToken.Scopes) {
+		return nil, status.Errorf(codes.PermissionDenied, grpcerror.ErrForbidden.Error())
+	}
+
+	return jwt.ContextWithClaims(ctx, claims), nil
+}
+
+func (i *JWTInterceptor) hasRole(method string, role interface{}) bool {
+	for _, accessible := range i.accessibleRoles[method] {
+		if accessible == role {
+			return true
 		}
 	}
 
-	return status.Errorf(codes.PermissionDenied, grpcerror.ErrForbidden.Error())
+	return false
+}
+
+// hasScopes reports whether the provided scopes include every scope required by the method.
+func (i *JWTInterceptor) hasScopes(method string, scopes []string) bool {
+	granted := make(map[string]bool, len(scopes))
+	for _, scope := range scopes {
+		granted[scope] = true
+	}
+
+	for _, required := range i.requiredScopes[method] {
+		if !granted[required] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkImpersonation rejects calls of the methods blocked under impersonation and
+// calls of admins that are no longer admins, and records every other call made
+// with an impersonation token in the audit trail. Calls that cannot be recorded
+// are rejected.
+func (i *JWTInterceptor) checkImpersonation(
+	ctx context.Context,
+	method string,
+	actorID, userID int64,
+	sessionID string) error {
+	if i.impersonationBlocked[method] {
+		return status.Error(codes.PermissionDenied, grpcerror.ErrImpersonationDenied.Error())
+	}
+
+	actor, err := i.users.GetUserInfo(ctx, actorID)
+	if errors.Is(err, grpcerror.ErrUserNotFound) {
+		return status.Error(codes.Unauthenticated, grpcerror.ErrInvalidToken.Error())
+	}
+	if err != nil {
+		return status.Error(codes.Internal, grpcerror.ErrInternalError.Error())
+	}
+	if actor.Role != models.AdminRole || actor.EffectiveStatus(time.Now()) != models.StatusActive {
+		return status.Error(codes.PermissionDenied, grpcerror.ErrForbidden.Error())
+	}
+
+	err = i.audit.RecordAuditEvent(ctx, &models.AuditEvent{
+		Action:    models.AuditImpersonatedCall,
+		ActorID:   actorID,
+		UserID:    userID,
+		SessionID: sessionID,
+		Method:    method,
+		IP:        meta.ClientIP(ctx),
+		UserAgent: meta.UserAgent(ctx),
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return status.Error(codes.Internal, grpcerror.ErrInternalError.Error())
+	}
+
+	return nil
+}
+
+// checkSession verifies that the session the token was issued for is still active. The last-seen time of the
+// session is stored at most once per touch interval; failing to store it does not reject the request.
+func (i *JWTInterceptor) checkSession(ctx context.Context, sessionID string) error {
+	session, err := i.sessions.GetSession(ctx, sessionID)
+	if errors.Is(err, grpcerror.ErrSessionNotFound) {
+		return status.Error(codes.Unauthenticated, grpcerror.ErrSessionRevoked.Error())
+	}
+	if err != nil {
+		return status.Error(codes.Internal, grpcerror.ErrInternalError.Error())
+	}
+
+	if session.Revoked {
+		return status.Error(codes.Unauthenticated, grpcerror.ErrSessionRevoked.Error())
+	}
+
+	now := time.Now()
+	if now.Sub(session.LastSeenAt) >= i.sessionTouchInterval {
+		_ = i.sessions.TouchSession(ctx, sessionID, now)
+	}
+
+	return nil
+}
+
+// checkUser verifies that the user the token was issued to still exists and is
+// neither suspended nor disabled, so that already issued tokens stop working. It returns the user.
+func (i *JWTInterceptor) checkUser(ctx context.Context, userID int64) (models.User, error) {
+	user, err := i.users.GetUserInfo(ctx, userID)
+	if errors.Is(err, grpcerror.ErrUserNotFound) {
+		return models.User{}, status.Error(codes.Unauthenticated, grpcerror.ErrUserNotFound.Error())
+	}
+	if err != nil {
+		return models.User{}, status.Error(codes.Internal, grpcerror.ErrInternalError.Error())
+	}
+
+	switch user.EffectiveStatus(time.Now()) {
+	case models.StatusSuspended:
+		return models.User{}, status.Error(codes.PermissionDenied, grpcerror.ErrUserSuspended.Error())
+	case models.StatusDisabled:
+		return models.User{}, status.Error(codes.PermissionDenied, grpcerror.ErrUserDisabled.Error())
+	default:
+		return user, nil
+	}
 }
 
 // Unary returns a gRPC UnaryServerInterceptor that performs authorization checks before allowing the execution
@@ -71,7 +296,9 @@ func (i *JWTInterceptor) Unary() grpc.UnaryServerInterceptor {
 		info *grpc.UnaryServerInfo,
 		handler grpc.UnaryHandler,
 	) (interface{}, error) {
-		err := i.authorize(ctx, info.FullMethod)
+		ctx = meta.ContextWithClientIP(ctx, i.trustedProxies.ResolveClientIP(ctx))
+
+		ctx, err := i.authorize(ctx, info.FullMethod)
 		if err != nil {
 			return nil, err
 		}
@@ -89,11 +316,23 @@ func (i *JWTInterceptor) Stream() grpc.StreamServerInterceptor {
 		info *grpc.StreamServerInfo,
 		handler grpc.StreamHandler,
 	) error {
-		err := i.authorize(stream.Context(), info.FullMethod)
+		ctx := meta.ContextWithClientIP(stream.Context(), i.trustedProxies.ResolveClientIP(stream.Context()))
+
+		ctx, err := i.authorize(ctx, info.FullMethod)
 		if err != nil {
 			return err
 		}
 
-		return handler(srv, stream)
+		return handler(srv, &serverStream{ServerStream: stream, ctx: ctx})
 	}
 }
+
+// serverStream is a grpc.ServerStream with the context returned by authorize.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
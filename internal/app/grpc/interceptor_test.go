@@ -0,0 +1,67 @@
+package grpcapp
+
+import (
+	"context"
+	"errors"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+type fakeSessions struct {
+	repository.SessionRepository
+	sessions map[string]models.Session
+	err      error
+	touched  int
+}
+
+func (r *fakeSessions) GetSession(_ context.Context, sessionID string) (models.Session, error) {
+	if r.err != nil {
+		return models.Session{}, r.err
+	}
+	session, ok := r.sessions[sessionID]
+	if !ok {
+		return models.Session{}, grpcerror.ErrSessionNotFound
+	}
+	return session, nil
+}
+
+func (r *fakeSessions) TouchSession(_ context.Context, sessionID string, at time.Time) error {
+	session := r.sessions[sessionID]
+	session.LastSeenAt = at
+	r.sessions[sessionID] = session
+	r.touched++
+	return nil
+}
+
+func TestCheckSession(t *testing.T) {
+	repo := &fakeSessions{sessions: map[string]models.Session{
+		"active":  {ID: "active", LastSeenAt: time.Now().Add(-time.Hour)},
+		"revoked": {ID: "revoked", Revoked: true},
+	}}
+	i := &JWTInterceptor{sessions: repo, sessionTouchInterval: time.Minute}
+	ctx := context.Background()
+
+	assert.NoError(t, i.checkSession(ctx, "active"))
+	assert.NoError(t, i.checkSession(ctx, "active"))
+	assert.Equal(t, 1, repo.touched, "last seen time is stored once per touch interval")
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(i.checkSession(ctx, "revoked")))
+	assert.Equal(t, codes.Unauthenticated, status.Code(i.checkSession(ctx, "unknown")))
+
+	repo.err = errors.New("connection refused")
+	assert.Equal(t, codes.Internal, status.Code(i.checkSession(ctx, "active")),
+		"database failures are not reported as revoked sessions")
+}
+
+func TestAuthorizeAccessToken_Blocked(t *testing.T) {
+	i := &JWTInterceptor{accessTokenBlocked: map[string]bool{"/userinfo.UserInfo/DeleteUser": true}}
+
+	_, err := i.authorizeAccessToken(context.Background(), "/userinfo.UserInfo/DeleteUser", "secret")
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
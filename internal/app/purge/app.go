@@ -0,0 +1,100 @@
+package purgeapp
+
+import (
+	"context"
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/repository"
+	"log/slog"
+	"time"
+)
+
+// App periodically purges users whose soft-deletion grace period is over. The
+// users were removed from their families before they were soft-deleted.
+type App struct {
+	log  *slog.Logger
+	repo repository.PurgeRepository
+	cfg  *config.DeletionConfig
+	stop chan struct{}
+	done chan struct{}
+}
+
+// New creates a new instance of the purge job.
+func New(
+	log *slog.Logger,
+	repo repository.PurgeRepository,
+	cfg *config.DeletionConfig,
+) *App {
+	return &App{
+		log:  log,
+		repo: repo,
+		cfg:  cfg,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+}
+
+// Run purges the users every purge interval until Stop is called.
+func (a *App) Run() {
+	const op = "purgeapp.Run"
+
+	log := a.log.With(slog.String("op", op))
+
+	defer close(a.done)
+
+	ticker := time.NewTicker(a.cfg.PurgeInterval)
+	defer ticker.Stop()
+
+	log.Info("purge job is running", slog.Duration("interval", a.cfg.PurgeInterval))
+
+	for {
+		select {
+		case <-a.stop:
+			return
+		case <-ticker.C:
+			purged, err := a.Purge(context.Background())
+			if err != nil {
+				log.Error("failed to purge deleted users", sl.Err(err))
+			}
+			if purged > 0 {
+				log.Info("deleted users purged", slog.Int("count", purged))
+			}
+		}
+	}
+}
+
+// Stop stops the purge job and waits for the running purge to finish.
+func (a *App) Stop() {
+	const op = "purgeapp.Stop"
+
+	a.log.With(slog.String("op", op)).Info("stopping purge job")
+
+	close(a.stop)
+	<-a.done
+}
+
+// Purge removes the users that were deleted before the grace period and returns
+// how many users were purged. A user whose purge fails is left for the next run.
+func (a *App) Purge(ctx context.Context) (int, error) {
+	const op = "purgeapp.Purge"
+
+	log := a.log.With(slog.String("op", op))
+
+	users, err := a.repo.GetUsersToPurge(ctx, time.Now().Add(-a.cfg.GracePeriod))
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	purged := 0
+	for _, user := range users {
+		if err = a.repo.PurgeUser(ctx, user.ID); err != nil {
+			log.Error("failed to purge user", sl.Err(err), slog.Int64("user_id", user.ID))
+			continue
+		}
+
+		purged++
+	}
+
+	return purged, nil
+}
@@ -1,29 +1,100 @@
 package config
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"github.com/ilyakaznacheev/cleanenv"
 	"github.com/spf13/viper"
+	"log/slog"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
 const (
-	UserCollection     = "user"
-	SequenceCollection = "sequence"
+	UserCollection         = "user"
+	SequenceCollection     = "sequence"
+	SessionCollection      = "session"
+	MigrationCollection    = "migration"
+	LoginHistoryCollection = "login_history"
+	AvatarBucket           = "avatar"
+	AuditCollection        = "audit"
+	AccessTokenCollection  = "access_token"
 )
 
 type Config struct {
-	Env           string        `yaml:"env" env-default:"local"`
-	TokenTTL      time.Duration `yaml:"token_ttl"`
-	Mongo         MongoConfig   `yaml:"mongo_config"`
-	GRPC          GRPCConfig    `yaml:"grpc"`
-	ClientsConfig ClientsConfig `yaml:"clients_config"`
+	Env           string               `yaml:"env" env-default:"local"`
+	TokenTTL      time.Duration        `yaml:"token_ttl"`
+	Token         TokenConfig          `yaml:"token"`
+	Mongo         MongoConfig          `yaml:"mongo_config"`
+	GRPC          GRPCConfig           `yaml:"grpc"`
+	ClientsConfig ClientsConfig        `yaml:"clients_config"`
+	PasswordHash  PasswordHashConfig   `yaml:"password_hash"`
+	Pepper        PepperConfig         `yaml:"pepper"`
+	Policy        PasswordPolicyConfig `yaml:"password_policy"`
+	Email         EmailConfig          `yaml:"email"`
+	SignUp        SignUpConfig         `yaml:"sign_up"`
+	Phone         PhoneConfig          `yaml:"phone"`
+	IDGenerator   IDGeneratorConfig    `yaml:"id_generator"`
+	Cache         CacheConfig          `yaml:"cache"`
+	Encryption    EncryptionConfig     `yaml:"encryption"`
+	Deletion      DeletionConfig       `yaml:"deletion"`
+	LoginHistory  LoginHistoryConfig   `yaml:"login_history"`
+	Attributes    []AttributeSchema    `yaml:"attributes"`
+	Avatar        AvatarConfig         `yaml:"avatar"`
+	Impersonation ImpersonationConfig  `yaml:"impersonation"`
+	Introspection IntrospectionConfig  `yaml:"introspection"`
+	Clients       []ServiceClient      `yaml:"service_clients"`
+	AccessTokens  AccessTokenConfig    `yaml:"access_tokens"`
+	Sessions      SessionConfig        `yaml:"sessions"`
 	HashSalt      string
 	SigningKey    string
 }
 
+// redacted replaces secrets in the logged configuration.
+const redacted = "[REDACTED]"
+
+// logConfig is Config without the LogValue method, so that the redacted copy is
+// logged as a plain struct.
+type logConfig Config
+
+// LogValue returns the configuration with the secrets read from the environment
+// replaced, so that the configuration can be logged. The IDs of the master keys
+// and the pepper versions are kept.
+func (c Config) LogValue() slog.Value {
+	c.HashSalt = redactString(c.HashSalt)
+	c.SigningKey = redactString(c.SigningKey)
+	c.Mongo.Password = redactString(c.Mongo.Password)
+	c.Cache.Redis.Password = redactString(c.Cache.Redis.Password)
+	c.Email.SMTP.Password = redactString(c.Email.SMTP.Password)
+	c.Phone.SMS.APIKey = redactString(c.Phone.SMS.APIKey)
+	c.ClientsConfig.AdminPassword = redactString(c.ClientsConfig.AdminPassword)
+	c.Encryption.IndexKey = redactString(c.Encryption.IndexKey)
+
+	masterKeys := make(map[string]string, len(c.Encryption.MasterKeys))
+	for id := range c.Encryption.MasterKeys {
+		masterKeys[id] = redacted
+	}
+	c.Encryption.MasterKeys = masterKeys
+
+	peppers := make(map[int]string, len(c.Pepper.Versions))
+	for version := range c.Pepper.Versions {
+		peppers[version] = redacted
+	}
+	c.Pepper.Versions = peppers
+
+	return slog.AnyValue(logConfig(c))
+}
+
+func redactString(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return redacted
+}
+
 type MongoConfig struct {
 	User             string
 	Password         string
@@ -32,9 +103,217 @@ type MongoConfig struct {
 	Collections      map[string]string `yaml:"collections"`
 }
 
+// PasswordHashConfig holds the argon2id parameters used for new password hashes.
+type PasswordHashConfig struct {
+	Memory      uint32 `yaml:"memory" env-default:"65536"`
+	Iterations  uint32 `yaml:"iterations" env-default:"3"`
+	Parallelism uint8  `yaml:"parallelism" env-default:"2"`
+	SaltLength  uint32 `yaml:"salt_length" env-default:"16"`
+	KeyLength   uint32 `yaml:"key_length" env-default:"32"`
+}
+
+// IDGeneratorConfig selects how user IDs are generated: "sequence" takes IDs one by
+// one from the sequence collection, "block" reserves BlockSize IDs at a time, and
+// "snowflake" generates time-ordered IDs locally using NodeID.
+type IDGeneratorConfig struct {
+	Strategy  string `yaml:"strategy" env-default:"sequence"`
+	NodeID    int64  `yaml:"node_id" env-default:"0"`
+	BlockSize int64  `yaml:"block_size" env-default:"100"`
+}
+
+// CacheConfig holds the settings of the user lookup cache. The in-process LRU tier
+// is used whenever the cache is enabled, the Redis tier only if its address is set.
+type CacheConfig struct {
+	Enabled  bool          `yaml:"enabled" env-default:"false"`
+	LRUSize  int           `yaml:"lru_size" env-default:"10000"`
+	LocalTTL time.Duration `yaml:"local_ttl" env-default:"30s"`
+	Redis    RedisConfig   `yaml:"redis"`
+}
+
+type RedisConfig struct {
+	Address   string        `yaml:"address"`
+	DB        int           `yaml:"db" env-default:"0"`
+	TTL       time.Duration `yaml:"ttl" env-default:"10m"`
+	KeyPrefix string        `yaml:"key_prefix" env-default:"sso:"`
+	Password  string
+}
+
+// AttributeSchema describes a custom user attribute. Read and Write list the roles
+// that may access the attribute: "user" grants access to the owner of the
+// attribute, "admin" to administrators. Attributes with Claim set are included in
+// the tokens issued to the user. MaxLength limits the length of string values if
+// positive.
+type AttributeSchema struct {
+	Namespace string   `yaml:"namespace"`
+	Name      string   `yaml:"name"`
+	Type      string   `yaml:"type"`
+	MaxLength int      `yaml:"max_length"`
+	Read      []string `yaml:"read"`
+	Write     []string `yaml:"write"`
+	Claim     bool     `yaml:"claim"`
+}
+
+// AvatarConfig holds the avatar settings. Uploaded images of up to MaxSize bytes
+// and MaxPixels pixels are cropped to a square and stored resized to each of Sizes
+// in pixels. The GridFS bucket is taken from the "avatar" entry of the collections.
+type AvatarConfig struct {
+	MaxSize   int64 `yaml:"max_size" env-default:"5242880"`
+	MaxPixels int   `yaml:"max_pixels" env-default:"25000000"`
+	Sizes     []int `yaml:"sizes" env-default:"64,128,256"`
+}
+
+// ImpersonationConfig holds the admin impersonation settings. Impersonation tokens
+// expire after TokenTTL regardless of the regular token TTL.
+type ImpersonationConfig struct {
+	TokenTTL time.Duration `yaml:"token_ttl" env-default:"15m"`
+}
+
+// TokenConfig holds the claims of issued tokens. Tokens are issued by Issuer for
+// Audience, the service itself, unless the audience of a service client is
+// requested. Scopes are the scopes of the service itself; tokens carry the
+// requested scopes or, if none are requested, all scopes of the audience.
+type TokenConfig struct {
+	Issuer   string   `yaml:"issuer" env-default:"sso"`
+	Audience string   `yaml:"audience" env-default:"sso"`
+	Scopes   []string `yaml:"scopes" env-default:"profile:read,profile:write,password:write,users:read,users:write"`
+}
+
+// IntrospectionConfig holds the token introspection settings. Responses are cached
+// for CacheTTL in an LRU of CacheSize entries, so revocations and suspensions take
+// effect within CacheTTL.
+type IntrospectionConfig struct {
+	CacheTTL  time.Duration `yaml:"cache_ttl" env-default:"10s"`
+	CacheSize int           `yaml:"cache_size" env-default:"10000"`
+}
+
+// ServiceClient is a service that receives tokens issued for its ID as the audience
+// and may call the service-to-service RPCs. Scopes are the scopes tokens for the
+// service may carry. Only the hex-encoded SHA-256 hash of its secret is configured.
+type ServiceClient struct {
+	ID         string   `yaml:"id"`
+	SecretHash string   `yaml:"secret_hash"`
+	Scopes     []string `yaml:"scopes"`
+}
+
+// AccessTokenConfig holds the personal access token settings. Tokens expire after
+// at most MaxTTL, and a user can hold up to MaxPerUser active tokens. The last use
+// of a token is stored at most once per TouchInterval.
+type AccessTokenConfig struct {
+	MaxTTL        time.Duration `yaml:"max_ttl" env-default:"8760h"`
+	MaxPerUser    int           `yaml:"max_per_user" env-default:"20"`
+	TouchInterval time.Duration `yaml:"touch_interval" env-default:"1m"`
+}
+
+// SessionConfig holds the sign-in session settings. The last-seen time of a
+// session is stored at most once per TouchInterval.
+type SessionConfig struct {
+	TouchInterval time.Duration `yaml:"touch_interval" env-default:"1m"`
+}
+
+// DeletionConfig holds the soft delete settings: deleted users can be restored
+// within GracePeriod and are purged by a job running every PurgeInterval.
+type DeletionConfig struct {
+	GracePeriod   time.Duration `yaml:"grace_period" env-default:"720h"`
+	PurgeInterval time.Duration `yaml:"purge_interval" env-default:"1h"`
+}
+
+// LoginHistoryConfig holds how long sign-in attempts are kept. The retention is
+// applied by the TTL index created by the migrations, so changing it requires
+// recreating the index.
+type LoginHistoryConfig struct {
+	Retention time.Duration `yaml:"retention" env-default:"2160h"`
+}
+
+// EncryptionConfig holds the field-level encryption settings of personal data.
+// Master keys are read from the pii_master_keys environment variable in the form
+// "id1=base64key1,id2=base64key2", the blind index key from pii_index_key.
+type EncryptionConfig struct {
+	Enabled      bool   `yaml:"enabled" env-default:"false"`
+	CurrentKeyID string `yaml:"current_key_id"`
+	MasterKeys   map[string]string
+	IndexKey     string
+}
+
+// EmailConfig holds the email normalization and email change settings. The
+// confirmation and cancellation tokens of an email change are valid for ChangeTTL
+// and are appended to ConfirmURL and CancelURL in the messages sent to the user.
+type EmailConfig struct {
+	ProviderRules bool          `yaml:"provider_rules" env-default:"false"`
+	ChangeTTL     time.Duration `yaml:"change_ttl" env-default:"24h"`
+	ConfirmURL    string        `yaml:"confirm_url" env-default:"http://localhost:8080/email/confirm?token="`
+	CancelURL     string        `yaml:"cancel_url" env-default:"http://localhost:8080/email/cancel?token="`
+	SMTP          SMTPConfig    `yaml:"smtp"`
+}
+
+// SMTPConfig holds the mail server the emails are delivered through. Without a
+// host the emails are only written to the log. The password is read from
+// SMTP_PASSWORD.
+type SMTPConfig struct {
+	Host     string        `yaml:"host"`
+	Port     int           `yaml:"port" env-default:"587"`
+	Username string        `yaml:"username"`
+	From     string        `yaml:"from"`
+	Timeout  time.Duration `yaml:"timeout" env-default:"10s"`
+	Password string
+}
+
+// SignUpConfig holds the registration settings. In the enumeration-safe mode a
+// sign-up with a registered email gets the same response as a successful one, so
+// that registered emails cannot be discovered, and the owner of the email is
+// notified instead. The response then never contains the ID of the user.
+type SignUpConfig struct {
+	EnumerationSafe bool `yaml:"enumeration_safe" env-default:"false"`
+}
+
+// PhoneConfig holds the phone number settings. Numbers without a country calling
+// code are parsed as numbers of DefaultRegion. Verification codes of CodeLength
+// digits are valid for CodeTTL and MaxAttempts guesses, and a new code can be
+// requested once per ResendInterval.
+type PhoneConfig struct {
+	DefaultRegion  string        `yaml:"default_region" env-default:"BY"`
+	CodeLength     int           `yaml:"code_length" env-default:"6"`
+	CodeTTL        time.Duration `yaml:"code_ttl" env-default:"10m"`
+	MaxAttempts    int           `yaml:"max_attempts" env-default:"5"`
+	ResendInterval time.Duration `yaml:"resend_interval" env-default:"1m"`
+	SMS            SMSConfig     `yaml:"sms"`
+}
+
+// SMSConfig holds the HTTP gateway the text messages are delivered through. Every
+// message is posted to WebhookURL as JSON, authorized with the key from SMS_API_KEY.
+// Without a URL the messages are only written to the log.
+type SMSConfig struct {
+	WebhookURL string        `yaml:"webhook_url"`
+	Timeout    time.Duration `yaml:"timeout" env-default:"10s"`
+	APIKey     string
+}
+
+// PasswordPolicyConfig holds the rules new passwords have to satisfy.
+type PasswordPolicyConfig struct {
+	MinLength            int    `yaml:"min_length" env-default:"8"`
+	MaxLength            int    `yaml:"max_length" env-default:"128"`
+	RequireUpper         bool   `yaml:"require_upper" env-default:"true"`
+	RequireLower         bool   `yaml:"require_lower" env-default:"true"`
+	RequireDigit         bool   `yaml:"require_digit" env-default:"true"`
+	RequireSymbol        bool   `yaml:"require_symbol" env-default:"false"`
+	DisallowPersonalInfo bool   `yaml:"disallow_personal_info" env-default:"true"`
+	HistorySize          int    `yaml:"history_size" env-default:"5"`
+	BreachedListPath     string `yaml:"breached_list_path"`
+}
+
+// PepperConfig holds the versioned peppers appended to passwords before hashing.
+// Version 0 is the legacy HashSalt, other versions are read from the hash_salts
+// environment variable in the form "1=pepper1,2=pepper2".
+type PepperConfig struct {
+	CurrentVersion int `yaml:"current_version" env-default:"0"`
+	Versions       map[int]string
+}
+
 type GRPCConfig struct {
 	Port    int           `yaml:"port"`
 	Timeout time.Duration `yaml:"timeout"`
+	// TrustedProxies lists the addresses or CIDRs of the proxies whose
+	// x-forwarded-for header is honoured. Without any, the peer address is used.
+	TrustedProxies []string `yaml:"trusted_proxies"`
 }
 
 type Client struct {
@@ -94,7 +373,25 @@ func parseEnv(cfg *Config) error {
 	cfg.Mongo.User = viper.GetString("mongo_user")
 	cfg.Mongo.Password = viper.GetString("mongo_password")
 	cfg.HashSalt = viper.GetString("hash_salt")
+
+	peppers, err := parsePeppers(viper.GetString("hash_salts"))
+	if err != nil {
+		return err
+	}
+	peppers[0] = cfg.HashSalt
+	cfg.Pepper.Versions = peppers
+
+	masterKeys, err := parseMasterKeys(viper.GetString("pii_master_keys"))
+	if err != nil {
+		return err
+	}
+	cfg.Encryption.MasterKeys = masterKeys
+	cfg.Encryption.IndexKey = viper.GetString("pii_index_key")
+
 	cfg.SigningKey = viper.GetString("signing_key")
+	cfg.Cache.Redis.Password = viper.GetString("redis_password")
+	cfg.Email.SMTP.Password = viper.GetString("smtp_password")
+	cfg.Phone.SMS.APIKey = viper.GetString("sms_api_key")
 	cfg.ClientsConfig.AdminEmail = viper.GetString("admin_email")
 	cfg.ClientsConfig.AdminPassword = viper.GetString("admin_password")
 
@@ -118,10 +415,34 @@ func BindEnv() error {
 		return fmt.Errorf("failed to set up hash_salt: %w", err)
 	}
 
+	if err := viper.BindEnv("hash_salts"); err != nil {
+		return fmt.Errorf("failed to set up hash_salts: %w", err)
+	}
+
 	if err := viper.BindEnv("signing_key"); err != nil {
 		return fmt.Errorf("failed to set up signing_key: %w", err)
 	}
 
+	if err := viper.BindEnv("pii_master_keys"); err != nil {
+		return fmt.Errorf("failed to set up pii_master_keys: %w", err)
+	}
+
+	if err := viper.BindEnv("pii_index_key"); err != nil {
+		return fmt.Errorf("failed to set up pii_index_key: %w", err)
+	}
+
+	if err := viper.BindEnv("redis_password"); err != nil {
+		return fmt.Errorf("failed to set up redis_password: %w", err)
+	}
+
+	if err := viper.BindEnv("smtp_password"); err != nil {
+		return fmt.Errorf("failed to set up smtp_password: %w", err)
+	}
+
+	if err := viper.BindEnv("sms_api_key"); err != nil {
+		return fmt.Errorf("failed to set up sms_api_key: %w", err)
+	}
+
 	if err := viper.BindEnv("admin_email"); err != nil {
 		return fmt.Errorf("failed to set up admin_email: %w", err)
 	}
@@ -133,6 +454,51 @@ func BindEnv() error {
 	return nil
 }
 
+// parsePeppers parses versioned peppers in the form "1=pepper1,2=pepper2".
+func parsePeppers(raw string) (map[int]string, error) {
+	peppers := make(map[int]string)
+
+	for _, entry := range strings.Split(raw, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+
+		version, pepper, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, errors.New("invalid pepper entry in hash_salts")
+		}
+
+		v, err := strconv.Atoi(strings.TrimSpace(version))
+		if err != nil || v <= 0 {
+			return nil, fmt.Errorf("invalid pepper version in hash_salts: %q", version)
+		}
+
+		peppers[v] = pepper
+	}
+
+	return peppers, nil
+}
+
+// parseMasterKeys parses master keys in the form "id1=key1,id2=key2".
+func parseMasterKeys(raw string) (map[string]string, error) {
+	keys := make(map[string]string)
+
+	for _, entry := range strings.Split(raw, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+
+		id, key, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(id) == "" {
+			return nil, errors.New("invalid master key entry in pii_master_keys")
+		}
+
+		keys[strings.TrimSpace(id)] = strings.TrimSpace(key)
+	}
+
+	return keys, nil
+}
+
 func fetchConfigPath() string {
 	var res string
 
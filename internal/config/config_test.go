@@ -0,0 +1,33 @@
+package config
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+)
+
+func TestConfig_LogValue(t *testing.T) {
+	cfg := &Config{
+		HashSalt:   "hash-salt-secret",
+		SigningKey: "signing-key-secret",
+		Mongo:      MongoConfig{DBName: "sso", Password: "mongo-secret"},
+		Pepper:     PepperConfig{Versions: map[int]string{0: "hash-salt-secret", 1: "pepper-secret"}},
+		Encryption: EncryptionConfig{
+			CurrentKeyID: "1",
+			MasterKeys:   map[string]string{"1": "master-key-secret"},
+			IndexKey:     "index-key-secret",
+		},
+		ClientsConfig: ClientsConfig{AdminPassword: "admin-secret"},
+	}
+	cfg.Cache.Redis.Password = "redis-secret"
+
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("config", slog.Any("config", cfg))
+
+	assert.NotContains(t, buf.String(), "secret")
+	assert.Contains(t, buf.String(), `"DBName":"sso"`)
+	assert.Contains(t, buf.String(), redacted)
+	assert.Equal(t, "signing-key-secret", cfg.SigningKey, "the configuration is not changed")
+	assert.Equal(t, "pepper-secret", cfg.Pepper.Versions[1])
+}
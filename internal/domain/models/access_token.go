@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// AccessTokenPrefix starts every personal access token, so that the tokens can be
+// told apart from JWTs and recognized by secret scanners.
+const AccessTokenPrefix = "sso_pat_"
+
+// AccessToken is a personal access token of the user, used by scripts and service
+// accounts in place of a password. Only the hash of the token is stored. Tokens
+// created by an admin for another user carry the ID of the admin.
+type AccessToken struct {
+	ID         string     `bson:"token_id"`
+	UserID     int64      `bson:"user_id"`
+	Name       string     `bson:"name"`
+	Hash       string     `bson:"hash"`
+	Scopes     []string   `bson:"scopes"`
+	CreatedBy  int64      `bson:"created_by"`
+	CreatedAt  time.Time  `bson:"created_at"`
+	ExpiresAt  time.Time  `bson:"expires_at"`
+	LastUsedAt *time.Time `bson:"last_used_at,omitempty"`
+	Revoked    bool       `bson:"revoked"`
+}
@@ -0,0 +1,23 @@
+package models
+
+// AttributeType is the type of the value of a custom attribute.
+type AttributeType string
+
+const (
+	AttributeString AttributeType = "string"
+	AttributeInt    AttributeType = "int"
+	AttributeFloat  AttributeType = "float"
+	AttributeBool   AttributeType = "bool"
+)
+
+// Attribute is a custom attribute of the user, e.g. the locale of the user in the
+// "profile" namespace. Attributes are described by the schemas of the attribute
+// registry. A nil Value deletes the attribute.
+type Attribute struct {
+	Namespace string      `json:"namespace"`
+	Name      string      `json:"name"`
+	Value     interface{} `json:"value"`
+}
+
+// Attributes holds the custom attribute values of the user by namespace and name.
+type Attributes map[string]map[string]interface{}
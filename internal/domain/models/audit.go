@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Actions recorded in the audit trail.
+const (
+	AuditImpersonationStarted = "impersonation_started"
+	AuditImpersonatedCall     = "impersonated_call"
+)
+
+// AuditEvent is a record of an action performed by the actor, an admin, on behalf
+// of or against the user with the provided user ID.
+type AuditEvent struct {
+	Action    string    `bson:"action"`
+	ActorID   int64     `bson:"actor_id"`
+	UserID    int64     `bson:"user_id"`
+	SessionID string    `bson:"session_id,omitempty"`
+	Method    string    `bson:"method,omitempty"`
+	Reason    string    `bson:"reason,omitempty"`
+	IP        string    `bson:"ip"`
+	UserAgent string    `bson:"user_agent"`
+	CreatedAt time.Time `bson:"created_at"`
+}
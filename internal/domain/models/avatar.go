@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Avatar describes the current avatar of the user. The images of every size are
+// stored in GridFS and tagged with the user ID, the upload ID and the size.
+type Avatar struct {
+	UploadID    string    `bson:"upload_id"`
+	ContentType string    `bson:"content_type"`
+	Sizes       []int     `bson:"sizes"`
+	UpdatedAt   time.Time `bson:"updated_at"`
+}
+
+// AvatarImage is an avatar image of a single size.
+type AvatarImage struct {
+	ContentType string
+	Size        int
+	Data        []byte
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// EmailChange is a requested change of the user email. The change is applied only
+// after it is confirmed with the token sent to the new address, and it can be
+// cancelled, or reverted if already confirmed, with the token sent to the old
+// address until it expires. Only hashes of the tokens are stored.
+type EmailChange struct {
+	Email                   string     `bson:"email"`
+	EmailNormalized         string     `bson:"email_normalized"`
+	PreviousEmail           string     `bson:"previous_email,omitempty"`
+	PreviousEmailNormalized string     `bson:"previous_email_normalized,omitempty"`
+	ConfirmTokenHash        string     `bson:"confirm_token_hash"`
+	CancelTokenHash         string     `bson:"cancel_token_hash"`
+	RequestedAt             time.Time  `bson:"requested_at"`
+	ExpiresAt               time.Time  `bson:"expires_at"`
+	ConfirmedAt             *time.Time `bson:"confirmed_at,omitempty"`
+}
@@ -0,0 +1,9 @@
+package models
+
+// Grant is the audience and the scopes a token is issued for. When requested, an
+// empty audience stands for the service itself and no scopes for every scope of
+// the audience.
+type Grant struct {
+	Audience string
+	Scopes   []string
+}
@@ -0,0 +1,20 @@
+package models
+
+// Introspection is the state of a token as reported to resource servers, in the
+// spirit of RFC 7662. Inactive tokens carry no other fields, so that callers do not
+// learn why a token is rejected.
+type Introspection struct {
+	Active     bool                   `json:"active"`
+	Issuer     string                 `json:"iss,omitempty"`
+	Subject    string                 `json:"sub,omitempty"`
+	Audience   []string               `json:"aud,omitempty"`
+	Scope      string                 `json:"scope,omitempty"`
+	IssuedAt   int64                  `json:"iat,omitempty"`
+	UserID     int64                  `json:"user_id,omitempty"`
+	Email      string                 `json:"email,omitempty"`
+	Role       Role                   `json:"role,omitempty"`
+	SessionID  string                 `json:"session_id,omitempty"`
+	ActorID    int64                  `json:"actor_id,omitempty"`
+	ExpiresAt  int64                  `json:"exp,omitempty"`
+	Attributes map[string]interface{} `json:"attrs,omitempty"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Reasons of failed sign-in attempts.
+const (
+	LoginUnknownUser     = "unknown_user"
+	LoginInvalidPassword = "invalid_password"
+	LoginUserSuspended   = "user_suspended"
+	LoginUserDisabled    = "user_disabled"
+)
+
+// LoginAttempt is a record of a single sign-in attempt. Attempts for unknown
+// emails are recorded with a zero user ID.
+type LoginAttempt struct {
+	UserID        int64     `bson:"user_id"`
+	Success       bool      `bson:"success"`
+	FailureReason string    `bson:"failure_reason,omitempty"`
+	IP            string    `bson:"ip"`
+	UserAgent     string    `bson:"user_agent"`
+	MFAUsed       bool      `bson:"mfa_used"`
+	SessionID     string    `bson:"session_id,omitempty"`
+	CreatedAt     time.Time `bson:"created_at"`
+}
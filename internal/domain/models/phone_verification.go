@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// PhoneVerification is a pending verification of the user phone number with a
+// one-time code sent by SMS. Only a hash of the code is stored. The verification
+// is dropped when the phone number changes.
+type PhoneVerification struct {
+	CodeHash  string    `bson:"code_hash"`
+	Attempts  int       `bson:"attempts"`
+	SentAt    time.Time `bson:"sent_at"`
+	ExpiresAt time.Time `bson:"expires_at"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Session is a sign-in of the user. Sessions started by an admin impersonating the
+// user carry the ID of the admin.
+type Session struct {
+	ID         string    `bson:"session_id"`
+	UserID     int64     `bson:"user_id"`
+	UserAgent  string    `bson:"user_agent"`
+	IP         string    `bson:"ip"`
+	CreatedAt  time.Time `bson:"created_at"`
+	LastSeenAt time.Time `bson:"last_seen_at"`
+	Revoked    bool      `bson:"revoked"`
+	ActorID    int64     `bson:"actor_id,omitempty"`
+}
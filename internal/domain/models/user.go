@@ -9,14 +9,78 @@ const (
 	AdminRole Role = "admin"
 )
 
+// Profile fields that can be updated, named as in the UpdateUserInfo field mask.
+const (
+	FieldEmail       = "email"
+	FieldPhoneNumber = "phone_number"
+	FieldName        = "name"
+	FieldSurname     = "surname"
+)
+
+// AccountStatus controls whether a user can sign in and use issued tokens.
+type AccountStatus string
+
+const (
+	StatusActive    AccountStatus = "active"
+	StatusSuspended AccountStatus = "suspended"
+	StatusDisabled  AccountStatus = "disabled"
+)
+
 type User struct {
-	ID           int64     `bson:"user_id"`
-	Email        string    `bson:"email"`
-	PhoneNumber  string    `bson:"phone_number"`
-	Name         string    `bson:"name"`
-	Surname      string    `bson:"surname"`
-	PassHash     string    `bson:"pass_hash"`
-	RegisteredAt time.Time `bson:"registered_at"`
-	Role         Role      `bson:"role"`
-	FamilyIDs    []int64   `bson:"family_ids"`
+	ID                int64              `bson:"user_id"`
+	Email             string             `bson:"email"`
+	EmailNormalized   string             `bson:"email_normalized"`
+	PhoneNumber       string             `bson:"phone_number"`
+	PhoneNormalized   string             `bson:"phone_normalized,omitempty"`
+	PhoneVerified     bool               `bson:"phone_verified"`
+	Name              string             `bson:"name"`
+	Surname           string             `bson:"surname"`
+	PassHash          string             `bson:"pass_hash"`
+	PepperVersion     int                `bson:"pepper_version"`
+	PasswordHistory   []PasswordHash     `bson:"password_history,omitempty"`
+	RegisteredAt      time.Time          `bson:"registered_at"`
+	LastLoginAt       *time.Time         `bson:"last_login_at,omitempty"`
+	Role              Role               `bson:"role"`
+	FamilyIDs         []int64            `bson:"family_ids"`
+	DataKey           *DataKey           `bson:"data_key,omitempty"`
+	DeletedAt         *time.Time         `bson:"deleted_at,omitempty"`
+	Status            AccountStatus      `bson:"status,omitempty"`
+	StatusReason      string             `bson:"status_reason,omitempty"`
+	SuspendedUntil    *time.Time         `bson:"suspended_until,omitempty"`
+	Version           int64              `bson:"version"`
+	EmailChange       *EmailChange       `bson:"email_change,omitempty"`
+	PhoneVerification *PhoneVerification `bson:"phone_verification,omitempty"`
+	Attributes        Attributes         `bson:"attributes,omitempty"`
+	Avatar            *Avatar            `bson:"avatar,omitempty"`
+}
+
+// EffectiveStatus returns the account status of the user at the provided time.
+// Users without a recorded status are active, and a suspension ends by itself
+// once it expires, without the stored status being updated.
+func (u *User) EffectiveStatus(now time.Time) AccountStatus {
+	switch u.Status {
+	case StatusSuspended:
+		if u.SuspendedUntil != nil && !now.Before(*u.SuspendedUntil) {
+			return StatusActive
+		}
+		return StatusSuspended
+	case StatusDisabled:
+		return StatusDisabled
+	default:
+		return StatusActive
+	}
+}
+
+// DataKey is the key the personal data of a user is encrypted with, wrapped by
+// the master key with the provided ID.
+type DataKey struct {
+	KeyID   string `bson:"key_id"`
+	Wrapped []byte `bson:"wrapped"`
+}
+
+// PasswordHash is a previously used password hash along with the version of the
+// pepper it was created with.
+type PasswordHash struct {
+	Hash          string `bson:"hash"`
+	PepperVersion int    `bson:"pepper_version"`
 }
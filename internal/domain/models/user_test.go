@@ -0,0 +1,45 @@
+package models
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestUser_EffectiveStatus(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Minute), now.Add(time.Minute)
+
+	table := []struct {
+		name     string
+		user     User
+		expected AccountStatus
+	}{
+		{
+			name:     "Legacy user without status",
+			user:     User{},
+			expected: StatusActive,
+		},
+		{
+			name:     "Suspension in effect",
+			user:     User{Status: StatusSuspended, SuspendedUntil: &future},
+			expected: StatusSuspended,
+		},
+		{
+			name:     "Expired suspension",
+			user:     User{Status: StatusSuspended, SuspendedUntil: &past},
+			expected: StatusActive,
+		},
+		{
+			name:     "Disabled user",
+			user:     User{Status: StatusDisabled},
+			expected: StatusDisabled,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.user.EffectiveStatus(now))
+		})
+	}
+}
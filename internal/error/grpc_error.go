@@ -3,14 +3,47 @@ package grpcerror
 import "errors"
 
 var (
-	ErrInternalError   = errors.New("internal error")
-	ErrUserNotInFamily = errors.New("user already not in the family")
-	ErrUserInFamily    = errors.New("user already in the family")
-	ErrUserExists      = errors.New("user already exists")
-	ErrUserNotFound    = errors.New("user not found")
-	ErrInvalidPassword = errors.New("invalid password")
-	ErrNoToken         = errors.New("authorization token was not provided")
-	ErrInvalidToken    = errors.New("invalid token")
-	ErrTokenClaims     = errors.New("failed to get token claims")
-	ErrForbidden       = errors.New("forbidden")
+	ErrInternalError        = errors.New("internal error")
+	ErrUserNotInFamily      = errors.New("user already not in the family")
+	ErrUserInFamily         = errors.New("user already in the family")
+	ErrUserExists           = errors.New("user already exists")
+	ErrUserNotFound         = errors.New("user not found")
+	ErrInvalidPassword      = errors.New("invalid password")
+	ErrNoToken              = errors.New("authorization token was not provided")
+	ErrInvalidToken         = errors.New("invalid token")
+	ErrTokenClaims          = errors.New("failed to get token claims")
+	ErrForbidden            = errors.New("forbidden")
+	ErrSessionNotFound      = errors.New("session not found")
+	ErrSessionRevoked       = errors.New("session was revoked")
+	ErrWeakPassword         = errors.New("password does not satisfy the password policy")
+	ErrUserNotDeleted       = errors.New("user is not deleted")
+	ErrRestoreExpired       = errors.New("restore period of the user has expired")
+	ErrUserSuspended        = errors.New("user is suspended")
+	ErrUserDisabled         = errors.New("user is disabled")
+	ErrInvalidStatus        = errors.New("invalid account status")
+	ErrVersionConflict      = errors.New("user was modified concurrently")
+	ErrInvalidFieldMask     = errors.New("invalid field mask")
+	ErrInvalidPhone         = errors.New("invalid phone number")
+	ErrInvalidEmail         = errors.New("invalid email was provided")
+	ErrEmailChangeNotFound  = errors.New("email change not found or expired")
+	ErrEmailChangeRequired  = errors.New("email can only be changed with a confirmation")
+	ErrVerificationNotFound = errors.New("phone verification not found or expired")
+	ErrInvalidCode          = errors.New("invalid verification code")
+	ErrCodeRecentlySent     = errors.New("verification code was sent recently")
+	ErrPhoneVerified        = errors.New("phone number is already verified")
+	ErrUnknownAttribute     = errors.New("unknown attribute")
+	ErrInvalidAttribute     = errors.New("invalid attribute value")
+	ErrAvatarTooLarge       = errors.New("avatar image is too large")
+	ErrUnsupportedImage     = errors.New("unsupported avatar image")
+	ErrAvatarNotFound       = errors.New("avatar not found")
+	ErrImpersonationDenied  = errors.New("operation is not allowed under impersonation")
+	ErrImpersonateAdmin     = errors.New("admins cannot be impersonated")
+	ErrInvalidClient        = errors.New("invalid client credentials")
+	ErrUnknownAudience      = errors.New("unknown token audience")
+	ErrInvalidScope         = errors.New("scope is not allowed for the audience")
+	ErrPhoneInUse           = errors.New("phone number is already verified by another user")
+	ErrTokenNotFound        = errors.New("access token not found")
+	ErrInvalidTokenName     = errors.New("invalid access token name")
+	ErrInvalidTokenTTL      = errors.New("invalid access token lifetime")
+	ErrTooManyTokens        = errors.New("too many access tokens")
 )
@@ -0,0 +1,71 @@
+package accesstoken
+
+import (
+	"context"
+	"errors"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log/slog"
+)
+
+// CreateToken creates a personal access token for the requested user and returns its secret.
+// It delegates the operation to the CreateToken method of the AccessTokenService.
+func (s *serverAPI) CreateToken(
+	ctx context.Context,
+	req *ssov1.CreateTokenRequest) (
+	*ssov1.CreateTokenResponse, error) {
+	const op = "accesstoken.grpc.CreateToken"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", req.GetUserId()),
+	)
+
+	log.Info("trying to create access token")
+
+	if req.GetTtl() != nil {
+		if err := req.GetTtl().CheckValid(); err != nil {
+			return nil, status.Error(codes.InvalidArgument, grpcerror.ErrInvalidTokenTTL.Error())
+		}
+	}
+
+	secret, token, err := s.tokens.CreateToken(ctx,
+		req.GetUserId(), req.GetName(), req.GetScopes(), req.GetTtl().AsDuration())
+	if errors.Is(err, grpcerror.ErrInvalidTokenName) ||
+		errors.Is(err, grpcerror.ErrInvalidTokenTTL) ||
+		errors.Is(err, grpcerror.ErrUnknownAudience) ||
+		errors.Is(err, grpcerror.ErrInvalidScope) {
+		log.Info("invalid access token request", sl.Err(err))
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, grpcerror.ErrTooManyTokens) {
+		log.Info(grpcerror.ErrTooManyTokens.Error())
+		return nil, status.Error(codes.ResourceExhausted, grpcerror.ErrTooManyTokens.Error())
+	}
+	if errors.Is(err, grpcerror.ErrImpersonationDenied) {
+		log.Info(grpcerror.ErrImpersonationDenied.Error())
+		return nil, status.Error(codes.PermissionDenied, grpcerror.ErrImpersonationDenied.Error())
+	}
+	if errors.Is(err, grpcerror.ErrForbidden) {
+		log.Info(grpcerror.ErrForbidden.Error())
+		return nil, status.Error(codes.PermissionDenied, grpcerror.ErrForbidden.Error())
+	}
+	if errors.Is(err, grpcerror.ErrUserNotFound) {
+		log.Info(grpcerror.ErrUserNotFound.Error())
+		return nil, status.Error(codes.NotFound, grpcerror.ErrUserNotFound.Error())
+	}
+	if err != nil {
+		log.Error("failed to create access token", sl.Err(err))
+		return nil, status.Error(codes.Internal, grpcerror.ErrInternalError.Error())
+	}
+
+	log.Info("access token successfully created", slog.String("token_id", token.ID))
+
+	return &ssov1.CreateTokenResponse{
+		Token: secret,
+		Info:  toAccessTokenInfo(&token),
+	}, nil
+}
@@ -0,0 +1,53 @@
+package accesstoken
+
+import (
+	"context"
+	"errors"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log/slog"
+)
+
+// ListTokens returns the personal access tokens of the requested user that were not revoked.
+// It delegates the operation to the ListTokens method of the AccessTokenService.
+func (s *serverAPI) ListTokens(
+	ctx context.Context,
+	req *ssov1.ListTokensRequest) (
+	*ssov1.ListTokensResponse, error) {
+	const op = "accesstoken.grpc.ListTokens"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", req.GetUserId()),
+	)
+
+	log.Info("trying to list access tokens")
+
+	tokens, err := s.tokens.ListTokens(ctx, req.GetUserId())
+	if errors.Is(err, grpcerror.ErrForbidden) {
+		log.Info(grpcerror.ErrForbidden.Error())
+		return nil, status.Error(codes.PermissionDenied, grpcerror.ErrForbidden.Error())
+	}
+	if errors.Is(err, grpcerror.ErrUserNotFound) {
+		log.Info(grpcerror.ErrUserNotFound.Error())
+		return nil, status.Error(codes.NotFound, grpcerror.ErrUserNotFound.Error())
+	}
+	if err != nil {
+		log.Error("failed to list access tokens", sl.Err(err))
+		return nil, status.Error(codes.Internal, grpcerror.ErrInternalError.Error())
+	}
+
+	resp := &ssov1.ListTokensResponse{
+		Tokens: make([]*ssov1.AccessTokenInfo, 0, len(tokens)),
+	}
+	for i := range tokens {
+		resp.Tokens = append(resp.Tokens, toAccessTokenInfo(&tokens[i]))
+	}
+
+	log.Info("access tokens successfully listed")
+
+	return resp, nil
+}
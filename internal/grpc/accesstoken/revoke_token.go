@@ -0,0 +1,48 @@
+package accesstoken
+
+import (
+	"context"
+	"errors"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log/slog"
+)
+
+// RevokeToken revokes the personal access token with the provided ID.
+// It delegates the operation to the RevokeToken method of the AccessTokenService.
+func (s *serverAPI) RevokeToken(
+	ctx context.Context,
+	req *ssov1.RevokeTokenRequest) (
+	*ssov1.RevokeTokenResponse, error) {
+	const op = "accesstoken.grpc.RevokeToken"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("token_id", req.GetTokenId()),
+	)
+
+	log.Info("trying to revoke access token")
+
+	if req.GetTokenId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token_id is required")
+	}
+
+	err := s.tokens.RevokeToken(ctx, req.GetTokenId())
+	if errors.Is(err, grpcerror.ErrTokenNotFound) {
+		log.Info(grpcerror.ErrTokenNotFound.Error())
+		return nil, status.Error(codes.NotFound, grpcerror.ErrTokenNotFound.Error())
+	}
+	if err != nil {
+		log.Error("failed to revoke access token", sl.Err(err))
+		return nil, status.Error(codes.Internal, grpcerror.ErrInternalError.Error())
+	}
+
+	log.Info("access token successfully revoked")
+
+	return &ssov1.RevokeTokenResponse{
+		Succeed: true,
+	}, nil
+}
@@ -0,0 +1,42 @@
+package accesstoken
+
+import (
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"log/slog"
+)
+
+type serverAPI struct {
+	ssov1.UnimplementedAccessTokensServer
+	log    *slog.Logger
+	tokens services.AccessTokens
+}
+
+// Register registers the AccessTokens gRPC service implementation with the provided gRPC server.
+func Register(gRPC *grpc.Server, log *slog.Logger, tokens services.AccessTokens) {
+	ssov1.RegisterAccessTokensServer(gRPC, &serverAPI{
+		log:    log,
+		tokens: tokens,
+	})
+}
+
+func toAccessTokenInfo(token *models.AccessToken) *ssov1.AccessTokenInfo {
+	info := &ssov1.AccessTokenInfo{
+		Id:        token.ID,
+		UserId:    token.UserID,
+		Name:      token.Name,
+		Scopes:    token.Scopes,
+		CreatedBy: token.CreatedBy,
+		CreatedAt: timestamppb.New(token.CreatedAt.UTC()),
+		ExpiresAt: timestamppb.New(token.ExpiresAt.UTC()),
+	}
+
+	if token.LastUsedAt != nil {
+		info.LastUsedAt = timestamppb.New(token.LastUsedAt.UTC())
+	}
+
+	return info
+}
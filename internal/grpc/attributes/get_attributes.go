@@ -0,0 +1,62 @@
+package attributes
+
+import (
+	"context"
+	"errors"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"log/slog"
+)
+
+// GetAttributes returns the custom attributes of the requested user the user making the request may read.
+// It delegates the operation to the GetAttributes method of the AttributeService.
+func (s *serverAPI) GetAttributes(
+	ctx context.Context,
+	req *ssov1.GetAttributesRequest) (
+	*ssov1.GetAttributesResponse, error) {
+	const op = "attributes.grpc.GetAttributes"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", req.GetUserId()),
+	)
+
+	log.Info("trying to get attributes")
+
+	attrs, err := s.attrs.GetAttributes(ctx, req.GetUserId())
+	if errors.Is(err, grpcerror.ErrUserNotFound) {
+		log.Info(grpcerror.ErrUserNotFound.Error())
+		return nil, status.Error(codes.NotFound, grpcerror.ErrUserNotFound.Error())
+	}
+	if err != nil {
+		log.Error("failed to get attributes", sl.Err(err))
+		return nil, status.Error(codes.Internal, grpcerror.ErrInternalError.Error())
+	}
+
+	resp := &ssov1.GetAttributesResponse{
+		Attributes: make([]*ssov1.Attribute, 0, len(attrs)),
+	}
+
+	for _, attr := range attrs {
+		value, err := structpb.NewValue(attr.Value)
+		if err != nil {
+			log.Error("failed to convert attribute value", sl.Err(err),
+				slog.String("namespace", attr.Namespace), slog.String("name", attr.Name))
+			return nil, status.Error(codes.Internal, grpcerror.ErrInternalError.Error())
+		}
+
+		resp.Attributes = append(resp.Attributes, &ssov1.Attribute{
+			Namespace: attr.Namespace,
+			Name:      attr.Name,
+			Value:     value,
+		})
+	}
+
+	log.Info("attributes successfully got")
+
+	return resp, nil
+}
@@ -0,0 +1,22 @@
+package attributes
+
+import (
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"google.golang.org/grpc"
+	"log/slog"
+)
+
+type serverAPI struct {
+	ssov1.UnimplementedAttributesServer
+	log   *slog.Logger
+	attrs services.Attributes
+}
+
+// Register registers the Attributes gRPC service implementation with the provided gRPC server.
+func Register(gRPC *grpc.Server, log *slog.Logger, attrs services.Attributes) {
+	ssov1.RegisterAttributesServer(gRPC, &serverAPI{
+		log:   log,
+		attrs: attrs,
+	})
+}
@@ -0,0 +1,71 @@
+package attributes
+
+import (
+	"context"
+	"errors"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log/slog"
+)
+
+// SetAttributes sets the provided custom attributes of the requested user, deleting
+// those with a null value. It delegates the operation to the SetAttributes method of
+// the AttributeService.
+func (s *serverAPI) SetAttributes(
+	ctx context.Context,
+	req *ssov1.SetAttributesRequest) (
+	*ssov1.SetAttributesResponse, error) {
+	const op = "attributes.grpc.SetAttributes"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", req.GetUserId()),
+	)
+
+	log.Info("trying to set attributes")
+
+	if len(req.GetAttributes()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "attributes are required")
+	}
+
+	attrs := make([]models.Attribute, 0, len(req.GetAttributes()))
+	for _, attr := range req.GetAttributes() {
+		if attr.GetValue() == nil {
+			return nil, status.Error(codes.InvalidArgument, "attribute value is required")
+		}
+
+		attrs = append(attrs, models.Attribute{
+			Namespace: attr.GetNamespace(),
+			Name:      attr.GetName(),
+			Value:     attr.GetValue().AsInterface(),
+		})
+	}
+
+	err := s.attrs.SetAttributes(ctx, req.GetUserId(), attrs)
+	if errors.Is(err, grpcerror.ErrUnknownAttribute) || errors.Is(err, grpcerror.ErrInvalidAttribute) {
+		log.Info("invalid attributes", sl.Err(err))
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, grpcerror.ErrForbidden) {
+		log.Info(grpcerror.ErrForbidden.Error())
+		return nil, status.Error(codes.PermissionDenied, grpcerror.ErrForbidden.Error())
+	}
+	if errors.Is(err, grpcerror.ErrUserNotFound) {
+		log.Info(grpcerror.ErrUserNotFound.Error())
+		return nil, status.Error(codes.NotFound, grpcerror.ErrUserNotFound.Error())
+	}
+	if err != nil {
+		log.Error("failed to set attributes", sl.Err(err))
+		return nil, status.Error(codes.Internal, grpcerror.ErrInternalError.Error())
+	}
+
+	log.Info("attributes successfully set")
+
+	return &ssov1.SetAttributesResponse{
+		Succeed: true,
+	}, nil
+}
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/phone"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services"
 	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
 	"google.golang.org/grpc"
@@ -9,14 +10,16 @@ import (
 
 type serverAPI struct {
 	ssov1.UnimplementedAuthServer
-	log  *slog.Logger
-	auth services.Auth
+	log    *slog.Logger
+	auth   services.Auth
+	phones *phone.Normalizer
 }
 
 // Register associates the gRPC implementation of the Auth service with the provided gRPC server.
-func Register(gRPC *grpc.Server, log *slog.Logger, auth services.Auth) {
+func Register(gRPC *grpc.Server, log *slog.Logger, auth services.Auth, phones *phone.Normalizer) {
 	ssov1.RegisterAuthServer(gRPC, &serverAPI{
-		log:  log,
-		auth: auth,
+		log:    log,
+		auth:   auth,
+		phones: phones,
 	})
 }
@@ -3,6 +3,7 @@ package auth
 import (
 	"context"
 	"errors"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
 	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
 	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
@@ -10,10 +11,14 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"log/slog"
+	"strings"
 )
 
 // SignIn authenticates a user based on the provided gRPC request.
 // It delegates the user authentication operation to the SignIn method of the AuthService.
+// The email field of the request may also hold a verified phone number, as the
+// request has no separate identifier field yet. The request has no audience or
+// scopes either, so tokens are issued for the service itself with all its scopes.
 func (s *serverAPI) SignIn(
 	ctx context.Context,
 	req *ssov1.SignInRequest,
@@ -25,16 +30,27 @@ func (s *serverAPI) SignIn(
 
 	log.Info("trying to sign-in user")
 
-	if err := validateLogin(req.GetEmail(), req.GetPassword()); err != nil {
+	identifier := strings.TrimSpace(req.GetEmail())
+
+	if err := validateLogin(identifier, req.GetPassword()); err != nil {
 		log.Warn("invalid input", sl.Err(err))
 		return nil, err
 	}
 
-	token, err := s.auth.SignIn(ctx, req.GetEmail(), req.GetPassword())
+	token, err := s.auth.SignIn(ctx, identifier, req.GetPassword(), models.Grant{})
 	if err != nil {
 		if errors.Is(err, grpcerror.ErrUserNotFound) {
 			return nil, status.Error(codes.InvalidArgument, grpcerror.ErrUserNotFound.Error())
 		}
+		if errors.Is(err, grpcerror.ErrUserSuspended) {
+			return nil, status.Error(codes.PermissionDenied, grpcerror.ErrUserSuspended.Error())
+		}
+		if errors.Is(err, grpcerror.ErrUserDisabled) {
+			return nil, status.Error(codes.PermissionDenied, grpcerror.ErrUserDisabled.Error())
+		}
+		if errors.Is(err, grpcerror.ErrUnknownAudience) || errors.Is(err, grpcerror.ErrInvalidScope) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
 		log.Error("failed to log in user", sl.Err(err))
 		return nil, status.Error(codes.Internal, grpcerror.ErrInternalError.Error())
 	}
@@ -46,9 +62,17 @@ func (s *serverAPI) SignIn(
 	}, nil
 }
 
-func validateLogin(email, password string) error {
-	if err := checkmail.ValidateFormat(email); err != nil {
-		return status.Error(codes.InvalidArgument, "email format is invalid")
+// validateLogin checks the sign-in data. The format of emails is checked, phone
+// numbers are validated on lookup.
+func validateLogin(identifier, password string) error {
+	if identifier == "" {
+		return status.Error(codes.InvalidArgument, "email or phone number is required")
+	}
+
+	if strings.Contains(identifier, "@") {
+		if err := checkmail.ValidateFormat(identifier); err != nil {
+			return status.Error(codes.InvalidArgument, "email format is invalid")
+		}
 	}
 
 	if password == "" {
@@ -5,17 +5,21 @@ import (
 	"errors"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
 	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/password"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/phone"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
 	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
 	"github.com/badoux/checkmail"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"log/slog"
+	"strings"
 	"time"
 )
 
 // SignUp registers a new user based on the provided gRPC request.
 // It delegates the user registration operation to the SignUp method of the AuthService.
+// In the enumeration-safe mode the response contains no user ID.
 func (s *serverAPI) SignUp(
 	ctx context.Context,
 	req *ssov1.SignUpRequest,
@@ -29,7 +33,7 @@ func (s *serverAPI) SignUp(
 
 	preUser := &models.User{
 		ID:           -1,
-		Email:        req.GetEmail(),
+		Email:        strings.TrimSpace(req.GetEmail()),
 		PhoneNumber:  req.GetPhoneNumber(),
 		Name:         req.GetName(),
 		Surname:      req.GetSurname(),
@@ -37,13 +41,17 @@ func (s *serverAPI) SignUp(
 		RegisteredAt: time.Now().UTC(),
 	}
 
-	if err := validateRegister(preUser); err != nil {
+	if err := validateRegister(preUser, s.phones); err != nil {
 		log.Info("invalid input", sl.Err(err))
 		return nil, err
 	}
 
 	userId, err := s.auth.SignUp(ctx, preUser)
 	if err != nil {
+		var policyErr *password.PolicyError
+		if errors.As(err, &policyErr) {
+			return nil, policyErr.GRPCStatus().Err()
+		}
 		if errors.Is(err, grpcerror.ErrUserExists) {
 			return nil, status.Error(codes.InvalidArgument, grpcerror.ErrUserExists.Error())
 		}
@@ -58,7 +66,9 @@ func (s *serverAPI) SignUp(
 	}, nil
 }
 
-func validateRegister(user *models.User) error {
+// validateRegister checks the registration data and formats the phone number of
+// the user in E.164.
+func validateRegister(user *models.User, phones *phone.Normalizer) error {
 	if err := checkmail.ValidateFormat(user.Email); err != nil {
 		return status.Error(codes.InvalidArgument, "email format is invalid")
 	}
@@ -71,6 +81,12 @@ func validateRegister(user *models.User) error {
 		return status.Error(codes.InvalidArgument, "phone number is required")
 	}
 
+	phoneNumber, err := phones.Normalize(user.PhoneNumber)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, grpcerror.ErrInvalidPhone.Error())
+	}
+	user.PhoneNumber = phoneNumber
+
 	if user.Name == "" {
 		return status.Error(codes.InvalidArgument, "name is required")
 	}
@@ -0,0 +1,37 @@
+package avatar
+
+import (
+	"context"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log/slog"
+)
+
+// DeleteAvatar removes the avatar of the user making the request.
+// It delegates the operation to the DeleteAvatar method of the AvatarService.
+func (s *serverAPI) DeleteAvatar(
+	ctx context.Context,
+	_ *ssov1.DeleteAvatarRequest) (
+	*ssov1.DeleteAvatarResponse, error) {
+	const op = "avatar.grpc.DeleteAvatar"
+
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("trying to delete avatar")
+
+	if err := s.avatars.DeleteAvatar(ctx); err != nil {
+		log.Error("failed to delete avatar", sl.Err(err))
+		return nil, status.Error(codes.Internal, grpcerror.ErrInternalError.Error())
+	}
+
+	log.Info("avatar successfully deleted")
+
+	return &ssov1.DeleteAvatarResponse{
+		Succeed: true,
+	}, nil
+}
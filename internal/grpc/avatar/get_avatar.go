@@ -0,0 +1,54 @@
+package avatar
+
+import (
+	"context"
+	"errors"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log/slog"
+)
+
+// GetAvatar returns the avatar image of the requested user closest to the requested size.
+// It delegates the operation to the GetAvatar method of the AvatarService.
+func (s *serverAPI) GetAvatar(
+	ctx context.Context,
+	req *ssov1.GetAvatarRequest) (
+	*ssov1.GetAvatarResponse, error) {
+	const op = "avatar.grpc.GetAvatar"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", req.GetUserId()),
+	)
+
+	log.Info("trying to get avatar")
+
+	if req.GetSize() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "size must not be negative")
+	}
+
+	image, err := s.avatars.GetAvatar(ctx, req.GetUserId(), int(req.GetSize()))
+	if errors.Is(err, grpcerror.ErrAvatarNotFound) {
+		log.Info(grpcerror.ErrAvatarNotFound.Error())
+		return nil, status.Error(codes.NotFound, grpcerror.ErrAvatarNotFound.Error())
+	}
+	if errors.Is(err, grpcerror.ErrUserNotFound) {
+		log.Info(grpcerror.ErrUserNotFound.Error())
+		return nil, status.Error(codes.NotFound, grpcerror.ErrUserNotFound.Error())
+	}
+	if err != nil {
+		log.Error("failed to get avatar", sl.Err(err))
+		return nil, status.Error(codes.Internal, grpcerror.ErrInternalError.Error())
+	}
+
+	log.Info("avatar successfully got")
+
+	return &ssov1.GetAvatarResponse{
+		ContentType: image.ContentType,
+		Size:        int32(image.Size),
+		Data:        image.Data,
+	}, nil
+}
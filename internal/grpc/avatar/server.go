@@ -0,0 +1,22 @@
+package avatar
+
+import (
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"google.golang.org/grpc"
+	"log/slog"
+)
+
+type serverAPI struct {
+	ssov1.UnimplementedAvatarsServer
+	log     *slog.Logger
+	avatars services.Avatars
+}
+
+// Register registers the Avatars gRPC service implementation with the provided gRPC server.
+func Register(gRPC *grpc.Server, log *slog.Logger, avatars services.Avatars) {
+	ssov1.RegisterAvatarsServer(gRPC, &serverAPI{
+		log:     log,
+		avatars: avatars,
+	})
+}
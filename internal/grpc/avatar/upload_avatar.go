@@ -0,0 +1,61 @@
+package avatar
+
+import (
+	"errors"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log/slog"
+)
+
+// UploadAvatar replaces the avatar of the user making the request with the image
+// streamed by the client. It delegates the operation to the UploadAvatar method of
+// the AvatarService.
+func (s *serverAPI) UploadAvatar(stream ssov1.Avatars_UploadAvatarServer) error {
+	const op = "avatar.grpc.UploadAvatar"
+
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("trying to upload avatar")
+
+	err := s.avatars.UploadAvatar(stream.Context(), &chunkReader{stream: stream})
+	if errors.Is(err, grpcerror.ErrAvatarTooLarge) || errors.Is(err, grpcerror.ErrUnsupportedImage) {
+		log.Info("avatar rejected", sl.Err(err))
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		log.Error("failed to upload avatar", sl.Err(err))
+		return status.Error(codes.Internal, grpcerror.ErrInternalError.Error())
+	}
+
+	log.Info("avatar successfully uploaded")
+
+	return stream.SendAndClose(&ssov1.UploadAvatarResponse{
+		Succeed: true,
+	})
+}
+
+// chunkReader reads the image sent in the chunks of the upload stream.
+type chunkReader struct {
+	stream ssov1.Avatars_UploadAvatarServer
+	buf    []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		chunk, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.buf = chunk.GetData()
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+
+	return n, nil
+}
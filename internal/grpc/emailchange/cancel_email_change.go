@@ -0,0 +1,53 @@
+package emailchange
+
+import (
+	"context"
+	"errors"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log/slog"
+)
+
+// CancelEmailChange cancels the email change the provided cancellation token was
+// sent for, restoring the previous email if the change was already confirmed. The
+// token is the only credential, so the method requires no authorization.
+// It delegates the cancellation to the CancelEmailChange method of the EmailChangeService.
+func (s *serverAPI) CancelEmailChange(
+	ctx context.Context,
+	req *ssov1.CancelEmailChangeRequest) (
+	*ssov1.CancelEmailChangeResponse, error) {
+	const op = "emailchange.grpc.CancelEmailChange"
+
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("trying to cancel email change")
+
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	err := s.emailChange.CancelEmailChange(ctx, req.GetToken())
+	if errors.Is(err, grpcerror.ErrEmailChangeNotFound) {
+		log.Info(grpcerror.ErrEmailChangeNotFound.Error())
+		return nil, status.Error(codes.NotFound, grpcerror.ErrEmailChangeNotFound.Error())
+	}
+	if errors.Is(err, grpcerror.ErrUserExists) {
+		log.Info(grpcerror.ErrUserExists.Error())
+		return nil, status.Error(codes.AlreadyExists, grpcerror.ErrUserExists.Error())
+	}
+	if err != nil {
+		log.Error("failed to cancel email change", sl.Err(err))
+		return nil, status.Error(codes.Internal, grpcerror.ErrInternalError.Error())
+	}
+
+	log.Info("email change successfully canceled")
+
+	return &ssov1.CancelEmailChangeResponse{
+		Succeed: true,
+	}, nil
+}
@@ -0,0 +1,52 @@
+package emailchange
+
+import (
+	"context"
+	"errors"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log/slog"
+)
+
+// ConfirmEmailChange applies the email change the provided confirmation token was
+// sent for. The token is the only credential, so the method requires no authorization.
+// It delegates the confirmation to the ConfirmEmailChange method of the EmailChangeService.
+func (s *serverAPI) ConfirmEmailChange(
+	ctx context.Context,
+	req *ssov1.ConfirmEmailChangeRequest) (
+	*ssov1.ConfirmEmailChangeResponse, error) {
+	const op = "emailchange.grpc.ConfirmEmailChange"
+
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("trying to confirm email change")
+
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	err := s.emailChange.ConfirmEmailChange(ctx, req.GetToken())
+	if errors.Is(err, grpcerror.ErrEmailChangeNotFound) {
+		log.Info(grpcerror.ErrEmailChangeNotFound.Error())
+		return nil, status.Error(codes.NotFound, grpcerror.ErrEmailChangeNotFound.Error())
+	}
+	if errors.Is(err, grpcerror.ErrUserExists) {
+		log.Info(grpcerror.ErrUserExists.Error())
+		return nil, status.Error(codes.AlreadyExists, grpcerror.ErrUserExists.Error())
+	}
+	if err != nil {
+		log.Error("failed to confirm email change", sl.Err(err))
+		return nil, status.Error(codes.Internal, grpcerror.ErrInternalError.Error())
+	}
+
+	log.Info("email change successfully confirmed")
+
+	return &ssov1.ConfirmEmailChangeResponse{
+		Succeed: true,
+	}, nil
+}
@@ -0,0 +1,22 @@
+package emailchange
+
+import (
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"google.golang.org/grpc"
+	"log/slog"
+)
+
+type serverAPI struct {
+	ssov1.UnimplementedEmailChangeServer
+	log         *slog.Logger
+	emailChange services.EmailChange
+}
+
+// Register registers the EmailChange gRPC service implementation with the provided gRPC server.
+func Register(gRPC *grpc.Server, log *slog.Logger, emailChange services.EmailChange) {
+	ssov1.RegisterEmailChangeServer(gRPC, &serverAPI{
+		log:         log,
+		emailChange: emailChange,
+	})
+}
@@ -0,0 +1,41 @@
+package export
+
+import (
+	"errors"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log/slog"
+)
+
+// ExportMyData streams the data archive of the user making the request.
+// It delegates the export to the ExportMyData method of the ExportService.
+func (s *serverAPI) ExportMyData(
+	_ *ssov1.ExportMyDataRequest,
+	stream ssov1.Export_ExportMyDataServer) error {
+	const op = "export.grpc.ExportMyData"
+
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("trying to export user data")
+
+	err := s.export.ExportMyData(stream.Context(), func(chunk []byte) error {
+		return stream.Send(&ssov1.ExportChunk{Data: chunk})
+	})
+	if errors.Is(err, grpcerror.ErrUserNotFound) {
+		log.Info(grpcerror.ErrUserNotFound.Error())
+		return status.Error(codes.NotFound, grpcerror.ErrUserNotFound.Error())
+	}
+	if err != nil {
+		log.Error("failed to export user data", sl.Err(err))
+		return status.Error(codes.Internal, grpcerror.ErrInternalError.Error())
+	}
+
+	log.Info("user data successfully exported")
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package export
+
+import (
+	"errors"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log/slog"
+)
+
+// ExportUserData streams the data archive of the user with the provided user ID.
+// It delegates the export to the ExportUserData method of the ExportService.
+func (s *serverAPI) ExportUserData(
+	req *ssov1.ExportUserDataRequest,
+	stream ssov1.Export_ExportUserDataServer) error {
+	const op = "export.grpc.ExportUserData"
+
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("trying to export user data", slog.Int64("user_id", req.GetUserId()))
+
+	err := s.export.ExportUserData(stream.Context(), req.GetUserId(), func(chunk []byte) error {
+		return stream.Send(&ssov1.ExportChunk{Data: chunk})
+	})
+	if errors.Is(err, grpcerror.ErrUserNotFound) {
+		log.Info(grpcerror.ErrUserNotFound.Error())
+		return status.Error(codes.NotFound, grpcerror.ErrUserNotFound.Error())
+	}
+	if err != nil {
+		log.Error("failed to export user data", sl.Err(err),
+			slog.Int64("user_id", req.GetUserId()))
+		return status.Error(codes.Internal, grpcerror.ErrInternalError.Error())
+	}
+
+	log.Info("user data successfully exported", slog.Int64("user_id", req.GetUserId()))
+
+	return nil
+}
@@ -0,0 +1,22 @@
+package export
+
+import (
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"google.golang.org/grpc"
+	"log/slog"
+)
+
+type serverAPI struct {
+	ssov1.UnimplementedExportServer
+	log    *slog.Logger
+	export services.Export
+}
+
+// Register registers the Export gRPC service implementation with the provided gRPC server.
+func Register(gRPC *grpc.Server, log *slog.Logger, export services.Export) {
+	ssov1.RegisterExportServer(gRPC, &serverAPI{
+		log:    log,
+		export: export,
+	})
+}
@@ -0,0 +1,72 @@
+package impersonation
+
+import (
+	"context"
+	"errors"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log/slog"
+	"strings"
+)
+
+// Impersonate issues a token for the requested user to the admin making the request.
+// It delegates the operation to the Impersonate method of the ImpersonationService.
+func (s *serverAPI) Impersonate(
+	ctx context.Context,
+	req *ssov1.ImpersonateRequest) (
+	*ssov1.ImpersonateResponse, error) {
+	const op = "impersonation.grpc.Impersonate"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", req.GetUserId()),
+	)
+
+	log.Info("trying to impersonate user")
+
+	if req.GetUserId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if strings.TrimSpace(req.GetReason()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "reason is required")
+	}
+
+	token, err := s.impersonation.Impersonate(ctx, req.GetUserId(), req.GetReason())
+	if errors.Is(err, grpcerror.ErrUserNotFound) {
+		log.Info(grpcerror.ErrUserNotFound.Error())
+		return nil, status.Error(codes.NotFound, grpcerror.ErrUserNotFound.Error())
+	}
+	if errors.Is(err, grpcerror.ErrImpersonateAdmin) {
+		log.Info(grpcerror.ErrImpersonateAdmin.Error())
+		return nil, status.Error(codes.PermissionDenied, grpcerror.ErrImpersonateAdmin.Error())
+	}
+	if errors.Is(err, grpcerror.ErrImpersonationDenied) {
+		log.Info(grpcerror.ErrImpersonationDenied.Error())
+		return nil, status.Error(codes.PermissionDenied, grpcerror.ErrImpersonationDenied.Error())
+	}
+	if errors.Is(err, grpcerror.ErrForbidden) {
+		log.Info(grpcerror.ErrForbidden.Error())
+		return nil, status.Error(codes.PermissionDenied, grpcerror.ErrForbidden.Error())
+	}
+	if errors.Is(err, grpcerror.ErrUserSuspended) {
+		log.Info(grpcerror.ErrUserSuspended.Error())
+		return nil, status.Error(codes.FailedPrecondition, grpcerror.ErrUserSuspended.Error())
+	}
+	if errors.Is(err, grpcerror.ErrUserDisabled) {
+		log.Info(grpcerror.ErrUserDisabled.Error())
+		return nil, status.Error(codes.FailedPrecondition, grpcerror.ErrUserDisabled.Error())
+	}
+	if err != nil {
+		log.Error("failed to impersonate user", sl.Err(err))
+		return nil, status.Error(codes.Internal, grpcerror.ErrInternalError.Error())
+	}
+
+	log.Info("impersonation token successfully issued")
+
+	return &ssov1.ImpersonateResponse{
+		Token: token,
+	}, nil
+}
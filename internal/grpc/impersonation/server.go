@@ -0,0 +1,22 @@
+package impersonation
+
+import (
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"google.golang.org/grpc"
+	"log/slog"
+)
+
+type serverAPI struct {
+	ssov1.UnimplementedImpersonationServer
+	log           *slog.Logger
+	impersonation services.Impersonation
+}
+
+// Register registers the Impersonation gRPC service implementation with the provided gRPC server.
+func Register(gRPC *grpc.Server, log *slog.Logger, impersonation services.Impersonation) {
+	ssov1.RegisterImpersonationServer(gRPC, &serverAPI{
+		log:           log,
+		impersonation: impersonation,
+	})
+}
@@ -0,0 +1,73 @@
+package introspection
+
+import (
+	"context"
+	"errors"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"log/slog"
+)
+
+// Introspect reports whether the provided token is active to the service client making the request.
+// It delegates the operation to the Introspect method of the IntrospectionService.
+func (s *serverAPI) Introspect(
+	ctx context.Context,
+	req *ssov1.IntrospectRequest) (
+	*ssov1.IntrospectResponse, error) {
+	const op = "introspection.grpc.Introspect"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("client_id", req.GetClientId()),
+	)
+
+	log.Info("trying to introspect token")
+
+	if req.GetClientId() == "" || req.GetClientSecret() == "" {
+		return nil, status.Error(codes.Unauthenticated, grpcerror.ErrInvalidClient.Error())
+	}
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	result, err := s.introspection.Introspect(ctx, req.GetClientId(), req.GetClientSecret(), req.GetToken())
+	if errors.Is(err, grpcerror.ErrInvalidClient) {
+		log.Info(grpcerror.ErrInvalidClient.Error())
+		return nil, status.Error(codes.Unauthenticated, grpcerror.ErrInvalidClient.Error())
+	}
+	if err != nil {
+		log.Error("failed to introspect token", sl.Err(err))
+		return nil, status.Error(codes.Internal, grpcerror.ErrInternalError.Error())
+	}
+
+	var attrs *structpb.Struct
+	if len(result.Attributes) > 0 {
+		attrs, err = structpb.NewStruct(result.Attributes)
+		if err != nil {
+			log.Error("failed to convert token attributes", sl.Err(err))
+			return nil, status.Error(codes.Internal, grpcerror.ErrInternalError.Error())
+		}
+	}
+
+	log.Info("token successfully introspected", slog.Bool("active", result.Active))
+
+	return &ssov1.IntrospectResponse{
+		Active:    result.Active,
+		Iss:       result.Issuer,
+		Sub:       result.Subject,
+		Aud:       result.Audience,
+		Scope:     result.Scope,
+		Iat:       result.IssuedAt,
+		Exp:       result.ExpiresAt,
+		UserId:    result.UserID,
+		Email:     result.Email,
+		Role:      string(result.Role),
+		SessionId: result.SessionID,
+		ActorId:   result.ActorID,
+		Attrs:     attrs,
+	}, nil
+}
@@ -0,0 +1,22 @@
+package introspection
+
+import (
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"google.golang.org/grpc"
+	"log/slog"
+)
+
+type serverAPI struct {
+	ssov1.UnimplementedIntrospectionServer
+	log           *slog.Logger
+	introspection services.Introspection
+}
+
+// Register registers the Introspection gRPC service implementation with the provided gRPC server.
+func Register(gRPC *grpc.Server, log *slog.Logger, introspection services.Introspection) {
+	ssov1.RegisterIntrospectionServer(gRPC, &serverAPI{
+		log:           log,
+		introspection: introspection,
+	})
+}
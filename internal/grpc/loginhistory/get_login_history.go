@@ -0,0 +1,38 @@
+package loginhistory
+
+import (
+	"context"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log/slog"
+)
+
+// GetLoginHistory retrieves the most recent sign-in attempts of the user making the request.
+// It delegates the retrieval operation to the GetLoginHistory method of the LoginHistoryService.
+func (s *serverAPI) GetLoginHistory(
+	ctx context.Context,
+	req *ssov1.GetLoginHistoryRequest) (
+	*ssov1.GetLoginHistoryResponse, error) {
+	const op = "loginhistory.grpc.GetLoginHistory"
+
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("trying to get login history")
+
+	attempts, err := s.history.GetLoginHistory(ctx, req.GetLimit())
+	if err != nil {
+		log.Error("failed to get login history", sl.Err(err))
+		return nil, status.Error(codes.Internal, grpcerror.ErrInternalError.Error())
+	}
+
+	log.Info("login history successfully retrieved")
+
+	return &ssov1.GetLoginHistoryResponse{
+		Attempts: toLoginAttempts(attempts),
+	}, nil
+}
@@ -0,0 +1,40 @@
+package loginhistory
+
+import (
+	"context"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log/slog"
+)
+
+// GetUserLoginHistory retrieves the most recent sign-in attempts of the user with the
+// provided user ID. It delegates the retrieval operation to the GetUserLoginHistory
+// method of the LoginHistoryService.
+func (s *serverAPI) GetUserLoginHistory(
+	ctx context.Context,
+	req *ssov1.GetUserLoginHistoryRequest) (
+	*ssov1.GetUserLoginHistoryResponse, error) {
+	const op = "loginhistory.grpc.GetUserLoginHistory"
+
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("trying to get login history", slog.Int64("user_id", req.GetUserId()))
+
+	attempts, err := s.history.GetUserLoginHistory(ctx, req.GetUserId(), req.GetLimit())
+	if err != nil {
+		log.Error("failed to get login history", sl.Err(err),
+			slog.Int64("user_id", req.GetUserId()))
+		return nil, status.Error(codes.Internal, grpcerror.ErrInternalError.Error())
+	}
+
+	log.Info("login history successfully retrieved", slog.Int64("user_id", req.GetUserId()))
+
+	return &ssov1.GetUserLoginHistoryResponse{
+		Attempts: toLoginAttempts(attempts),
+	}, nil
+}
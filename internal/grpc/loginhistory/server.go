@@ -0,0 +1,42 @@
+package loginhistory
+
+import (
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"log/slog"
+)
+
+type serverAPI struct {
+	ssov1.UnimplementedLoginHistoryServer
+	log     *slog.Logger
+	history services.LoginHistory
+}
+
+// Register registers the LoginHistory gRPC service implementation with the provided gRPC server.
+func Register(gRPC *grpc.Server, log *slog.Logger, history services.LoginHistory) {
+	ssov1.RegisterLoginHistoryServer(gRPC, &serverAPI{
+		log:     log,
+		history: history,
+	})
+}
+
+func toLoginAttempts(attempts []models.LoginAttempt) []*ssov1.LoginAttempt {
+	converted := make([]*ssov1.LoginAttempt, 0, len(attempts))
+
+	for _, attempt := range attempts {
+		converted = append(converted, &ssov1.LoginAttempt{
+			Success:       attempt.Success,
+			FailureReason: attempt.FailureReason,
+			Ip:            attempt.IP,
+			UserAgent:     attempt.UserAgent,
+			MfaUsed:       attempt.MFAUsed,
+			SessionId:     attempt.SessionID,
+			CreatedAt:     timestamppb.New(attempt.CreatedAt.UTC()),
+		})
+	}
+
+	return converted
+}
@@ -0,0 +1,55 @@
+package phoneverification
+
+import (
+	"context"
+	"errors"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log/slog"
+)
+
+// SendPhoneCode sends a verification code to the phone number of the user making the request.
+// It delegates the operation to the SendPhoneCode method of the PhoneVerificationService.
+func (s *serverAPI) SendPhoneCode(
+	ctx context.Context,
+	_ *ssov1.SendPhoneCodeRequest) (
+	*ssov1.SendPhoneCodeResponse, error) {
+	const op = "phoneverification.grpc.SendPhoneCode"
+
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("trying to send phone verification code")
+
+	err := s.phones.SendPhoneCode(ctx)
+	if errors.Is(err, grpcerror.ErrInvalidPhone) {
+		log.Info("user has no phone number")
+		return nil, status.Error(codes.FailedPrecondition, grpcerror.ErrInvalidPhone.Error())
+	}
+	if errors.Is(err, grpcerror.ErrPhoneVerified) {
+		log.Info(grpcerror.ErrPhoneVerified.Error())
+		return nil, status.Error(codes.FailedPrecondition, grpcerror.ErrPhoneVerified.Error())
+	}
+	if errors.Is(err, grpcerror.ErrCodeRecentlySent) {
+		log.Info(grpcerror.ErrCodeRecentlySent.Error())
+		return nil, status.Error(codes.ResourceExhausted, grpcerror.ErrCodeRecentlySent.Error())
+	}
+	if errors.Is(err, grpcerror.ErrUserNotFound) {
+		log.Info(grpcerror.ErrUserNotFound.Error())
+		return nil, status.Error(codes.NotFound, grpcerror.ErrUserNotFound.Error())
+	}
+	if err != nil {
+		log.Error("failed to send phone verification code", sl.Err(err))
+		return nil, status.Error(codes.Internal, grpcerror.ErrInternalError.Error())
+	}
+
+	log.Info("phone verification code successfully sent")
+
+	return &ssov1.SendPhoneCodeResponse{
+		Succeed: true,
+	}, nil
+}
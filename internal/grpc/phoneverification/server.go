@@ -0,0 +1,22 @@
+package phoneverification
+
+import (
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"google.golang.org/grpc"
+	"log/slog"
+)
+
+type serverAPI struct {
+	ssov1.UnimplementedPhoneVerificationServer
+	log    *slog.Logger
+	phones services.PhoneVerification
+}
+
+// Register registers the PhoneVerification gRPC service implementation with the provided gRPC server.
+func Register(gRPC *grpc.Server, log *slog.Logger, phones services.PhoneVerification) {
+	ssov1.RegisterPhoneVerificationServer(gRPC, &serverAPI{
+		log:    log,
+		phones: phones,
+	})
+}
@@ -0,0 +1,56 @@
+package phoneverification
+
+import (
+	"context"
+	"errors"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log/slog"
+)
+
+// VerifyPhone marks the phone number of the user making the request as verified if
+// the provided code matches the one sent to it. It delegates the operation to the
+// VerifyPhone method of the PhoneVerificationService.
+func (s *serverAPI) VerifyPhone(
+	ctx context.Context,
+	req *ssov1.VerifyPhoneRequest) (
+	*ssov1.VerifyPhoneResponse, error) {
+	const op = "phoneverification.grpc.VerifyPhone"
+
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("trying to verify phone")
+
+	if req.GetCode() == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+
+	err := s.phones.VerifyPhone(ctx, req.GetCode())
+	if errors.Is(err, grpcerror.ErrInvalidCode) {
+		log.Info(grpcerror.ErrInvalidCode.Error())
+		return nil, status.Error(codes.InvalidArgument, grpcerror.ErrInvalidCode.Error())
+	}
+	if errors.Is(err, grpcerror.ErrVerificationNotFound) {
+		log.Info(grpcerror.ErrVerificationNotFound.Error())
+		return nil, status.Error(codes.NotFound, grpcerror.ErrVerificationNotFound.Error())
+	}
+	if errors.Is(err, grpcerror.ErrPhoneInUse) {
+		log.Info(grpcerror.ErrPhoneInUse.Error())
+		return nil, status.Error(codes.AlreadyExists, grpcerror.ErrPhoneInUse.Error())
+	}
+	if err != nil {
+		log.Error("failed to verify phone", sl.Err(err))
+		return nil, status.Error(codes.Internal, grpcerror.ErrInternalError.Error())
+	}
+
+	log.Info("phone successfully verified")
+
+	return &ssov1.VerifyPhoneResponse{
+		Succeed: true,
+	}, nil
+}
@@ -0,0 +1,52 @@
+package session
+
+import (
+	"context"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"log/slog"
+)
+
+// ListSessions retrieves the active sessions of the user making the request.
+// It delegates the retrieval operation to the ListSessions method of the SessionService.
+func (s *serverAPI) ListSessions(
+	ctx context.Context,
+	_ *ssov1.ListSessionsRequest) (
+	*ssov1.ListSessionsResponse, error) {
+	const op = "session.grpc.ListSessions"
+
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("trying to list sessions")
+
+	sessions, err := s.sessions.ListSessions(ctx)
+	if err != nil {
+		log.Error("failed to list sessions", sl.Err(err))
+		return nil, status.Error(codes.Internal, grpcerror.ErrInternalError.Error())
+	}
+
+	resp := &ssov1.ListSessionsResponse{
+		Sessions: make([]*ssov1.Session, 0, len(sessions)),
+	}
+
+	for _, session := range sessions {
+		resp.Sessions = append(resp.Sessions, &ssov1.Session{
+			SessionId:  session.ID,
+			UserAgent:  session.UserAgent,
+			Ip:         session.IP,
+			CreatedAt:  timestamppb.New(session.CreatedAt.UTC()),
+			LastSeenAt: timestamppb.New(session.LastSeenAt.UTC()),
+			ActorId:    session.ActorID,
+		})
+	}
+
+	log.Info("sessions successfully listed")
+
+	return resp, nil
+}
@@ -0,0 +1,38 @@
+package session
+
+import (
+	"context"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log/slog"
+)
+
+// RevokeAllOtherSessions revokes every session of the user making the request except for
+// the one the request was made with. It delegates the revocation to the
+// RevokeAllOtherSessions method of the SessionService.
+func (s *serverAPI) RevokeAllOtherSessions(
+	ctx context.Context,
+	_ *ssov1.RevokeAllOtherSessionsRequest) (
+	*ssov1.RevokeAllOtherSessionsResponse, error) {
+	const op = "session.grpc.RevokeAllOtherSessions"
+
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("trying to revoke all other sessions")
+
+	if err := s.sessions.RevokeAllOtherSessions(ctx); err != nil {
+		log.Error("failed to revoke sessions", sl.Err(err))
+		return nil, status.Error(codes.Internal, grpcerror.ErrInternalError.Error())
+	}
+
+	log.Info("all other sessions successfully revoked")
+
+	return &ssov1.RevokeAllOtherSessionsResponse{
+		Succeed: true,
+	}, nil
+}
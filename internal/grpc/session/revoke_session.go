@@ -0,0 +1,48 @@
+package session
+
+import (
+	"context"
+	"errors"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log/slog"
+)
+
+// RevokeSession revokes a session of the user making the request based on the provided
+// gRPC request containing the session ID. It delegates the revocation to the
+// RevokeSession method of the SessionService.
+func (s *serverAPI) RevokeSession(
+	ctx context.Context,
+	req *ssov1.RevokeSessionRequest) (
+	*ssov1.RevokeSessionResponse, error) {
+	const op = "session.grpc.RevokeSession"
+
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("trying to revoke session")
+
+	if req.GetSessionId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "session_id is required")
+	}
+
+	err := s.sessions.RevokeSession(ctx, req.GetSessionId())
+	if errors.Is(err, grpcerror.ErrSessionNotFound) {
+		log.Info(grpcerror.ErrSessionNotFound.Error())
+		return nil, status.Error(codes.NotFound, grpcerror.ErrSessionNotFound.Error())
+	}
+	if err != nil {
+		log.Error("failed to revoke session", sl.Err(err))
+		return nil, status.Error(codes.Internal, grpcerror.ErrInternalError.Error())
+	}
+
+	log.Info("session successfully revoked")
+
+	return &ssov1.RevokeSessionResponse{
+		Succeed: true,
+	}, nil
+}
@@ -0,0 +1,22 @@
+package session
+
+import (
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"google.golang.org/grpc"
+	"log/slog"
+)
+
+type serverAPI struct {
+	ssov1.UnimplementedSessionsServer
+	log      *slog.Logger
+	sessions services.Sessions
+}
+
+// Register registers the Sessions gRPC service implementation with the provided gRPC server.
+func Register(gRPC *grpc.Server, log *slog.Logger, sessions services.Sessions) {
+	ssov1.RegisterSessionsServer(gRPC, &serverAPI{
+		log:      log,
+		sessions: sessions,
+	})
+}
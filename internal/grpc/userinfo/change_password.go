@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/password"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
 	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
 	"google.golang.org/grpc/codes"
@@ -30,12 +31,12 @@ func (s *serverAPI) ChangePassword(
 		return nil, status.Error(codes.InvalidArgument, "new password is required")
 	}
 
-	if len(req.GetNewPassword()) >= 72 {
-		log.Info("password is too long")
-		return nil, status.Error(codes.InvalidArgument, "password is too long")
-	}
-
 	err := s.userInfo.ChangePassword(ctx, req.OldPassword, req.GetNewPassword())
+	var policyErr *password.PolicyError
+	if errors.As(err, &policyErr) {
+		log.Info("new password rejected by policy", sl.Err(err))
+		return nil, policyErr.GRPCStatus().Err()
+	}
 	if errors.Is(err, grpcerror.ErrUserNotFound) {
 		log.Info(grpcerror.ErrUserNotFound.Error())
 		return nil, status.Error(codes.InvalidArgument, grpcerror.ErrUserNotFound.Error())
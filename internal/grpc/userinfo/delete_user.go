@@ -11,8 +11,10 @@ import (
 	"log/slog"
 )
 
-// DeleteUser deletes a user based on the provided gRPC request containing the user ID.
-// It delegates the deletion operation to the DeleteUser method of the UserInfoService.
+// DeleteUser soft-deletes a user based on the provided gRPC request containing the
+// user ID. The user is removed from its families and its invites are deleted first,
+// while the family service can still look the user up. The user can be restored
+// within the grace period, but its family memberships are not.
 func (s *serverAPI) DeleteUser(
 	ctx context.Context,
 	req *ssov1.DeleteUserRequest) (
@@ -36,9 +38,6 @@ func (s *serverAPI) DeleteUser(
 		return nil, status.Error(codes.Internal, grpcerror.ErrInternalError.Error())
 	}
 
-	log.Info("user info successfully retrieved",
-		slog.Any("user", user))
-
 	err = s.family.DeleteUserFromFamilies(ctx, req.GetUserId(), user.FamilyIDs)
 	if err != nil {
 		log.Error("failed to delete user from family", sl.Err(err))
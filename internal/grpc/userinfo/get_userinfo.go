@@ -45,5 +45,6 @@ func (s *serverAPI) GetUserInfo(
 		Name:         user.Name,
 		Surname:      user.Surname,
 		RegisteredAt: timestamppb.New(user.RegisteredAt.UTC()),
+		Version:      user.Version,
 	}, nil
 }
@@ -0,0 +1,53 @@
+package userinfo
+
+import (
+	"context"
+	"errors"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log/slog"
+)
+
+// RestoreUser restores a soft-deleted user based on the provided gRPC request
+// containing the user ID. The user can only be restored within the grace period
+// after the deletion, and its family memberships are not restored.
+func (s *serverAPI) RestoreUser(
+	ctx context.Context,
+	req *ssov1.RestoreUserRequest) (
+	*ssov1.RestoreUserResponse, error) {
+	const op = "userinfo.grpc.RestoreUser"
+
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("trying to restore user", slog.Int64("user_id", req.GetUserId()))
+
+	err := s.userInfo.RestoreUser(ctx, req.GetUserId())
+	if errors.Is(err, grpcerror.ErrUserNotFound) {
+		log.Info(grpcerror.ErrUserNotFound.Error())
+		return nil, status.Error(codes.NotFound, grpcerror.ErrUserNotFound.Error())
+	}
+	if errors.Is(err, grpcerror.ErrUserNotDeleted) {
+		log.Info(grpcerror.ErrUserNotDeleted.Error())
+		return nil, status.Error(codes.FailedPrecondition, grpcerror.ErrUserNotDeleted.Error())
+	}
+	if errors.Is(err, grpcerror.ErrRestoreExpired) {
+		log.Info(grpcerror.ErrRestoreExpired.Error())
+		return nil, status.Error(codes.FailedPrecondition, grpcerror.ErrRestoreExpired.Error())
+	}
+	if err != nil {
+		log.Error("failed to restore user", sl.Err(err),
+			slog.Int64("user_id", req.GetUserId()))
+		return nil, status.Error(codes.Internal, grpcerror.ErrInternalError.Error())
+	}
+
+	log.Info("user successfully restored", slog.Int64("user_id", req.GetUserId()))
+
+	return &ssov1.RestoreUserResponse{
+		Succeed: true,
+	}, nil
+}
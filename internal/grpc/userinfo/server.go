@@ -9,9 +9,10 @@ import (
 
 type serverAPI struct {
 	ssov1.UnimplementedUserInfoServer
-	log      *slog.Logger
-	userInfo services.UserInfo
-	family   services.Family
+	log         *slog.Logger
+	userInfo    services.UserInfo
+	family      services.Family
+	emailChange services.EmailChange
 }
 
 // Register registers the UserInfo gRPC service implementation with the provided gRPC server.
@@ -19,10 +20,12 @@ func Register(
 	gRPC *grpc.Server,
 	log *slog.Logger,
 	userInfo services.UserInfo,
-	family services.Family) {
+	family services.Family,
+	emailChange services.EmailChange) {
 	ssov1.RegisterUserInfoServer(gRPC, &serverAPI{
-		log:      log,
-		userInfo: userInfo,
-		family:   family,
+		log:         log,
+		userInfo:    userInfo,
+		family:      family,
+		emailChange: emailChange,
 	})
 }
@@ -0,0 +1,71 @@
+package userinfo
+
+import (
+	"context"
+	"errors"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log/slog"
+	"time"
+)
+
+// accountStatuses maps the account statuses of the API to the statuses of the model.
+var accountStatuses = map[ssov1.AccountStatus]models.AccountStatus{ //nolint:gochecknoglobals
+	ssov1.AccountStatus_ACCOUNT_STATUS_ACTIVE:    models.StatusActive,
+	ssov1.AccountStatus_ACCOUNT_STATUS_SUSPENDED: models.StatusSuspended,
+	ssov1.AccountStatus_ACCOUNT_STATUS_DISABLED:  models.StatusDisabled,
+}
+
+// SetUserStatus changes the account status of a user based on the provided gRPC
+// request. Suspended and disabled users cannot sign in, and their access tokens
+// are revoked.
+func (s *serverAPI) SetUserStatus(
+	ctx context.Context,
+	req *ssov1.SetUserStatusRequest) (
+	*ssov1.SetUserStatusResponse, error) {
+	const op = "userinfo.grpc.SetUserStatus"
+
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("trying to set user status",
+		slog.Int64("user_id", req.GetUserId()), slog.String("status", req.GetStatus().String()))
+
+	accountStatus, ok := accountStatuses[req.GetStatus()]
+	if !ok {
+		log.Info(grpcerror.ErrInvalidStatus.Error())
+		return nil, status.Error(codes.InvalidArgument, grpcerror.ErrInvalidStatus.Error())
+	}
+
+	var until *time.Time
+	if req.GetUntil() != nil {
+		t := req.GetUntil().AsTime()
+		until = &t
+	}
+
+	err := s.userInfo.SetUserStatus(ctx, req.GetUserId(), accountStatus, req.GetReason(), until)
+	if errors.Is(err, grpcerror.ErrInvalidStatus) {
+		log.Info(err.Error())
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, grpcerror.ErrUserNotFound) {
+		log.Info(grpcerror.ErrUserNotFound.Error())
+		return nil, status.Error(codes.NotFound, grpcerror.ErrUserNotFound.Error())
+	}
+	if err != nil {
+		log.Error("failed to set user status", sl.Err(err),
+			slog.Int64("user_id", req.GetUserId()))
+		return nil, status.Error(codes.Internal, grpcerror.ErrInternalError.Error())
+	}
+
+	log.Info("user status successfully set", slog.Int64("user_id", req.GetUserId()))
+
+	return &ssov1.SetUserStatusResponse{
+		Succeed: true,
+	}, nil
+}
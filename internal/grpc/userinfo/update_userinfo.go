@@ -11,11 +11,23 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"log/slog"
+	"strings"
 )
 
+// maskFields maps the update mask paths of the request to the profile fields.
+var maskFields = map[string]string{ //nolint:gochecknoglobals
+	"new_email":        models.FieldEmail,
+	"new_phone_number": models.FieldPhoneNumber,
+	"new_name":         models.FieldName,
+	"new_surname":      models.FieldSurname,
+}
+
 // UpdateUserInfo updates user information based on the provided gRPC request.
-// It validates the new email format and delegates
-// the update operation to the UpdateUserInfo method of the UserInfoService.
+// It delegates the update operation to the UpdateUserInfo method of the
+// UserInfoService. A new email is not applied right away: a change is requested
+// from the EmailChange service and applied once the user confirms it.
+// Without an update mask only non-empty fields are updated. With an expected
+// version the update fails with Aborted if the user was modified concurrently.
 func (s *serverAPI) UpdateUserInfo(
 	ctx context.Context,
 	req *ssov1.UpdateUserInfoRequest) (
@@ -29,18 +41,59 @@ func (s *serverAPI) UpdateUserInfo(
 
 	log.Info("updating user info")
 
-	if err := checkmail.ValidateFormat(req.GetNewEmail()); req.GetNewEmail() != "" && err != nil {
+	newEmail := strings.TrimSpace(req.GetNewEmail())
+	changeEmail := newEmail != ""
+
+	var fields []string
+	if req.GetUpdateMask() != nil {
+		fields = make([]string, 0, len(req.GetUpdateMask().GetPaths()))
+		changeEmail = false
+		for _, path := range req.GetUpdateMask().GetPaths() {
+			field, ok := maskFields[path]
+			if !ok {
+				log.Info(grpcerror.ErrInvalidFieldMask.Error(), slog.String("path", path))
+				return nil, status.Errorf(codes.InvalidArgument, "%s: unknown path %s",
+					grpcerror.ErrInvalidFieldMask.Error(), path)
+			}
+			if field == models.FieldEmail {
+				changeEmail = true
+				continue
+			}
+			fields = append(fields, field)
+		}
+	}
+
+	if err := checkmail.ValidateFormat(newEmail); changeEmail && err != nil {
 		return nil, status.Error(codes.InvalidArgument, "invalid email was provided")
 	}
 
+	var expectedVersion *int64
+	if req.GetExpectedVersion() != nil {
+		version := req.GetExpectedVersion().GetValue()
+		expectedVersion = &version
+	}
+
 	updateInfo := &models.User{
-		Email:       req.GetNewEmail(),
 		PhoneNumber: req.GetNewPhoneNumber(),
 		Name:        req.GetNewName(),
 		Surname:     req.GetNewSurname(),
 	}
 
-	err := s.userInfo.UpdateUserInfo(ctx, updateInfo)
+	err := s.userInfo.UpdateUserInfo(ctx, updateInfo, fields, expectedVersion)
+	if errors.Is(err, grpcerror.ErrVersionConflict) {
+		log.Info(grpcerror.ErrVersionConflict.Error())
+		return nil, status.Error(codes.Aborted, grpcerror.ErrVersionConflict.Error())
+	}
+	if errors.Is(err, grpcerror.ErrInvalidEmail) ||
+		errors.Is(err, grpcerror.ErrInvalidPhone) ||
+		errors.Is(err, grpcerror.ErrInvalidFieldMask) {
+		log.Info("invalid update", sl.Err(err))
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, grpcerror.ErrUserExists) {
+		log.Info(grpcerror.ErrUserExists.Error())
+		return nil, status.Error(codes.InvalidArgument, grpcerror.ErrUserExists.Error())
+	}
 	if errors.Is(err, grpcerror.ErrUserNotFound) {
 		log.Info(grpcerror.ErrUserNotFound.Error())
 		return nil, status.Error(codes.InvalidArgument, grpcerror.ErrUserNotFound.Error())
@@ -50,6 +103,19 @@ func (s *serverAPI) UpdateUserInfo(
 		return nil, status.Error(codes.Internal, grpcerror.ErrInternalError.Error())
 	}
 
+	if changeEmail {
+		err = s.emailChange.RequestEmailChange(ctx, newEmail)
+		if errors.Is(err, grpcerror.ErrInvalidEmail) {
+			log.Info(grpcerror.ErrInvalidEmail.Error())
+			return nil, status.Error(codes.InvalidArgument, grpcerror.ErrInvalidEmail.Error())
+		}
+		if err != nil {
+			log.Error("failed to request email change", sl.Err(err))
+			return nil, status.Error(codes.Internal, grpcerror.ErrInternalError.Error())
+		}
+		log.Info("email change requested")
+	}
+
 	log.Info("info successfully updated")
 
 	return &ssov1.UpdateUserInfoResponse{
@@ -0,0 +1,270 @@
+package attributes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"math"
+	"regexp"
+	"sort"
+	"unicode/utf8"
+)
+
+// ClaimName is the name of the token claim holding the attributes included in tokens.
+const ClaimName = "attrs"
+
+// ownerRole is the access role of the owner of the attribute.
+const ownerRole = "user"
+
+var (
+	ErrInvalidSchema = errors.New("invalid attribute schema")
+
+	namePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+)
+
+// Schema describes a custom user attribute.
+type Schema struct {
+	Namespace string
+	Name      string
+	Type      models.AttributeType
+	MaxLength int
+	Claim     bool
+
+	read  map[string]bool
+	write map[string]bool
+}
+
+// Registry holds the schemas of the custom user attributes.
+type Registry struct {
+	schemas map[string]Schema
+}
+
+// NewRegistry creates and returns a new instance of the Registry with the provided
+// schemas. It fails if a schema is invalid or described twice.
+func NewRegistry(schemas []config.AttributeSchema) (*Registry, error) {
+	r := &Registry{schemas: make(map[string]Schema, len(schemas))}
+
+	for _, cfg := range schemas {
+		schema, err := newSchema(cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		key := schema.key()
+		if _, ok := r.schemas[key]; ok {
+			return nil, fmt.Errorf("%w: %s is described twice", ErrInvalidSchema, key)
+		}
+
+		r.schemas[key] = schema
+	}
+
+	return r, nil
+}
+
+func newSchema(cfg config.AttributeSchema) (Schema, error) {
+	if !namePattern.MatchString(cfg.Namespace) || !namePattern.MatchString(cfg.Name) {
+		return Schema{}, fmt.Errorf("%w: invalid name %s.%s", ErrInvalidSchema, cfg.Namespace, cfg.Name)
+	}
+
+	schema := Schema{
+		Namespace: cfg.Namespace,
+		Name:      cfg.Name,
+		Type:      models.AttributeType(cfg.Type),
+		MaxLength: cfg.MaxLength,
+		Claim:     cfg.Claim,
+	}
+
+	switch schema.Type {
+	case models.AttributeString, models.AttributeInt, models.AttributeFloat, models.AttributeBool:
+	default:
+		return Schema{}, fmt.Errorf("%w: %s has unknown type %q", ErrInvalidSchema, schema.key(), cfg.Type)
+	}
+
+	var err error
+	if schema.read, err = roles(schema.key(), cfg.Read); err != nil {
+		return Schema{}, err
+	}
+	if schema.write, err = roles(schema.key(), cfg.Write); err != nil {
+		return Schema{}, err
+	}
+
+	return schema, nil
+}
+
+func roles(key string, names []string) (map[string]bool, error) {
+	set := make(map[string]bool, len(names))
+
+	for _, name := range names {
+		if name != ownerRole && name != string(models.AdminRole) {
+			return nil, fmt.Errorf("%w: %s has unknown role %q", ErrInvalidSchema, key, name)
+		}
+		set[name] = true
+	}
+
+	return set, nil
+}
+
+func (s Schema) key() string {
+	return s.Namespace + "." + s.Name
+}
+
+// CanRead reports whether the caller with the provided role may read the attribute.
+// own tells whether the caller is the owner of the attribute.
+func (s Schema) CanRead(own bool, role models.Role) bool {
+	return allowed(s.read, own, role)
+}
+
+// CanWrite reports whether the caller with the provided role may write the
+// attribute. own tells whether the caller is the owner of the attribute.
+func (s Schema) CanWrite(own bool, role models.Role) bool {
+	return allowed(s.write, own, role)
+}
+
+func allowed(roles map[string]bool, own bool, role models.Role) bool {
+	return (own && roles[ownerRole]) || (role == models.AdminRole && roles[string(models.AdminRole)])
+}
+
+// Coerce checks that the value matches the type and length of the attribute and
+// returns it in the form it is stored in. Numbers decoded from JSON are accepted
+// for numeric attributes.
+func (s Schema) Coerce(value interface{}) (interface{}, error) {
+	switch s.Type {
+	case models.AttributeString:
+		v, ok := value.(string)
+		if !ok {
+			break
+		}
+		if s.MaxLength > 0 && utf8.RuneCountInString(v) > s.MaxLength {
+			return nil, fmt.Errorf("%w: %s is longer than %d characters",
+				grpcerror.ErrInvalidAttribute, s.key(), s.MaxLength)
+		}
+		return v, nil
+	case models.AttributeInt:
+		if v, ok := toInt(value); ok {
+			return v, nil
+		}
+	case models.AttributeFloat:
+		if v, ok := toFloat(value); ok {
+			return v, nil
+		}
+	case models.AttributeBool:
+		if v, ok := value.(bool); ok {
+			return v, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s must be of type %s", grpcerror.ErrInvalidAttribute, s.key(), s.Type)
+}
+
+func toInt(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case float64:
+		if v != math.Trunc(v) || math.Abs(v) > math.MaxInt64 {
+			return 0, false
+		}
+		return int64(v), true
+	case json.Number:
+		n, err := v.Int64()
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// normalize returns the stored value in the form it is stored in. Users read from
+// the remote cache are decoded from JSON, so that int attributes come back as
+// float64. Values that do not match the schema are returned as they are.
+func (s Schema) normalize(value interface{}) interface{} {
+	v, err := s.Coerce(value)
+	if err != nil {
+		return value
+	}
+	return v
+}
+
+// Schema returns the schema of the attribute with the provided namespace and name.
+func (r *Registry) Schema(namespace, name string) (Schema, bool) {
+	schema, ok := r.schemas[namespace+"."+name]
+	return schema, ok
+}
+
+// Readable returns the attributes of the user the caller with the provided role may
+// read, sorted by namespace and name. Stored values without a schema are skipped.
+func (r *Registry) Readable(user *models.User, own bool, role models.Role) []models.Attribute {
+	var attributes []models.Attribute
+
+	for namespace, values := range user.Attributes {
+		for name, value := range values {
+			schema, ok := r.Schema(namespace, name)
+			if !ok || !schema.CanRead(own, role) {
+				continue
+			}
+
+			attributes = append(attributes, models.Attribute{
+				Namespace: namespace,
+				Name:      name,
+				Value:     schema.normalize(value),
+			})
+		}
+	}
+
+	sort.Slice(attributes, func(i, j int) bool {
+		if attributes[i].Namespace != attributes[j].Namespace {
+			return attributes[i].Namespace < attributes[j].Namespace
+		}
+		return attributes[i].Name < attributes[j].Name
+	})
+
+	return attributes
+}
+
+// Claims returns the attributes of the user that are included in tokens, keyed by
+// "namespace.name". It returns nil if there are none.
+func (r *Registry) Claims(user *models.User) map[string]interface{} {
+	var claims map[string]interface{}
+
+	for namespace, values := range user.Attributes {
+		for name, value := range values {
+			schema, ok := r.Schema(namespace, name)
+			if !ok || !schema.Claim {
+				continue
+			}
+
+			if claims == nil {
+				claims = make(map[string]interface{})
+			}
+			claims[schema.key()] = schema.normalize(value)
+		}
+	}
+
+	return claims
+}
@@ -0,0 +1,119 @@
+package attributes
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func testSchemas() []config.AttributeSchema {
+	return []config.AttributeSchema{
+		{Namespace: "profile", Name: "locale", Type: "string", MaxLength: 5,
+			Read: []string{"user", "admin"}, Write: []string{"user"}, Claim: true},
+		{Namespace: "billing", Name: "tier", Type: "int",
+			Read: []string{"user", "admin"}, Write: []string{"admin"}},
+		{Namespace: "internal", Name: "score", Type: "float",
+			Read: []string{"admin"}, Write: []string{"admin"}},
+	}
+}
+
+func TestNewRegistry_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema config.AttributeSchema
+	}{
+		{"bad namespace", config.AttributeSchema{Namespace: "Profile", Name: "locale", Type: "string"}},
+		{"bad name", config.AttributeSchema{Namespace: "profile", Name: "time-zone", Type: "string"}},
+		{"unknown type", config.AttributeSchema{Namespace: "profile", Name: "locale", Type: "date"}},
+		{"unknown role", config.AttributeSchema{Namespace: "profile", Name: "locale", Type: "string",
+			Read: []string{"guest"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRegistry([]config.AttributeSchema{tt.schema})
+			assert.True(t, errors.Is(err, ErrInvalidSchema))
+		})
+	}
+
+	_, err := NewRegistry(append(testSchemas(), testSchemas()[0]))
+	assert.True(t, errors.Is(err, ErrInvalidSchema))
+}
+
+func TestSchema_Access(t *testing.T) {
+	r, err := NewRegistry(testSchemas())
+	require.NoError(t, err)
+
+	tier, ok := r.Schema("billing", "tier")
+	require.True(t, ok)
+
+	assert.True(t, tier.CanRead(true, models.UserRole))
+	assert.False(t, tier.CanRead(false, models.UserRole))
+	assert.True(t, tier.CanRead(false, models.AdminRole))
+	assert.False(t, tier.CanWrite(true, models.UserRole))
+	assert.True(t, tier.CanWrite(false, models.AdminRole))
+
+	_, ok = r.Schema("billing", "plan")
+	assert.False(t, ok)
+}
+
+func TestSchema_Coerce(t *testing.T) {
+	r, err := NewRegistry(testSchemas())
+	require.NoError(t, err)
+
+	locale, _ := r.Schema("profile", "locale")
+	tier, _ := r.Schema("billing", "tier")
+	score, _ := r.Schema("internal", "score")
+
+	v, err := locale.Coerce("be-BY")
+	require.NoError(t, err)
+	assert.Equal(t, "be-BY", v)
+
+	_, err = locale.Coerce("be-BY-x")
+	assert.True(t, errors.Is(err, grpcerror.ErrInvalidAttribute))
+
+	_, err = locale.Coerce(5)
+	assert.True(t, errors.Is(err, grpcerror.ErrInvalidAttribute))
+
+	v, err = tier.Coerce(float64(3))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), v)
+
+	v, err = tier.Coerce(json.Number("7"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), v)
+
+	_, err = tier.Coerce(3.5)
+	assert.True(t, errors.Is(err, grpcerror.ErrInvalidAttribute))
+
+	v, err = score.Coerce(2)
+	require.NoError(t, err)
+	assert.Equal(t, float64(2), v)
+}
+
+func TestRegistry_ReadableAndClaims(t *testing.T) {
+	r, err := NewRegistry(testSchemas())
+	require.NoError(t, err)
+
+	user := &models.User{Attributes: models.Attributes{
+		"profile":  {"locale": "en-US", "removed": "x"},
+		"billing":  {"tier": float64(2)},
+		"internal": {"score": 0.5},
+	}}
+
+	assert.Equal(t, []models.Attribute{
+		{Namespace: "billing", Name: "tier", Value: int64(2)},
+		{Namespace: "profile", Name: "locale", Value: "en-US"},
+	}, r.Readable(user, true, models.UserRole))
+
+	assert.Len(t, r.Readable(user, false, models.AdminRole), 3)
+	assert.Empty(t, r.Readable(user, false, models.UserRole))
+
+	assert.Equal(t, map[string]interface{}{"profile.locale": "en-US"}, r.Claims(user))
+	assert.Nil(t, r.Claims(&models.User{}))
+}
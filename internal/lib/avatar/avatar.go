@@ -0,0 +1,168 @@
+package avatar
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // registers the GIF decoder
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+const (
+	ContentTypeJPEG = "image/jpeg"
+	ContentTypePNG  = "image/png"
+	ContentTypeGIF  = "image/gif"
+
+	jpegQuality = 90
+)
+
+// Processor turns uploaded images into avatars of the configured sizes.
+type Processor struct {
+	cfg *config.AvatarConfig
+}
+
+// NewProcessor creates and returns a new instance of the Processor with the
+// provided avatar settings.
+func NewProcessor(cfg *config.AvatarConfig) *Processor {
+	return &Processor{cfg: cfg}
+}
+
+// MaxSize returns the maximum size of an uploaded image in bytes.
+func (p *Processor) MaxSize() int64 {
+	return p.cfg.MaxSize
+}
+
+// Sizes returns the sizes of the avatars in pixels.
+func (p *Processor) Sizes() []int {
+	return p.cfg.Sizes
+}
+
+// Process sniffs the content type of the uploaded image, crops the image to a
+// centered square and resizes it to each of the configured sizes. JPEG images are
+// stored as JPEG, PNG and GIF images as PNG to keep transparency. It returns the
+// content type of the avatars and the encoded avatars by size.
+func (p *Processor) Process(data []byte) (string, map[int][]byte, error) {
+	if int64(len(data)) > p.cfg.MaxSize {
+		return "", nil, grpcerror.ErrAvatarTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	switch contentType {
+	case ContentTypeJPEG, ContentTypePNG, ContentTypeGIF:
+	default:
+		return "", nil, fmt.Errorf("%w: %s", grpcerror.ErrUnsupportedImage, contentType)
+	}
+
+	// The dimensions are checked before decoding, so that a small file cannot make
+	// the server allocate a huge image.
+	imgCfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %v", grpcerror.ErrUnsupportedImage, err)
+	}
+	if imgCfg.Width <= 0 || imgCfg.Height <= 0 || imgCfg.Width*imgCfg.Height > p.cfg.MaxPixels {
+		return "", nil, grpcerror.ErrAvatarTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %v", grpcerror.ErrUnsupportedImage, err)
+	}
+
+	square := cropSquare(img)
+
+	outType := ContentTypePNG
+	if contentType == ContentTypeJPEG {
+		outType = ContentTypeJPEG
+	}
+
+	images := make(map[int][]byte, len(p.cfg.Sizes))
+	for _, size := range p.cfg.Sizes {
+		resized := resize(square, size)
+
+		var buf bytes.Buffer
+		if outType == ContentTypeJPEG {
+			err = jpeg.Encode(&buf, resized, &jpeg.Options{Quality: jpegQuality})
+		} else {
+			err = png.Encode(&buf, resized)
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to encode avatar: %w", err)
+		}
+
+		images[size] = buf.Bytes()
+	}
+
+	return outType, images, nil
+}
+
+// cropSquare returns the centered square part of the image as RGBA.
+func cropSquare(img image.Image) *image.RGBA {
+	b := img.Bounds()
+
+	side := b.Dx()
+	if b.Dy() < side {
+		side = b.Dy()
+	}
+
+	x0 := b.Min.X + (b.Dx()-side)/2
+	y0 := b.Min.Y + (b.Dy()-side)/2
+
+	square := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(square, square.Bounds(), img, image.Pt(x0, y0), draw.Src)
+
+	return square
+}
+
+// resize scales the square image to size x size pixels. Every target pixel is the
+// average of the source pixels it covers, which keeps downscaled avatars smooth.
+// Upscaling repeats the nearest source pixel.
+func resize(src *image.RGBA, size int) *image.RGBA {
+	side := src.Bounds().Dx()
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+
+	for y := 0; y < size; y++ {
+		sy0, sy1 := span(y, size, side)
+		for x := 0; x < size; x++ {
+			sx0, sx1 := span(x, size, side)
+
+			var r, g, b, a, n uint32
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					c := src.RGBAAt(sx, sy)
+					r += uint32(c.R)
+					g += uint32(c.G)
+					b += uint32(c.B)
+					a += uint32(c.A)
+					n++
+				}
+			}
+
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8(r / n),
+				G: uint8(g / n),
+				B: uint8(b / n),
+				A: uint8(a / n),
+			})
+		}
+	}
+
+	return dst
+}
+
+// span returns the range of source pixels covered by the target pixel i when
+// scaling from side to size pixels. The range is never empty.
+func span(i, size, side int) (int, int) {
+	start := i * side / size
+	end := (i + 1) * side / size
+	if end <= start {
+		end = start + 1
+	}
+
+	return start, end
+}
@@ -0,0 +1,103 @@
+package avatar
+
+import (
+	"bytes"
+	"errors"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func testProcessor() *Processor {
+	return NewProcessor(&config.AvatarConfig{
+		MaxSize:   1 << 20,
+		MaxPixels: 1000 * 1000,
+		Sizes:     []int{16, 64},
+	})
+}
+
+func encodedImage(t *testing.T, width, height int, encode func(*bytes.Buffer, image.Image) error) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetRGBA(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, encode(&buf, img))
+
+	return buf.Bytes()
+}
+
+func encodePNG(buf *bytes.Buffer, img image.Image) error {
+	return png.Encode(buf, img)
+}
+
+func encodeJPEG(buf *bytes.Buffer, img image.Image) error {
+	return jpeg.Encode(buf, img, nil)
+}
+
+func TestProcess(t *testing.T) {
+	tests := []struct {
+		name        string
+		encode      func(*bytes.Buffer, image.Image) error
+		contentType string
+	}{
+		{"png", encodePNG, ContentTypePNG},
+		{"jpeg", encodeJPEG, ContentTypeJPEG},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contentType, images, err := testProcessor().Process(encodedImage(t, 120, 40, tt.encode))
+			require.NoError(t, err)
+			assert.Equal(t, tt.contentType, contentType)
+			require.Len(t, images, 2)
+
+			for _, size := range []int{16, 64} {
+				cfg, format, err := image.DecodeConfig(bytes.NewReader(images[size]))
+				require.NoError(t, err)
+				assert.Equal(t, tt.name, format)
+				assert.Equal(t, size, cfg.Width)
+				assert.Equal(t, size, cfg.Height)
+			}
+		})
+	}
+}
+
+func TestProcess_Rejected(t *testing.T) {
+	p := testProcessor()
+
+	_, _, err := p.Process([]byte("definitely not an image"))
+	assert.True(t, errors.Is(err, grpcerror.ErrUnsupportedImage))
+
+	_, _, err = p.Process(make([]byte, p.MaxSize()+1))
+	assert.True(t, errors.Is(err, grpcerror.ErrAvatarTooLarge))
+
+	_, _, err = p.Process(encodedImage(t, 2000, 600, encodePNG))
+	assert.True(t, errors.Is(err, grpcerror.ErrAvatarTooLarge))
+
+	truncated := encodedImage(t, 32, 32, encodePNG)
+	_, _, err = p.Process(truncated[:len(truncated)/2])
+	assert.True(t, errors.Is(err, grpcerror.ErrUnsupportedImage))
+}
+
+func TestResize_Average(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	src.SetRGBA(0, 0, color.RGBA{A: 255})
+	src.SetRGBA(1, 0, color.RGBA{R: 200, A: 255})
+	src.SetRGBA(0, 1, color.RGBA{R: 200, A: 255})
+	src.SetRGBA(1, 1, color.RGBA{A: 255})
+
+	assert.Equal(t, color.RGBA{R: 100, A: 255}, resize(src, 1).RGBAAt(0, 0))
+	assert.Equal(t, color.RGBA{R: 200, A: 255}, resize(src, 4).RGBAAt(2, 0))
+}
@@ -0,0 +1,109 @@
+package clients
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+)
+
+// Registry holds the audiences tokens can be issued for along with their scopes:
+// the service itself and the services allowed to call the service-to-service RPCs.
+type Registry struct {
+	audience     string
+	scopes       map[string][]string
+	secretHashes map[string][]byte
+}
+
+// NewRegistry creates and returns a new instance of the Registry with the audience
+// of the service itself from the provided token settings and the provided clients.
+// It fails if a client has no ID, is described twice or takes the audience of the
+// service, or if its secret hash is not a hex-encoded SHA-256 hash.
+func NewRegistry(tokenCfg *config.TokenConfig, clients []config.ServiceClient) (*Registry, error) {
+	r := &Registry{
+		audience:     tokenCfg.Audience,
+		scopes:       map[string][]string{tokenCfg.Audience: tokenCfg.Scopes},
+		secretHashes: make(map[string][]byte, len(clients)),
+	}
+
+	for _, client := range clients {
+		if client.ID == "" {
+			return nil, fmt.Errorf("client without ID")
+		}
+		if _, ok := r.scopes[client.ID]; ok {
+			return nil, fmt.Errorf("client %s is described twice", client.ID)
+		}
+
+		hash, err := hex.DecodeString(client.SecretHash)
+		if err != nil || len(hash) != sha256.Size {
+			return nil, fmt.Errorf("client %s has an invalid secret hash", client.ID)
+		}
+
+		r.scopes[client.ID] = client.Scopes
+		r.secretHashes[client.ID] = hash
+	}
+
+	return r, nil
+}
+
+// Authenticate checks the provided client credentials. Unknown clients take as long
+// to reject as wrong secrets.
+func (r *Registry) Authenticate(clientID, secret string) error {
+	sum := sha256.Sum256([]byte(secret))
+
+	expected, ok := r.secretHashes[clientID]
+	if !ok {
+		expected = make([]byte, sha256.Size)
+	}
+
+	if subtle.ConstantTimeCompare(sum[:], expected) != 1 || !ok {
+		return grpcerror.ErrInvalidClient
+	}
+
+	return nil
+}
+
+// Resolve returns the grant a token is issued for when the provided grant is
+// requested. An empty audience stands for the service itself, and no scopes for
+// every scope of the audience. Unknown audiences and scopes the audience does not
+// have are rejected.
+func (r *Registry) Resolve(requested models.Grant) (models.Grant, error) {
+	audience := requested.Audience
+	if audience == "" {
+		audience = r.audience
+	}
+
+	allowed, ok := r.scopes[audience]
+	if !ok {
+		return models.Grant{}, fmt.Errorf("%w: %s", grpcerror.ErrUnknownAudience, audience)
+	}
+
+	if len(requested.Scopes) == 0 {
+		return models.Grant{Audience: audience, Scopes: allowed}, nil
+	}
+
+	scopes := make([]string, 0, len(requested.Scopes))
+	for _, scope := range requested.Scopes {
+		if !contains(allowed, scope) {
+			return models.Grant{}, fmt.Errorf("%w: %s", grpcerror.ErrInvalidScope, scope)
+		}
+		if !contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	return models.Grant{Audience: audience, Scopes: scopes}, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
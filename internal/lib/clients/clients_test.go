@@ -0,0 +1,80 @@
+package clients
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func secretHash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+var testTokenConfig = &config.TokenConfig{
+	Issuer:   "sso",
+	Audience: "sso",
+	Scopes:   []string{"profile:read", "profile:write"},
+}
+
+func TestAuthenticate(t *testing.T) {
+	r, err := NewRegistry(testTokenConfig, []config.ServiceClient{
+		{ID: "family", SecretHash: secretHash("family-secret")},
+	})
+	require.NoError(t, err)
+
+	assert.NoError(t, r.Authenticate("family", "family-secret"))
+	assert.True(t, errors.Is(r.Authenticate("family", "wrong"), grpcerror.ErrInvalidClient))
+	assert.True(t, errors.Is(r.Authenticate("unknown", "family-secret"), grpcerror.ErrInvalidClient))
+	assert.True(t, errors.Is(r.Authenticate("unknown", ""), grpcerror.ErrInvalidClient))
+}
+
+func TestNewRegistry_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		clients []config.ServiceClient
+	}{
+		{"no id", []config.ServiceClient{{SecretHash: secretHash("s")}}},
+		{"not hex", []config.ServiceClient{{ID: "a", SecretHash: "secret"}}},
+		{"short hash", []config.ServiceClient{{ID: "a", SecretHash: "abcd"}}},
+		{"duplicate", []config.ServiceClient{
+			{ID: "a", SecretHash: secretHash("s")},
+			{ID: "a", SecretHash: secretHash("t")},
+		}},
+		{"own audience", []config.ServiceClient{{ID: "sso", SecretHash: secretHash("s")}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRegistry(testTokenConfig, tt.clients)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	r, err := NewRegistry(testTokenConfig, []config.ServiceClient{
+		{ID: "family", SecretHash: secretHash("s"), Scopes: []string{"family:read", "family:write"}},
+	})
+	require.NoError(t, err)
+
+	grant, err := r.Resolve(models.Grant{})
+	require.NoError(t, err)
+	assert.Equal(t, models.Grant{Audience: "sso", Scopes: []string{"profile:read", "profile:write"}}, grant)
+
+	grant, err = r.Resolve(models.Grant{Audience: "family", Scopes: []string{"family:read", "family:read"}})
+	require.NoError(t, err)
+	assert.Equal(t, models.Grant{Audience: "family", Scopes: []string{"family:read"}}, grant)
+
+	_, err = r.Resolve(models.Grant{Audience: "billing"})
+	assert.True(t, errors.Is(err, grpcerror.ErrUnknownAudience))
+
+	_, err = r.Resolve(models.Grant{Audience: "family", Scopes: []string{"profile:read"}})
+	assert.True(t, errors.Is(err, grpcerror.ErrInvalidScope))
+}
@@ -0,0 +1,70 @@
+package email
+
+import (
+	"strings"
+)
+
+// plusAddressingDomains are mail providers that deliver "local+tag@domain" to "local@domain".
+var plusAddressingDomains = map[string]struct{}{ //nolint:gochecknoglobals
+	"gmail.com":    {},
+	"outlook.com":  {},
+	"hotmail.com":  {},
+	"live.com":     {},
+	"icloud.com":   {},
+	"me.com":       {},
+	"proton.me":    {},
+	"fastmail.com": {},
+}
+
+// Normalizer prepares email addresses for storage and for uniqueness checks.
+type Normalizer struct {
+	providerRules bool
+}
+
+// NewNormalizer creates and returns a new instance of the Normalizer. If providerRules
+// is set, provider-specific aliases (dots in Gmail addresses, plus-addressing tags)
+// are collapsed when computing the normalized address.
+func NewNormalizer(providerRules bool) *Normalizer {
+	return &Normalizer{providerRules: providerRules}
+}
+
+// Canonical returns the address in the form it is stored and shown to the user:
+// surrounding whitespace is trimmed and the domain is lowercased.
+func (n *Normalizer) Canonical(address string) string {
+	local, domain, ok := strings.Cut(strings.TrimSpace(address), "@")
+	if !ok {
+		return strings.TrimSpace(address)
+	}
+
+	return local + "@" + strings.ToLower(domain)
+}
+
+// Normalize returns the key used to look the address up and to enforce its uniqueness.
+// On top of Canonical it lowercases the local part and, if enabled, applies the
+// provider-specific rules.
+func (n *Normalizer) Normalize(address string) string {
+	normalized := strings.ToLower(n.Canonical(address))
+
+	if !n.providerRules {
+		return normalized
+	}
+
+	local, domain, ok := strings.Cut(normalized, "@")
+	if !ok {
+		return normalized
+	}
+
+	if domain == "googlemail.com" {
+		domain = "gmail.com"
+	}
+
+	if _, ok = plusAddressingDomains[domain]; ok {
+		local, _, _ = strings.Cut(local, "+")
+	}
+
+	if domain == "gmail.com" {
+		local = strings.ReplaceAll(local, ".", "")
+	}
+
+	return local + "@" + domain
+}
@@ -0,0 +1,31 @@
+package email
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Sender delivers email messages to users.
+type Sender interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// LogSender is a Sender that writes messages to the log instead of delivering them.
+// It is meant for local development and tests.
+type LogSender struct {
+	log *slog.Logger
+}
+
+// NewLogSender creates and returns a new instance of the LogSender.
+func NewLogSender(log *slog.Logger) *LogSender {
+	return &LogSender{log: log}
+}
+
+func (s *LogSender) Send(_ context.Context, to, subject, body string) error {
+	s.log.Info("email sent",
+		slog.String("to", to),
+		slog.String("subject", subject),
+		slog.String("body", body))
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package email
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSMTPServer accepts a single connection, answers the commands of a plain
+// SMTP session and records the envelope and the message data.
+type fakeSMTPServer struct {
+	listener net.Listener
+	from     string
+	rcpt     string
+	data     string
+	done     chan struct{}
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = l.Close()
+	})
+
+	srv := &fakeSMTPServer{listener: l, done: make(chan struct{})}
+	go srv.serve()
+
+	return srv
+}
+
+func (f *fakeSMTPServer) config() config.SMTPConfig {
+	host, port, _ := net.SplitHostPort(f.listener.Addr().String())
+	p, _ := strconv.Atoi(port)
+
+	return config.SMTPConfig{Host: host, Port: p, From: "sso@example.com", Timeout: time.Second}
+}
+
+func (f *fakeSMTPServer) serve() {
+	defer close(f.done)
+
+	conn, err := f.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) {
+		_, _ = conn.Write([]byte(line + "\r\n"))
+	}
+
+	reply("220 fake ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.TrimRight(line, "\r\n")
+
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 fake")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			f.from = strings.TrimPrefix(cmd, "MAIL FROM:")
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			f.rcpt = strings.TrimPrefix(cmd, "RCPT TO:")
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				line, err = r.ReadString('\n')
+				if err != nil || line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			f.data = data.String()
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func TestSMTPSender_Send(t *testing.T) {
+	srv := newFakeSMTPServer(t)
+
+	sender, err := NewSMTPSender(srv.config())
+	require.NoError(t, err)
+
+	err = sender.Send(context.Background(), "user@example.com", "Confirm your email", "line one\nline two")
+	require.NoError(t, err)
+	<-srv.done
+
+	assert.Equal(t, "<sso@example.com>", srv.from)
+	assert.Equal(t, "<user@example.com>", srv.rcpt)
+	assert.Contains(t, srv.data, "To: user@example.com\r\n")
+	assert.Contains(t, srv.data, "Subject: Confirm your email\r\n")
+	assert.True(t, strings.HasSuffix(srv.data, "\r\nline one\r\nline two\r\n"), srv.data)
+}
+
+func TestSMTPSender_HeaderInjection(t *testing.T) {
+	sender, err := NewSMTPSender(config.SMTPConfig{Host: "localhost", Port: 25, From: "sso@example.com"})
+	require.NoError(t, err)
+
+	err = sender.Send(context.Background(), "user@example.com\r\nBcc: other@example.com", "subject", "body")
+	assert.True(t, errors.Is(err, ErrInvalidHeader), err)
+
+	err = sender.Send(context.Background(), "user@example.com", "subject\nBcc: other@example.com", "body")
+	assert.True(t, errors.Is(err, ErrInvalidHeader), err)
+}
+
+func TestNewSMTPSender_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.SMTPConfig
+	}{
+		{"no host", config.SMTPConfig{Port: 25, From: "sso@example.com"}},
+		{"no sender", config.SMTPConfig{Host: "localhost", Port: 25}},
+		{"no port", config.SMTPConfig{Host: "localhost", From: "sso@example.com"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSMTPSender(tt.cfg)
+			assert.True(t, errors.Is(err, ErrInvalidSMTPConfig), err)
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package hasher
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrUnknownHashFormat = errors.New("unknown password hash format")
+	ErrInvalidHash       = errors.New("invalid password hash")
+	ErrInvalidParams     = errors.New("invalid argon2id parameters")
+)
+
+const (
+	minSaltLength = 8
+	minKeyLength  = 16
+)
+
+// PasswordHasher generates and verifies password hashes.
+type PasswordHasher interface {
+	// Hash returns the encoded hash of the provided password.
+	Hash(password string) (string, error)
+	// Verify reports whether the provided password matches the encoded hash.
+	Verify(encodedHash, password string) (bool, error)
+	// NeedsRehash reports whether the encoded hash was produced by a legacy
+	// algorithm or with parameters that differ from the current ones.
+	NeedsRehash(encodedHash string) bool
+}
+
+type params struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	saltLength  uint32
+	keyLength   uint32
+}
+
+// Argon2idHasher hashes passwords with argon2id and encodes the result in the PHC
+// string format. Legacy bcrypt hashes are still accepted by Verify.
+type Argon2idHasher struct {
+	params params
+}
+
+// New creates and returns a new instance of the Argon2idHasher with the provided
+// argon2id parameters. It returns ErrInvalidParams if argon2id cannot run with the
+// parameters or the salt or key would be too short.
+func New(cfg config.PasswordHashConfig) (*Argon2idHasher, error) {
+	p := params{
+		memory:      cfg.Memory,
+		iterations:  cfg.Iterations,
+		parallelism: cfg.Parallelism,
+		saltLength:  cfg.SaltLength,
+		keyLength:   cfg.KeyLength,
+	}
+
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	if p.saltLength < minSaltLength {
+		return nil, fmt.Errorf("%w: salt length must be at least %d", ErrInvalidParams, minSaltLength)
+	}
+	if p.keyLength < minKeyLength {
+		return nil, fmt.Errorf("%w: key length must be at least %d", ErrInvalidParams, minKeyLength)
+	}
+
+	return &Argon2idHasher{params: p}, nil
+}
+
+// validate checks the parameters argon2id requires: at least one iteration and
+// thread, and at least 8 KiB of memory per thread.
+func (p params) validate() error {
+	if p.iterations < 1 {
+		return fmt.Errorf("%w: iterations must be at least 1", ErrInvalidParams)
+	}
+	if p.parallelism < 1 {
+		return fmt.Errorf("%w: parallelism must be at least 1", ErrInvalidParams)
+	}
+	if p.memory < 8*uint32(p.parallelism) {
+		return fmt.Errorf("%w: memory must be at least 8 KiB per thread", ErrInvalidParams)
+	}
+
+	return nil
+}
+
+// Hash generates a random salt and returns the argon2id hash of the password
+// in the form $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<hash>.
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.params.saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	key := argon2.IDKey([]byte(password), salt,
+		h.params.iterations, h.params.memory, h.params.parallelism, h.params.keyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.params.memory, h.params.iterations, h.params.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Verify compares the password with the encoded hash. Both argon2id and legacy
+// bcrypt hashes are supported.
+func (h *Argon2idHasher) Verify(encodedHash, password string) (bool, error) {
+	if isBcrypt(encodedHash) {
+		err := bcrypt.CompareHashAndPassword([]byte(encodedHash), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to compare bcrypt hash: %w", err)
+		}
+		return true, nil
+	}
+
+	p, salt, key, err := decode(encodedHash)
+	if err != nil {
+		return false, err
+	}
+
+	otherKey := argon2.IDKey([]byte(password), salt, p.iterations, p.memory, p.parallelism, p.keyLength)
+
+	return subtle.ConstantTimeCompare(key, otherKey) == 1, nil
+}
+
+// NeedsRehash reports whether the encoded hash is a legacy bcrypt hash or an
+// argon2id hash produced with parameters other than the configured ones.
+func (h *Argon2idHasher) NeedsRehash(encodedHash string) bool {
+	if isBcrypt(encodedHash) {
+		return true
+	}
+
+	p, salt, _, err := decode(encodedHash)
+	if err != nil {
+		return true
+	}
+
+	return p.memory != h.params.memory ||
+		p.iterations != h.params.iterations ||
+		p.parallelism != h.params.parallelism ||
+		p.keyLength != h.params.keyLength ||
+		uint32(len(salt)) != h.params.saltLength
+}
+
+func isBcrypt(encodedHash string) bool {
+	return strings.HasPrefix(encodedHash, "$2a$") ||
+		strings.HasPrefix(encodedHash, "$2b$") ||
+		strings.HasPrefix(encodedHash, "$2y$")
+}
+
+// decode parses the PHC-formatted argon2id hash into its parameters, salt and key.
+func decode(encodedHash string) (params, []byte, []byte, error) {
+	var (
+		p       params
+		version int
+	)
+
+	parts := strings.Split(encodedHash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return params{}, nil, nil, ErrUnknownHashFormat
+	}
+
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params{}, nil, nil, ErrInvalidHash
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.iterations, &p.parallelism); err != nil {
+		return params{}, nil, nil, ErrInvalidHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params{}, nil, nil, ErrInvalidHash
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return params{}, nil, nil, ErrInvalidHash
+	}
+	p.keyLength = uint32(len(key))
+
+	if p.validate() != nil || p.keyLength == 0 {
+		return params{}, nil, nil, ErrInvalidHash
+	}
+
+	return p, salt, key, nil
+}
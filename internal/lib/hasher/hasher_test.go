@@ -0,0 +1,138 @@
+package hasher
+
+import (
+	"errors"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"testing"
+)
+
+var testConfig = config.PasswordHashConfig{
+	Memory:      8 * 1024,
+	Iterations:  1,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+func newTestHasher(t *testing.T, cfg config.PasswordHashConfig) *Argon2idHasher {
+	t.Helper()
+
+	h, err := New(cfg)
+	require.NoError(t, err)
+
+	return h
+}
+
+func TestNew_InvalidParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *config.PasswordHashConfig)
+	}{
+		{"zero memory", func(cfg *config.PasswordHashConfig) { cfg.Memory = 0 }},
+		{"memory below 8 KiB per thread", func(cfg *config.PasswordHashConfig) { cfg.Memory, cfg.Parallelism = 15, 2 }},
+		{"zero iterations", func(cfg *config.PasswordHashConfig) { cfg.Iterations = 0 }},
+		{"zero threads", func(cfg *config.PasswordHashConfig) { cfg.Parallelism = 0 }},
+		{"short salt", func(cfg *config.PasswordHashConfig) { cfg.SaltLength = 4 }},
+		{"short key", func(cfg *config.PasswordHashConfig) { cfg.KeyLength = 8 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig
+			tt.modify(&cfg)
+
+			_, err := New(cfg)
+			assert.True(t, errors.Is(err, ErrInvalidParams), err)
+		})
+	}
+}
+
+func TestHashAndVerify(t *testing.T) {
+	h := newTestHasher(t, testConfig)
+
+	hash, err := h.Hash("password")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=8192,t=1,p=1$"))
+
+	other, err := h.Hash("password")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, other, "every hash has its own salt")
+
+	ok, err := h.Verify(hash, "password")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = h.Verify(hash, "Password")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestVerify_Bcrypt(t *testing.T) {
+	h := newTestHasher(t, testConfig)
+
+	legacy, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	ok, err := h.Verify(string(legacy), "password")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = h.Verify(string(legacy), "other")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestVerify_InvalidHash(t *testing.T) {
+	h := newTestHasher(t, testConfig)
+
+	tests := []struct {
+		name string
+		hash string
+		err  error
+	}{
+		{"unknown format", "$scrypt$ln=15$salt$key", ErrUnknownHashFormat},
+		{"plain text", "password", ErrUnknownHashFormat},
+		{"other version", "$argon2id$v=16$m=8192,t=1,p=1$c2FsdHNhbHQ$a2V5a2V5a2V5a2V5", ErrInvalidHash},
+		{"zero threads", "$argon2id$v=19$m=8192,t=1,p=0$c2FsdHNhbHQ$a2V5a2V5a2V5a2V5", ErrInvalidHash},
+		{"zero iterations", "$argon2id$v=19$m=8192,t=0,p=1$c2FsdHNhbHQ$a2V5a2V5a2V5a2V5", ErrInvalidHash},
+		{"invalid salt", "$argon2id$v=19$m=8192,t=1,p=1$!!!$a2V5a2V5a2V5a2V5", ErrInvalidHash},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := h.Verify(tt.hash, "password")
+			assert.False(t, ok)
+			assert.True(t, errors.Is(err, tt.err), err)
+		})
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	h := newTestHasher(t, testConfig)
+
+	current, err := h.Hash("password")
+	require.NoError(t, err)
+
+	stronger := testConfig
+	stronger.Iterations = 2
+	old, err := newTestHasher(t, stronger).Hash("password")
+	require.NoError(t, err)
+
+	longerSalt := testConfig
+	longerSalt.SaltLength = 32
+	otherSalt, err := newTestHasher(t, longerSalt).Hash("password")
+	require.NoError(t, err)
+
+	legacy, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	assert.False(t, h.NeedsRehash(current))
+	assert.True(t, h.NeedsRehash(old), "other parameters")
+	assert.True(t, h.NeedsRehash(otherSalt), "other salt length")
+	assert.True(t, h.NeedsRehash(string(legacy)), "bcrypt")
+	assert.True(t, h.NeedsRehash("garbage"))
+}
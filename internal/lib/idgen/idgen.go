@@ -0,0 +1,133 @@
+package idgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	StrategySequence  = "sequence"
+	StrategySnowflake = "snowflake"
+	StrategyBlock     = "block"
+)
+
+const (
+	nodeBits     = 10
+	sequenceBits = 12
+	maxNodeID    = 1<<nodeBits - 1
+	maxSequence  = 1<<sequenceBits - 1
+)
+
+const (
+	// maxClockWait bounds the wait for the clock when the sequence is exhausted.
+	maxClockWait      = 100 * time.Millisecond
+	clockPollInterval = time.Millisecond / 10
+)
+
+var (
+	ErrInvalidNodeID    = errors.New("invalid snowflake node id")
+	ErrInvalidBlockSize = errors.New("invalid id block size")
+	ErrClockBehind      = errors.New("clock is behind the last issued snowflake id")
+)
+
+// Generator generates unique user IDs.
+type Generator interface {
+	NextID(ctx context.Context) (int64, error)
+}
+
+// epoch is the custom Snowflake epoch, 2024-01-01T00:00:00Z, in milliseconds.
+const epoch int64 = 1704067200000
+
+// Snowflake generates time-ordered IDs without a database round trip. An ID
+// consists of 41 bits of milliseconds since the custom epoch, 10 bits of node ID
+// and 12 bits of per-millisecond sequence, so every instance of the service must
+// be configured with a distinct node ID.
+type Snowflake struct {
+	mu       sync.Mutex
+	nodeID   int64
+	lastMs   int64
+	sequence int64
+	now      func() time.Time
+}
+
+// NewSnowflake creates and returns a new instance of the Snowflake generator for
+// the provided node ID, which must be within [0, 1023].
+func NewSnowflake(nodeID int64) (*Snowflake, error) {
+	if nodeID < 0 || nodeID > maxNodeID {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidNodeID, nodeID)
+	}
+
+	return &Snowflake{
+		nodeID: nodeID,
+		now:    time.Now,
+	}, nil
+}
+
+// NextID returns the next Snowflake ID. If the clock moved backwards, the IDs are
+// issued from the sequence of the last millisecond. If the sequence of the
+// millisecond is exhausted, it waits for the clock to pass the millisecond, for at
+// most maxClockWait, and returns ErrClockBehind if the clock does not catch up.
+func (s *Snowflake) NextID(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ms := s.now().UnixMilli() - epoch
+	if ms < s.lastMs {
+		ms = s.lastMs
+	}
+
+	var sequence int64
+	if ms == s.lastMs {
+		sequence = (s.sequence + 1) & maxSequence
+		if sequence == 0 {
+			var err error
+			if ms, err = s.waitAfter(ctx, s.lastMs); err != nil {
+				return -1, err
+			}
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return -1, err
+	}
+
+	s.lastMs, s.sequence = ms, sequence
+
+	return ms<<(nodeBits+sequenceBits) | s.nodeID<<sequenceBits | s.sequence, nil
+}
+
+// waitAfter waits until the clock passes the provided millisecond and returns the
+// new one. It gives up once the context is done or after maxClockWait.
+func (s *Snowflake) waitAfter(ctx context.Context, lastMs int64) (int64, error) {
+	ms := s.now().UnixMilli() - epoch
+	if ms > lastMs {
+		return ms, nil
+	}
+
+	if behind := time.Duration(lastMs-ms) * time.Millisecond; behind >= maxClockWait {
+		return -1, fmt.Errorf("%w: %s", ErrClockBehind, behind)
+	}
+
+	timeout := time.NewTimer(maxClockWait)
+	defer timeout.Stop()
+
+	ticker := time.NewTicker(clockPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return -1, ctx.Err()
+		case <-timeout.C:
+			return -1, fmt.Errorf("%w: waited %s", ErrClockBehind, maxClockWait)
+		case <-ticker.C:
+		}
+
+		if ms = s.now().UnixMilli() - epoch; ms > lastMs {
+			return ms, nil
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package idgen
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestSnowflake_ClockBackwards(t *testing.T) {
+	s, err := NewSnowflake(1)
+	require.NoError(t, err)
+
+	now := time.UnixMilli(epoch + 1000)
+	s.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	seen := make(map[int64]bool)
+
+	next := func() int64 {
+		id, err := s.NextID(ctx)
+		require.NoError(t, err)
+		require.False(t, seen[id], "duplicate id %d", id)
+		seen[id] = true
+		return id
+	}
+
+	first := next()
+	second := next()
+	assert.Greater(t, second, first)
+
+	now = now.Add(-5 * time.Millisecond)
+	for i := 0; i < 3; i++ {
+		id := next()
+		assert.Greater(t, id, second, "ids keep increasing while the clock is behind")
+		second = id
+	}
+
+	now = now.Add(10 * time.Millisecond)
+	assert.Greater(t, next(), second)
+}
+
+func TestSnowflake_SequenceExhausted(t *testing.T) {
+	s, err := NewSnowflake(0)
+	require.NoError(t, err)
+
+	start := time.UnixMilli(epoch + 1000)
+	calls := 0
+	s.now = func() time.Time {
+		calls++
+		if calls > maxSequence+2 {
+			return start.Add(time.Millisecond)
+		}
+		return start
+	}
+
+	ctx := context.Background()
+	seen := make(map[int64]bool, maxSequence+2)
+	for i := 0; i < maxSequence+2; i++ {
+		id, err := s.NextID(ctx)
+		require.NoError(t, err)
+		require.False(t, seen[id], "duplicate id %d", id)
+		seen[id] = true
+	}
+}
+
+func TestSnowflake_SequenceExhausted_ClockStuck(t *testing.T) {
+	s, err := NewSnowflake(0)
+	require.NoError(t, err)
+
+	start := time.UnixMilli(epoch + 1000)
+	s.now = func() time.Time { return start }
+
+	ctx := context.Background()
+	var last int64
+	for i := 0; i <= maxSequence; i++ {
+		last, err = s.NextID(ctx)
+		require.NoError(t, err)
+	}
+
+	began := time.Now()
+	_, err = s.NextID(ctx)
+	assert.ErrorIs(t, err, ErrClockBehind)
+	assert.Less(t, time.Since(began), 10*maxClockWait)
+
+	s.now = func() time.Time { return start.Add(time.Millisecond) }
+	id, err := s.NextID(ctx)
+	require.NoError(t, err)
+	assert.Greater(t, id, last, "a failed call does not reuse the sequence")
+}
+
+func TestSnowflake_SequenceExhausted_ClockFarBehind(t *testing.T) {
+	s, err := NewSnowflake(0)
+	require.NoError(t, err)
+
+	now := time.UnixMilli(epoch + 10000)
+	s.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	for i := 0; i <= maxSequence; i++ {
+		_, err = s.NextID(ctx)
+		require.NoError(t, err)
+	}
+
+	now = now.Add(-time.Second)
+
+	began := time.Now()
+	_, err = s.NextID(ctx)
+	assert.ErrorIs(t, err, ErrClockBehind)
+	assert.Less(t, time.Since(began), maxClockWait, "fails without waiting")
+}
+
+func TestSnowflake_SequenceExhausted_ContextCanceled(t *testing.T) {
+	s, err := NewSnowflake(0)
+	require.NoError(t, err)
+
+	start := time.UnixMilli(epoch + 1000)
+	s.now = func() time.Time { return start }
+
+	for i := 0; i <= maxSequence; i++ {
+		_, err = s.NextID(context.Background())
+		require.NoError(t, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), maxClockWait/10)
+	defer cancel()
+
+	_, err = s.NextID(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -2,7 +2,9 @@ package jwt
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -12,31 +14,85 @@ import (
 	"google.golang.org/grpc/metadata"
 )
 
+type claimsKey struct{}
+
 type Manager struct {
 	signingKey []byte
 	tokenTTL   time.Duration
+	issuer     string
 }
 
 // New creates and returns a new instance of the Manager with the provided
-// signing key and tokenTTL.
-func New(signingKey []byte, tokenTTL time.Duration) *Manager {
+// signing key, tokenTTL and issuer.
+func New(signingKey []byte, tokenTTL time.Duration, issuer string) *Manager {
 	return &Manager{
 		signingKey: signingKey,
 		tokenTTL:   tokenTTL,
+		issuer:     issuer,
 	}
 }
 
 // NewToken generates a new JWT token for the provided user with the configured
 // TTL and signing key. The token includes user-specific claims such as
-// user ID, email, role, and expiration time.
-func (m *Manager) NewToken(user models.User) (string, error) {
+// user ID, email, role, session ID and expiration time, and the registered
+// claims: the configured issuer, the user as the subject and the audience of the
+// provided grant. The scopes of the grant are included as the space-separated
+// "scope" claim. Non-empty attrs are included as the "attrs" claim.
+func (m *Manager) NewToken(
+	user models.User,
+	sessionID string,
+	grant models.Grant,
+	attrs map[string]interface{}) (string, error) {
+	claims := m.userClaims(user, sessionID, grant, m.tokenTTL)
+	if len(attrs) > 0 {
+		claims["attrs"] = attrs
+	}
+
+	return m.sign(claims)
+}
+
+// NewImpersonationToken generates a JWT token for the provided user that is used
+// by the admin with the provided actor ID. The admin is named in the "act" claim
+// as defined by RFC 8693, and the token expires after the provided TTL.
+func (m *Manager) NewImpersonationToken(
+	user models.User,
+	sessionID string,
+	actorID int64,
+	grant models.Grant,
+	ttl time.Duration) (string, error) {
+	claims := m.userClaims(user, sessionID, grant, ttl)
+	claims["act"] = map[string]interface{}{
+		"sub": strconv.FormatInt(actorID, 10),
+	}
+
+	return m.sign(claims)
+}
+
+func (m *Manager) userClaims(
+	user models.User,
+	sessionID string,
+	grant models.Grant,
+	ttl time.Duration) jwt.MapClaims {
+	now := time.Now()
+
 	claims := jwt.MapClaims{}
 
+	claims["iss"] = m.issuer
+	claims["sub"] = strconv.FormatInt(user.ID, 10)
+	claims["aud"] = grant.Audience
+	claims["iat"] = now.Unix()
+	claims["nbf"] = now.Unix()
+	claims["scope"] = strings.Join(grant.Scopes, " ")
 	claims["user_id"] = user.ID
 	claims["email"] = user.Email
 	claims["role"] = user.Role
-	claims["exp"] = time.Now().Add(m.tokenTTL).Unix()
+	claims["session_id"] = sessionID
+	claims["exp"] = now.Add(ttl).Unix()
 
+	return claims
+}
+
+func (m *Manager) sign(claims jwt.MapClaims) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS384, claims)
 
 	tokenString, err := token.SignedString(m.signingKey)
@@ -48,10 +104,14 @@ func (m *Manager) NewToken(user models.User) (string, error) {
 }
 
 // ParseToken parses the provided JWT token string and validates its signature
-// using the configured signing key. It returns the claims embedded in the token
-// if the signature is valid.
+// using the configured signing key, its time claims and its issuer. It returns the
+// claims embedded in the token if the token is valid. The audience is not checked,
+// as tokens are parsed for other audiences by introspection. Numeric claims are
+// decoded as json.Number, so that 64-bit user IDs do not lose precision.
 func (m *Manager) ParseToken(accessToken string) (jwt.MapClaims, error) {
-	token, err := jwt.Parse(accessToken, func(tkn *jwt.Token) (interface{}, error) {
+	parser := jwt.Parser{UseJSONNumber: true}
+
+	token, err := parser.Parse(accessToken, func(tkn *jwt.Token) (interface{}, error) {
 		if _, ok := tkn.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", tkn.Header["alg"]) //nolint
 		}
@@ -66,13 +126,22 @@ func (m *Manager) ParseToken(accessToken string) (jwt.MapClaims, error) {
 		return nil, grpcerror.ErrNoToken
 	}
 
+	if !claims.VerifyIssuer(m.issuer, true) {
+		return nil, fmt.Errorf("failed to parse token: %w", grpcerror.ErrInvalidToken)
+	}
+
 	return claims, nil
 }
 
 // GetClaims extracts and returns the JWT claims from the authorization token
 // in the provided context. It relies on the ParseToken method to parse and
-// validate the token's signature.
+// validate the token's signature. Claims put in the context by ContextWithClaims
+// are returned as they are.
 func (m *Manager) GetClaims(ctx context.Context) (jwt.MapClaims, error) {
+	if claims, ok := ctx.Value(claimsKey{}).(jwt.MapClaims); ok {
+		return claims, nil
+	}
+
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		return nil, grpcerror.ErrTokenClaims
@@ -111,15 +180,184 @@ func (m *Manager) GetUserIDFromContext(ctx context.Context) (int64, error) {
 		return -1, err
 	}
 
+	return UserIDFromClaims(claims)
+}
+
+// UserIDFromClaims extracts the user ID from the provided token claims.
+func UserIDFromClaims(claims jwt.MapClaims) (int64, error) {
 	id, ok := claims["user_id"]
 	if !ok {
 		return -1, grpcerror.ErrTokenClaims
 	}
 
-	idFloat, ok := id.(float64)
-	if !ok {
+	return userIDFromClaim(id)
+}
+
+// userIDFromClaim converts the user_id claim to int64. Tokens parsed by ParseToken
+// carry json.Number, while claims decoded elsewhere may carry float64.
+func userIDFromClaim(id interface{}) (int64, error) {
+	switch v := id.(type) {
+	case json.Number:
+		userID, err := v.Int64()
+		if err != nil {
+			return -1, grpcerror.ErrTokenClaims
+		}
+		return userID, nil
+	case float64:
+		return int64(v), nil
+	default:
 		return -1, grpcerror.ErrTokenClaims
 	}
+}
+
+// GetSessionIDFromContext extracts the ID of the session the authorization
+// token in the provided context was issued for.
+func (m *Manager) GetSessionIDFromContext(ctx context.Context) (string, error) {
+	claims, err := m.GetClaims(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return SessionIDFromClaims(claims)
+}
+
+// SessionIDFromClaims returns the session ID stored in the provided token claims.
+func SessionIDFromClaims(claims jwt.MapClaims) (string, error) {
+	sessionID, ok := claims["session_id"].(string)
+	if !ok || sessionID == "" {
+		return "", grpcerror.ErrTokenClaims
+	}
+
+	return sessionID, nil
+}
+
+// ActorIDFromClaims returns the ID of the admin named in the "act" claim of an
+// impersonation token. It reports false for tokens issued to the user.
+func ActorIDFromClaims(claims jwt.MapClaims) (int64, bool, error) {
+	act, ok := claims["act"]
+	if !ok {
+		return 0, false, nil
+	}
+
+	actor, ok := act.(map[string]interface{})
+	if !ok {
+		return 0, true, grpcerror.ErrTokenClaims
+	}
+
+	sub, ok := actor["sub"].(string)
+	if !ok {
+		return 0, true, grpcerror.ErrTokenClaims
+	}
+
+	actorID, err := strconv.ParseInt(sub, 10, 64)
+	if err != nil {
+		return 0, true, grpcerror.ErrTokenClaims
+	}
+
+	return actorID, true, nil
+}
+
+// ExpiresAtFromClaims returns the expiration time of the token as a Unix time.
+func ExpiresAtFromClaims(claims jwt.MapClaims) (int64, error) {
+	return unixTimeClaim(claims, "exp")
+}
+
+// IssuedAtFromClaims returns the issue time of the token as a Unix time.
+func IssuedAtFromClaims(claims jwt.MapClaims) (int64, error) {
+	return unixTimeClaim(claims, "iat")
+}
+
+func unixTimeClaim(claims jwt.MapClaims, name string) (int64, error) {
+	switch v := claims[name].(type) {
+	case json.Number:
+		t, err := v.Int64()
+		if err != nil {
+			return 0, grpcerror.ErrTokenClaims
+		}
+		return t, nil
+	case float64:
+		return int64(v), nil
+	default:
+		return 0, grpcerror.ErrTokenClaims
+	}
+}
+
+// HasAudience reports whether the token was issued for the provided audience.
+func HasAudience(claims jwt.MapClaims, audience string) bool {
+	return claims.VerifyAudience(audience, true)
+}
+
+// AudienceFromClaims returns the audiences of the "aud" claim, which holds either a
+// single audience or a list of them, read the way HasAudience reads them.
+func AudienceFromClaims(claims jwt.MapClaims) ([]string, error) {
+	switch aud := claims["aud"].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{aud}, nil
+	case []string:
+		return aud, nil
+	case []interface{}:
+		audience := make([]string, 0, len(aud))
+		for _, a := range aud {
+			s, ok := a.(string)
+			if !ok {
+				return nil, grpcerror.ErrTokenClaims
+			}
+			audience = append(audience, s)
+		}
+		return audience, nil
+	default:
+		return nil, grpcerror.ErrTokenClaims
+	}
+}
+
+// AttributesFromClaims returns the custom attributes of the "attrs" claim, or nil
+// if the token carries none.
+func AttributesFromClaims(claims jwt.MapClaims) (map[string]interface{}, error) {
+	attrs, ok := claims["attrs"]
+	if !ok {
+		return nil, nil
+	}
+
+	attributes, ok := attrs.(map[string]interface{})
+	if !ok {
+		return nil, grpcerror.ErrTokenClaims
+	}
+
+	return attributes, nil
+}
+
+// ScopesFromClaims returns the scopes listed in the space-separated "scope" claim.
+func ScopesFromClaims(claims jwt.MapClaims) []string {
+	scope, _ := claims["scope"].(string)
+	return strings.Fields(scope)
+}
+
+// AccessTokenClaims returns the claims of a request authenticated with the personal
+// access token with the provided ID and scopes that belongs to the provided user.
+// The claims carry no session ID, so the session RPCs cannot be called with them.
+func AccessTokenClaims(user models.User, tokenID string, scopes []string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":             strconv.FormatInt(user.ID, 10),
+		"scope":           strings.Join(scopes, " "),
+		"user_id":         json.Number(strconv.FormatInt(user.ID, 10)),
+		"email":           user.Email,
+		"role":            string(user.Role),
+		"access_token_id": tokenID,
+	}
+}
+
+// AccessTokenIDFromClaims returns the ID of the personal access token the request
+// was authenticated with. It reports false for requests made with a JWT.
+func AccessTokenIDFromClaims(claims jwt.MapClaims) (string, bool) {
+	tokenID, ok := claims["access_token_id"].(string)
+	return tokenID, ok && tokenID != ""
+}
 
-	return int64(idFloat), nil
+// ContextWithClaims returns a copy of the provided context that carries the
+// provided claims, so that GetClaims returns them instead of parsing the
+// authorization token, e.g. for requests made with a personal access token.
+func ContextWithClaims(ctx context.Context, claims jwt.MapClaims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
 }
@@ -0,0 +1,142 @@
+package meta
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+type clientIPKey struct{}
+
+// UserAgent returns the user agent of the client that sent the incoming request,
+// or an empty string if it was not provided.
+func UserAgent(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	if values := md.Get("user-agent"); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// TrustedProxies holds the networks of the proxies, e.g. the ingress, whose
+// x-forwarded-for header is trusted to carry the address of the client.
+type TrustedProxies []*net.IPNet
+
+// ParseTrustedProxies parses the provided CIDRs. Single addresses are accepted as
+// networks of one address.
+func ParseTrustedProxies(cidrs []string) (TrustedProxies, error) {
+	proxies := make(TrustedProxies, 0, len(cidrs))
+
+	for _, cidr := range cidrs {
+		if !strings.Contains(cidr, "/") {
+			ip := net.ParseIP(cidr)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", cidr)
+			}
+			bits := 8 * len(ip.To16())
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", cidr, err)
+		}
+		proxies = append(proxies, network)
+	}
+
+	return proxies, nil
+}
+
+func (p TrustedProxies) contains(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range p {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ResolveClientIP returns the IP address of the client that sent the incoming
+// request. The x-forwarded-for header is honoured only when the request came from
+// a trusted proxy; the addresses are walked from the right, and the first one that
+// is not a trusted proxy is the client, so that a client cannot spoof its address
+// by sending the header itself.
+func (p TrustedProxies) ResolveClientIP(ctx context.Context) string {
+	ip := peerIP(ctx)
+	if !p.contains(ip) {
+		return ip
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ip
+	}
+
+	var forwarded []string
+	for _, value := range md.Get("x-forwarded-for") {
+		forwarded = append(forwarded, strings.Split(value, ",")...)
+	}
+
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		addr := strings.TrimSpace(forwarded[i])
+		if net.ParseIP(addr) == nil {
+			break
+		}
+
+		ip = addr
+		if !p.contains(addr) {
+			break
+		}
+	}
+
+	return ip
+}
+
+// ContextWithClientIP returns a copy of the provided context that carries the
+// provided client IP address.
+func ContextWithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+// ClientIP returns the IP address of the client that sent the incoming request as
+// resolved by ResolveClientIP, or the address of the peer if it was not resolved.
+func ClientIP(ctx context.Context) string {
+	if ip, ok := ctx.Value(clientIPKey{}).(string); ok {
+		return ip
+	}
+
+	return peerIP(ctx)
+}
+
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+
+	return host
+}
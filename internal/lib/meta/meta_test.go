@@ -0,0 +1,69 @@
+package meta
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func newIncomingContext(peerAddr string, forwardedFor ...string) context.Context {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP(peerAddr), Port: 50000},
+	})
+
+	md := metadata.MD{}
+	for _, value := range forwardedFor {
+		md.Append("x-forwarded-for", value)
+	}
+
+	return metadata.NewIncomingContext(ctx, md)
+}
+
+func TestResolveClientIP(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		peer         string
+		forwardedFor []string
+		want         string
+	}{
+		{"direct", "203.0.113.7", nil, "203.0.113.7"},
+		{"spoofed by client", "203.0.113.7", []string{"1.2.3.4"}, "203.0.113.7"},
+		{"trusted proxy", "10.1.2.3", []string{"198.51.100.1"}, "198.51.100.1"},
+		{"single address proxy", "192.168.1.1", []string{"198.51.100.1"}, "198.51.100.1"},
+		{"spoofed behind proxy", "10.1.2.3", []string{"1.2.3.4, 198.51.100.1"}, "198.51.100.1"},
+		{"chain of proxies", "10.1.2.3", []string{"198.51.100.1, 10.9.9.9"}, "198.51.100.1"},
+		{"repeated header", "10.1.2.3", []string{"1.2.3.4", "198.51.100.1"}, "198.51.100.1"},
+		{"garbage", "10.1.2.3", []string{"unknown"}, "10.1.2.3"},
+		{"no header", "10.1.2.3", nil, "10.1.2.3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newIncomingContext(tt.peer, tt.forwardedFor...)
+			assert.Equal(t, tt.want, proxies.ResolveClientIP(ctx))
+		})
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	ctx := newIncomingContext("10.1.2.3", "198.51.100.1")
+	assert.Equal(t, "10.1.2.3", ClientIP(ctx), "the header is not honoured unless resolved")
+
+	ctx = ContextWithClientIP(ctx, "198.51.100.1")
+	assert.Equal(t, "198.51.100.1", ClientIP(ctx))
+}
+
+func TestParseTrustedProxies_Invalid(t *testing.T) {
+	for _, cidr := range []string{"10.0.0.0/33", "proxy", ""} {
+		_, err := ParseTrustedProxies([]string{cidr})
+		assert.Error(t, err, cidr)
+	}
+}
@@ -0,0 +1,76 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1" //nolint:gosec // SHA-1 is the format of breached password lists
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// prefixLength is the length of the hash prefix the breached list is bucketed by.
+const prefixLength = 5
+
+// BreachedList is a local list of SHA-1 hashes of breached passwords. Hashes are
+// bucketed by their 5-character prefix, as in the k-anonymity range API, so a
+// lookup only compares suffixes within a single bucket.
+type BreachedList struct {
+	buckets map[string]map[string]struct{}
+}
+
+// LoadBreachedList reads a breached password list file. Each line holds an
+// uppercase or lowercase hex SHA-1 hash, optionally followed by ":<count>".
+func LoadBreachedList(path string) (*BreachedList, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open breached password list: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	list := &BreachedList{buckets: make(map[string]map[string]struct{})}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		hash, _, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if len(hash) != sha1.Size*2 {
+			continue
+		}
+		list.add(strings.ToUpper(hash))
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read breached password list: %w", err)
+	}
+
+	return list, nil
+}
+
+// Contains reports whether the password is present in the list.
+func (l *BreachedList) Contains(password string) bool {
+	sum := sha1.Sum([]byte(password)) //nolint:gosec // SHA-1 is the format of breached password lists
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	bucket, ok := l.buckets[hash[:prefixLength]]
+	if !ok {
+		return false
+	}
+
+	_, ok = bucket[hash[prefixLength:]]
+
+	return ok
+}
+
+func (l *BreachedList) add(hash string) {
+	prefix, suffix := hash[:prefixLength], hash[prefixLength:]
+
+	bucket, ok := l.buckets[prefix]
+	if !ok {
+		bucket = make(map[string]struct{})
+		l.buckets[prefix] = bucket
+	}
+
+	bucket[suffix] = struct{}{}
+}
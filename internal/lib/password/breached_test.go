@@ -0,0 +1,73 @@
+package password
+
+import (
+	"crypto/sha1" //nolint:gosec // SHA-1 is the format of breached password lists
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadBreachedList(t *testing.T) {
+	path := writeBreachedList(t,
+		strings.ToUpper(sha1Hex("password")),
+		strings.ToLower(sha1Hex("qwerty"))+":3730471",
+		"  "+sha1Hex("letmein")+":12  ",
+		"not a hash",
+		"",
+		sha1Hex("short")[:20],
+	)
+
+	list, err := LoadBreachedList(path)
+	require.NoError(t, err)
+
+	tests := []struct {
+		password string
+		breached bool
+	}{
+		{"password", true},
+		{"qwerty", true},
+		{"letmein", true},
+		{"short", false},
+		{"Str0ng!pass", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.password, func(t *testing.T) {
+			assert.Equal(t, tt.breached, list.Contains(tt.password))
+		})
+	}
+}
+
+func TestLoadBreachedList_SharedPrefix(t *testing.T) {
+	hash := strings.ToUpper(sha1Hex("password"))
+	sibling := hash[:prefixLength] + strings.Repeat("0", len(hash)-prefixLength)
+
+	list, err := LoadBreachedList(writeBreachedList(t, sibling))
+	require.NoError(t, err)
+
+	assert.False(t, list.Contains("password"))
+}
+
+func TestLoadBreachedList_MissingFile(t *testing.T) {
+	_, err := LoadBreachedList(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
+
+func sha1Hex(password string) string {
+	sum := sha1.Sum([]byte(password)) //nolint:gosec // SHA-1 is the format of breached password lists
+	return hex.EncodeToString(sum[:])
+}
+
+func writeBreachedList(t *testing.T, lines ...string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600))
+
+	return path
+}
@@ -0,0 +1,176 @@
+package password
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// minPersonalInfoLength is the minimal length of a personal info substring
+// (name, surname, email local part) that is checked for presence in a password.
+const minPersonalInfoLength = 3
+
+// Violation describes a single password policy rule that was not satisfied.
+type Violation struct {
+	Field       string
+	Description string
+}
+
+// PolicyError is returned when a password does not satisfy the password policy.
+type PolicyError struct {
+	Violations []Violation
+}
+
+func (e *PolicyError) Error() string {
+	descriptions := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		descriptions = append(descriptions, v.Description)
+	}
+
+	return fmt.Sprintf("%s: %s", grpcerror.ErrWeakPassword.Error(), strings.Join(descriptions, "; "))
+}
+
+func (e *PolicyError) Unwrap() error {
+	return grpcerror.ErrWeakPassword
+}
+
+// GRPCStatus converts the error into an InvalidArgument status carrying
+// the field-level violations as BadRequest details.
+func (e *PolicyError) GRPCStatus() *status.Status {
+	st := status.New(codes.InvalidArgument, e.Error())
+
+	br := &errdetails.BadRequest{}
+	for _, v := range e.Violations {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+		})
+	}
+
+	detailed, err := st.WithDetails(br)
+	if err != nil {
+		return st
+	}
+
+	return detailed
+}
+
+// Policy validates passwords against the configured password rules
+// and the optional list of breached passwords.
+type Policy struct {
+	cfg      config.PasswordPolicyConfig
+	breached *BreachedList
+}
+
+// NewPolicy creates and returns a new instance of the Policy. If a breached
+// password list is configured, it is loaded from the file.
+func NewPolicy(cfg config.PasswordPolicyConfig) (*Policy, error) {
+	policy := &Policy{cfg: cfg}
+
+	if cfg.BreachedListPath != "" {
+		list, err := LoadBreachedList(cfg.BreachedListPath)
+		if err != nil {
+			return nil, err
+		}
+		policy.breached = list
+	}
+
+	return policy, nil
+}
+
+// HistorySize returns the number of previous passwords that may not be reused.
+func (p *Policy) HistorySize() int {
+	return p.cfg.HistorySize
+}
+
+// Validate checks the password provided for the field against the policy rules.
+// The user is used to disallow passwords containing personal information.
+// It returns a *PolicyError listing every violated rule, or nil.
+func (p *Policy) Validate(field, password string, user *models.User) error {
+	var violations []Violation
+
+	addViolation := func(description string) {
+		violations = append(violations, Violation{Field: field, Description: description})
+	}
+
+	length := utf8.RuneCountInString(password)
+	if length < p.cfg.MinLength {
+		addViolation(fmt.Sprintf("password must be at least %d characters long", p.cfg.MinLength))
+	}
+	if p.cfg.MaxLength > 0 && length > p.cfg.MaxLength {
+		addViolation(fmt.Sprintf("password must be at most %d characters long", p.cfg.MaxLength))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSymbol = true
+		}
+	}
+
+	if p.cfg.RequireUpper && !hasUpper {
+		addViolation("password must contain an uppercase letter")
+	}
+	if p.cfg.RequireLower && !hasLower {
+		addViolation("password must contain a lowercase letter")
+	}
+	if p.cfg.RequireDigit && !hasDigit {
+		addViolation("password must contain a digit")
+	}
+	if p.cfg.RequireSymbol && !hasSymbol {
+		addViolation("password must contain a special character")
+	}
+
+	if p.cfg.DisallowPersonalInfo && user != nil && containsPersonalInfo(password, user) {
+		addViolation("password must not contain your email, name or surname")
+	}
+
+	if p.breached != nil && p.breached.Contains(password) {
+		addViolation("password was found in a data breach")
+	}
+
+	if len(violations) > 0 {
+		return &PolicyError{Violations: violations}
+	}
+
+	return nil
+}
+
+// ReuseError returns the policy error reported when a recently used password
+// is provided for the field.
+func ReuseError(field string) error {
+	return &PolicyError{Violations: []Violation{{
+		Field:       field,
+		Description: "password was used recently",
+	}}}
+}
+
+func containsPersonalInfo(password string, user *models.User) bool {
+	lowered := strings.ToLower(password)
+
+	localPart, _, _ := strings.Cut(user.Email, "@")
+
+	for _, info := range []string{localPart, user.Name, user.Surname} {
+		info = strings.ToLower(strings.TrimSpace(info))
+		if utf8.RuneCountInString(info) >= minPersonalInfoLength && strings.Contains(lowered, info) {
+			return true
+		}
+	}
+
+	return false
+}
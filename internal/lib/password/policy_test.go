@@ -0,0 +1,157 @@
+package password
+
+import (
+	"errors"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"path/filepath"
+	"testing"
+)
+
+var testPolicyConfig = config.PasswordPolicyConfig{
+	MinLength:            8,
+	MaxLength:            16,
+	RequireUpper:         true,
+	RequireLower:         true,
+	RequireDigit:         true,
+	RequireSymbol:        true,
+	DisallowPersonalInfo: true,
+	HistorySize:          5,
+}
+
+var testUser = &models.User{
+	Email:   "johnny@example.com",
+	Name:    "John",
+	Surname: "Smith",
+}
+
+func newTestPolicy(t *testing.T, cfg config.PasswordPolicyConfig) *Policy {
+	t.Helper()
+
+	p, err := NewPolicy(cfg)
+	require.NoError(t, err)
+
+	return p
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	p := newTestPolicy(t, testPolicyConfig)
+
+	tests := []struct {
+		name       string
+		password   string
+		user       *models.User
+		violations []string
+	}{
+		{"valid password", "Str0ng!pass", testUser, nil},
+		{"too short", "Ab1!", testUser, []string{"password must be at least 8 characters long"}},
+		{"too long", "Abcdefgh1!abcdefgh", testUser, []string{"password must be at most 16 characters long"}},
+		{"length counted in runes", "Пароль1!Ab", testUser, nil},
+		{"no uppercase", "str0ng!pass", testUser, []string{"password must contain an uppercase letter"}},
+		{"no lowercase", "STR0NG!PASS", testUser, []string{"password must contain a lowercase letter"}},
+		{"no digit", "Strong!pass", testUser, []string{"password must contain a digit"}},
+		{"no symbol", "Str0ngpass", testUser, []string{"password must contain a special character"}},
+		{"contains name", "John!s3cret", testUser, []string{"password must not contain your email, name or surname"}},
+		{"contains surname case-insensitively", "mySMITH!1a", testUser, []string{"password must not contain your email, name or surname"}},
+		{"contains email local part", "Johnny!123", testUser, []string{"password must not contain your email, name or surname"}},
+		{"personal info ignored without user", "John!s3cret", nil, nil},
+		{"several violations", "abc", testUser, []string{
+			"password must be at least 8 characters long",
+			"password must contain an uppercase letter",
+			"password must contain a digit",
+			"password must contain a special character",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := p.Validate("password", tt.password, tt.user)
+			if tt.violations == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			var policyErr *PolicyError
+			require.True(t, errors.As(err, &policyErr), err)
+			assert.True(t, errors.Is(err, grpcerror.ErrWeakPassword))
+
+			descriptions := make([]string, 0, len(policyErr.Violations))
+			for _, v := range policyErr.Violations {
+				assert.Equal(t, "password", v.Field)
+				descriptions = append(descriptions, v.Description)
+			}
+			assert.Equal(t, tt.violations, descriptions)
+		})
+	}
+}
+
+func TestPolicy_Validate_ShortPersonalInfoIgnored(t *testing.T) {
+	p := newTestPolicy(t, testPolicyConfig)
+
+	user := &models.User{Email: "al@example.com", Name: "Al", Surname: "Li"}
+
+	assert.NoError(t, p.Validate("password", "Al!Li1alpha", user))
+}
+
+func TestPolicy_Validate_DisabledRules(t *testing.T) {
+	p := newTestPolicy(t, config.PasswordPolicyConfig{MinLength: 1})
+
+	assert.NoError(t, p.Validate("password", "john", testUser))
+}
+
+func TestPolicy_Validate_Breached(t *testing.T) {
+	cfg := testPolicyConfig
+	cfg.BreachedListPath = writeBreachedList(t, sha1Hex("Str0ng!pass"))
+
+	p := newTestPolicy(t, cfg)
+
+	err := p.Validate("new_password", "Str0ng!pass", testUser)
+	var policyErr *PolicyError
+	require.True(t, errors.As(err, &policyErr), err)
+	assert.Equal(t, []Violation{{Field: "new_password", Description: "password was found in a data breach"}}, policyErr.Violations)
+
+	assert.NoError(t, p.Validate("new_password", "0ther!Pass", testUser))
+}
+
+func TestNewPolicy_MissingBreachedList(t *testing.T) {
+	cfg := testPolicyConfig
+	cfg.BreachedListPath = filepath.Join(t.TempDir(), "missing.txt")
+
+	_, err := NewPolicy(cfg)
+	assert.Error(t, err)
+}
+
+func TestPolicyError_GRPCStatus(t *testing.T) {
+	err := &PolicyError{Violations: []Violation{
+		{Field: "password", Description: "password must contain a digit"},
+		{Field: "password", Description: "password must contain a special character"},
+	}}
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Equal(t, err.Error(), st.Message())
+	assert.Contains(t, st.Message(), grpcerror.ErrWeakPassword.Error())
+
+	require.Len(t, st.Details(), 1)
+	br, ok := st.Details()[0].(*errdetails.BadRequest)
+	require.True(t, ok)
+	require.Len(t, br.GetFieldViolations(), 2)
+	assert.Equal(t, "password", br.GetFieldViolations()[0].GetField())
+	assert.Equal(t, "password must contain a digit", br.GetFieldViolations()[0].GetDescription())
+	assert.Equal(t, "password must contain a special character", br.GetFieldViolations()[1].GetDescription())
+}
+
+func TestReuseError(t *testing.T) {
+	err := ReuseError("new_password")
+
+	assert.True(t, errors.Is(err, grpcerror.ErrWeakPassword))
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Contains(t, err.Error(), "password was used recently")
+}
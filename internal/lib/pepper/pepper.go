@@ -0,0 +1,49 @@
+package pepper
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+)
+
+var ErrUnknownVersion = errors.New("unknown pepper version")
+
+// Peppers holds the versioned secrets appended to passwords before hashing.
+type Peppers struct {
+	current  int
+	versions map[int]string
+}
+
+// New creates and returns a new instance of Peppers from the provided
+// configuration. It fails if the current version has no pepper configured.
+func New(cfg config.PepperConfig) (*Peppers, error) {
+	if _, ok := cfg.Versions[cfg.CurrentVersion]; !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownVersion, cfg.CurrentVersion)
+	}
+
+	return &Peppers{
+		current:  cfg.CurrentVersion,
+		versions: cfg.Versions,
+	}, nil
+}
+
+// Current returns the version of the pepper used for new password hashes.
+func (p *Peppers) Current() int {
+	return p.current
+}
+
+// Apply appends the pepper of the provided version to the password.
+func (p *Peppers) Apply(password string, version int) (string, error) {
+	pepper, ok := p.versions[version]
+	if !ok {
+		return "", fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+
+	return password + pepper, nil
+}
+
+// ApplyCurrent appends the current pepper to the password.
+func (p *Peppers) ApplyCurrent(password string) string {
+	return password + p.versions[p.current]
+}
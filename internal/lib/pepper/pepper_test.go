@@ -0,0 +1,106 @@
+package pepper
+
+import (
+	"errors"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.PepperConfig
+		err  error
+	}{
+		{
+			name: "current version configured",
+			cfg:  config.PepperConfig{CurrentVersion: 2, Versions: map[int]string{1: "old", 2: "new"}},
+		},
+		{
+			name: "current version missing",
+			cfg:  config.PepperConfig{CurrentVersion: 3, Versions: map[int]string{1: "old", 2: "new"}},
+			err:  ErrUnknownVersion,
+		},
+		{
+			name: "no versions",
+			cfg:  config.PepperConfig{},
+			err:  ErrUnknownVersion,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := New(tt.cfg)
+			if tt.err != nil {
+				assert.True(t, errors.Is(err, tt.err), err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.cfg.CurrentVersion, p.Current())
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	p, err := New(config.PepperConfig{CurrentVersion: 2, Versions: map[int]string{1: "old", 2: "new"}})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		version  int
+		expected string
+		err      error
+	}{
+		{"previous version", 1, "passwordold", nil},
+		{"current version", 2, "passwordnew", nil},
+		{"unknown version", 3, "", ErrUnknownVersion},
+		{"negative version", -1, "", ErrUnknownVersion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			peppered, err := p.Apply("password", tt.version)
+			assert.True(t, errors.Is(err, tt.err), err)
+			assert.Equal(t, tt.expected, peppered)
+		})
+	}
+}
+
+func TestApplyCurrent(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      config.PepperConfig
+		expected string
+	}{
+		{
+			name:     "latest version",
+			cfg:      config.PepperConfig{CurrentVersion: 2, Versions: map[int]string{1: "old", 2: "new"}},
+			expected: "passwordnew",
+		},
+		{
+			name:     "rolled back version",
+			cfg:      config.PepperConfig{CurrentVersion: 1, Versions: map[int]string{1: "old", 2: "new"}},
+			expected: "passwordold",
+		},
+		{
+			name:     "empty pepper",
+			cfg:      config.PepperConfig{CurrentVersion: 0, Versions: map[int]string{0: ""}},
+			expected: "password",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := New(tt.cfg)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, p.ApplyCurrent("password"))
+
+			applied, err := p.Apply("password", p.Current())
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, applied)
+		})
+	}
+}
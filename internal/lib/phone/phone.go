@@ -0,0 +1,34 @@
+package phone
+
+import (
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/nyaruka/phonenumbers"
+	"strings"
+)
+
+// Normalizer parses phone numbers and formats them in E.164.
+type Normalizer struct {
+	defaultRegion string
+}
+
+// NewNormalizer creates and returns a new instance of the Normalizer. Numbers
+// without a country calling code are parsed as numbers of defaultRegion, a CLDR
+// region code such as "BY" or "US".
+func NewNormalizer(defaultRegion string) *Normalizer {
+	return &Normalizer{defaultRegion: strings.ToUpper(defaultRegion)}
+}
+
+// Normalize returns the number in E.164, e.g. "+375291234567". ErrInvalidPhone is
+// returned if the number cannot be parsed or is not a valid number of its region.
+func (n *Normalizer) Normalize(number string) (string, error) {
+	parsed, err := phonenumbers.Parse(strings.TrimSpace(number), n.defaultRegion)
+	if err != nil {
+		return "", grpcerror.ErrInvalidPhone
+	}
+
+	if !phonenumbers.IsValidNumber(parsed) {
+		return "", grpcerror.ErrInvalidPhone
+	}
+
+	return phonenumbers.Format(parsed, phonenumbers.E164), nil
+}
@@ -0,0 +1,41 @@
+package phone
+
+import (
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestNormalizer_Normalize(t *testing.T) {
+	n := NewNormalizer("by")
+
+	table := []struct {
+		name     string
+		number   string
+		expected string
+	}{
+		{name: "E.164", number: "+375291234567", expected: "+375291234567"},
+		{name: "Formatted", number: "+375 (29) 123-45-67", expected: "+375291234567"},
+		{name: "National with trunk prefix", number: "8 029 123 45 67", expected: "+375291234567"},
+		{name: "Other region", number: "+1 650-253-0000", expected: "+16502530000"},
+		{name: "Surrounding whitespace", number: "  +375291234567 ", expected: "+375291234567"},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			normalized, err := n.Normalize(tt.number)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, normalized)
+		})
+	}
+}
+
+func TestNormalizer_Invalid(t *testing.T) {
+	n := NewNormalizer("BY")
+
+	for _, number := range []string{"", "call me", "12345", "+375 11 111"} {
+		_, err := n.Normalize(number)
+		assert.ErrorIs(t, err, grpcerror.ErrInvalidPhone, number)
+	}
+}
@@ -0,0 +1,30 @@
+package phone
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SMSSender delivers text messages to phone numbers in E.164.
+type SMSSender interface {
+	Send(ctx context.Context, to, text string) error
+}
+
+// LogSMSSender is an SMSSender that writes messages to the log instead of
+// delivering them. It is meant for local development and tests.
+type LogSMSSender struct {
+	log *slog.Logger
+}
+
+// NewLogSMSSender creates and returns a new instance of the LogSMSSender.
+func NewLogSMSSender(log *slog.Logger) *LogSMSSender {
+	return &LogSMSSender{log: log}
+}
+
+func (s *LogSMSSender) Send(_ context.Context, to, text string) error {
+	s.log.Info("sms sent",
+		slog.String("to", to),
+		slog.String("text", text))
+
+	return nil
+}
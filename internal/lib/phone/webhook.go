@@ -0,0 +1,80 @@
+package phone
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+var (
+	ErrInvalidSMSConfig = errors.New("invalid sms configuration")
+	ErrSMSRejected      = errors.New("sms gateway rejected the message")
+)
+
+// WebhookSMSSender is an SMSSender that posts every message as JSON to an HTTP
+// gateway, authorized with a bearer API key.
+type WebhookSMSSender struct {
+	url    string
+	apiKey string
+	client *http.Client
+}
+
+type webhookMessage struct {
+	To   string `json:"to"`
+	Text string `json:"text"`
+}
+
+// NewWebhookSMSSender creates and returns a new instance of the WebhookSMSSender
+// for the provided gateway.
+func NewWebhookSMSSender(cfg config.SMSConfig) (*WebhookSMSSender, error) {
+	u, err := url.Parse(cfg.WebhookURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return nil, fmt.Errorf("%w: invalid webhook url", ErrInvalidSMSConfig)
+	}
+
+	return &WebhookSMSSender{
+		url:    cfg.WebhookURL,
+		apiKey: cfg.APIKey,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}, nil
+}
+
+// Send posts the message to the gateway. Any response other than 2xx is an error.
+func (s *WebhookSMSSender) Send(ctx context.Context, to, text string) error {
+	const op = "phone.webhook.Send"
+
+	body, err := json.Marshal(webhookMessage{To: to, Text: text})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%s: %w: status %d", op, ErrSMSRejected, resp.StatusCode)
+	}
+
+	return nil
+}
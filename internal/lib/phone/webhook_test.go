@@ -0,0 +1,55 @@
+package phone
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWebhookSMSSender_Send(t *testing.T) {
+	var (
+		got  webhookMessage
+		auth string
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	sender, err := NewWebhookSMSSender(config.SMSConfig{WebhookURL: srv.URL, APIKey: "key", Timeout: time.Second})
+	require.NoError(t, err)
+
+	require.NoError(t, sender.Send(context.Background(), "+375291234567", "Your code is 123456"))
+	assert.Equal(t, "Bearer key", auth)
+	assert.Equal(t, webhookMessage{To: "+375291234567", Text: "Your code is 123456"}, got)
+}
+
+func TestWebhookSMSSender_Rejected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	sender, err := NewWebhookSMSSender(config.SMSConfig{WebhookURL: srv.URL, Timeout: time.Second})
+	require.NoError(t, err)
+
+	err = sender.Send(context.Background(), "+375291234567", "text")
+	assert.True(t, errors.Is(err, ErrSMSRejected), err)
+}
+
+func TestNewWebhookSMSSender_InvalidURL(t *testing.T) {
+	for _, u := range []string{"", "gateway.local/sms", "ftp://gateway.local/sms"} {
+		_, err := NewWebhookSMSSender(config.SMSConfig{WebhookURL: u})
+		assert.True(t, errors.Is(err, ErrInvalidSMSConfig), u)
+	}
+}
@@ -0,0 +1,112 @@
+package pii
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+const keySize = 32
+
+var (
+	ErrUnknownKey = errors.New("unknown master key")
+	ErrInvalidKey = errors.New("invalid master key")
+)
+
+// KeyProvider wraps and unwraps data encryption keys with master keys that never
+// leave it. It is the extension point for an external KMS.
+type KeyProvider interface {
+	CurrentKeyID() string
+	Wrap(keyID string, dataKey []byte) ([]byte, error)
+	Unwrap(keyID string, wrapped []byte) ([]byte, error)
+}
+
+// LocalKMS is a KeyProvider that keeps the master keys in memory. It stands in for
+// an external KMS and wraps data keys with AES-256-GCM.
+type LocalKMS struct {
+	current string
+	keys    map[string]cipher.AEAD
+}
+
+// NewLocalKMS creates and returns a new instance of the LocalKMS from base64-encoded
+// 256-bit master keys indexed by key ID. New data keys are wrapped with the current key.
+func NewLocalKMS(masterKeys map[string]string, currentKeyID string) (*LocalKMS, error) {
+	kms := &LocalKMS{
+		current: currentKeyID,
+		keys:    make(map[string]cipher.AEAD, len(masterKeys)),
+	}
+
+	for id, encoded := range masterKeys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != keySize {
+			return nil, fmt.Errorf("%w: %s must be a base64-encoded 32-byte key", ErrInvalidKey, id)
+		}
+
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+
+		kms.keys[id] = aead
+	}
+
+	if _, ok := kms.keys[currentKeyID]; !ok {
+		return nil, fmt.Errorf("%w: current key %q is not configured", ErrUnknownKey, currentKeyID)
+	}
+
+	return kms, nil
+}
+
+func (k *LocalKMS) CurrentKeyID() string {
+	return k.current
+}
+
+func (k *LocalKMS) Wrap(keyID string, dataKey []byte) ([]byte, error) {
+	aead, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+
+	return seal(aead, dataKey, []byte(keyID))
+}
+
+func (k *LocalKMS) Unwrap(keyID string, wrapped []byte) ([]byte, error) {
+	aead, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+
+	return open(aead, wrapped, []byte(keyID))
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts the plaintext and prepends the random nonce to the ciphertext.
+func seal(aead cipher.AEAD, plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(aead cipher.AEAD, sealed, additionalData []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("ciphertext is too short")
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
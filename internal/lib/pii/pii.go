@@ -0,0 +1,298 @@
+package pii
+
+import (
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	"strings"
+)
+
+// prefix marks encrypted field values, so that documents written before encryption
+// was enabled can still be read.
+const prefix = "enc:v1:"
+
+var ErrNoDataKey = errors.New("encrypted field without a data key")
+
+// Cipher encrypts the personal data of users with envelope encryption. Every user
+// has its own data key, stored next to the data wrapped by a master key of the
+// KeyProvider. The normalized email is replaced with a keyed blind index, so that
+// users can still be looked up by email and email uniqueness can still be enforced.
+type Cipher struct {
+	kms      KeyProvider
+	indexKey []byte
+}
+
+// New creates and returns a new instance of the Cipher backed by the LocalKMS with
+// the configured master keys.
+func New(cfg config.EncryptionConfig) (*Cipher, error) {
+	kms, err := NewLocalKMS(cfg.MasterKeys, cfg.CurrentKeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCipher(kms, cfg.IndexKey)
+}
+
+// NewCipher creates and returns a new instance of the Cipher.
+func NewCipher(kms KeyProvider, indexKey string) (*Cipher, error) {
+	if indexKey == "" {
+		return nil, errors.New("blind index key is empty")
+	}
+
+	return &Cipher{
+		kms:      kms,
+		indexKey: []byte(indexKey),
+	}, nil
+}
+
+// BlindIndex returns the deterministic keyed hash of the normalized email or phone
+// number that is stored and queried instead of the normalized value itself.
+func (c *Cipher) BlindIndex(normalized string) string {
+	mac := hmac.New(sha256.New, c.indexKey)
+	mac.Write([]byte(normalized))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// IsEncrypted reports whether the value was produced by the Cipher.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// Encrypt encrypts the non-empty plaintext personal fields of the user in place.
+// The user's data key is used if it has one, otherwise a new data key is generated
+// and wrapped with the current master key.
+func (c *Cipher) Encrypt(user *models.User) error {
+	dataKey, err := c.dataKey(user)
+	if err != nil {
+		return err
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return err
+	}
+
+	for name, field := range personalFields(user) {
+		if err = sealField(aead, name, field); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Decrypt decrypts the encrypted personal fields of the user in place. Plaintext
+// fields are left as they are.
+func (c *Cipher) Decrypt(user *models.User) error {
+	fields := personalFields(user)
+
+	encrypted := false
+	for _, field := range fields {
+		encrypted = encrypted || IsEncrypted(*field)
+	}
+	if !encrypted {
+		return nil
+	}
+
+	if user.DataKey == nil {
+		return ErrNoDataKey
+	}
+
+	dataKey, err := c.kms.Unwrap(user.DataKey.KeyID, user.DataKey.Wrapped)
+	if err != nil {
+		return fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return err
+	}
+
+	for name, field := range fields {
+		if err = openField(aead, name, field); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// EncryptEmailChange encrypts the plaintext emails of the pending email change of
+// the user in place with the data key of the user. The emails are encrypted as the
+// email field, so that they can be copied to it when the change is applied.
+func (c *Cipher) EncryptEmailChange(user *models.User) error {
+	if user.EmailChange == nil {
+		return nil
+	}
+
+	dataKey, err := c.dataKey(user)
+	if err != nil {
+		return err
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return err
+	}
+
+	for _, field := range emailChangeFields(user.EmailChange) {
+		if err = sealField(aead, "email", field); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DecryptEmailChange decrypts the encrypted emails of the pending email change of
+// the user in place. Plaintext emails are left as they are.
+func (c *Cipher) DecryptEmailChange(user *models.User) error {
+	if user.EmailChange == nil {
+		return nil
+	}
+
+	fields := emailChangeFields(user.EmailChange)
+	if !IsEncrypted(*fields[0]) && !IsEncrypted(*fields[1]) {
+		return nil
+	}
+
+	if user.DataKey == nil {
+		return ErrNoDataKey
+	}
+
+	dataKey, err := c.kms.Unwrap(user.DataKey.KeyID, user.DataKey.Wrapped)
+	if err != nil {
+		return fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return err
+	}
+
+	for _, field := range fields {
+		if err = openField(aead, "email", field); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Reencrypt decrypts the personal fields of the user and the emails of its pending
+// email change, and encrypts them again with a new data key wrapped by the current
+// master key. The email change is copied first, so that copies of the user made
+// before the call keep the previous values.
+func (c *Cipher) Reencrypt(user *models.User) error {
+	if user.EmailChange != nil {
+		change := *user.EmailChange
+		user.EmailChange = &change
+	}
+
+	if err := c.DecryptEmailChange(user); err != nil {
+		return err
+	}
+
+	if err := c.Decrypt(user); err != nil {
+		return err
+	}
+
+	user.DataKey = nil
+
+	if err := c.Encrypt(user); err != nil {
+		return err
+	}
+
+	return c.EncryptEmailChange(user)
+}
+
+func (c *Cipher) dataKey(user *models.User) ([]byte, error) {
+	if user.DataKey != nil {
+		dataKey, err := c.kms.Unwrap(user.DataKey.KeyID, user.DataKey.Wrapped)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+		}
+		return dataKey, nil
+	}
+
+	dataKey := make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+
+	keyID := c.kms.CurrentKeyID()
+
+	wrapped, err := c.kms.Wrap(keyID, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap data key: %w", err)
+	}
+
+	user.DataKey = &models.DataKey{
+		KeyID:   keyID,
+		Wrapped: wrapped,
+	}
+
+	return dataKey, nil
+}
+
+// personalFields returns the encrypted fields of the user by name. The name is
+// bound to the ciphertext, so that encrypted values cannot be swapped between fields.
+func personalFields(user *models.User) map[string]*string {
+	return map[string]*string{
+		"email":        &user.Email,
+		"phone_number": &user.PhoneNumber,
+		"name":         &user.Name,
+		"surname":      &user.Surname,
+	}
+}
+
+// emailChangeFields returns the encrypted emails of the email change.
+func emailChangeFields(change *models.EmailChange) []*string {
+	return []*string{&change.Email, &change.PreviousEmail}
+}
+
+// sealField encrypts the non-empty plaintext value of the field with the provided
+// name in place.
+func sealField(aead cipher.AEAD, name string, field *string) error {
+	if *field == "" || IsEncrypted(*field) {
+		return nil
+	}
+
+	sealed, err := seal(aead, []byte(*field), []byte(name))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt %s: %w", name, err)
+	}
+
+	*field = prefix + base64.StdEncoding.EncodeToString(sealed)
+
+	return nil
+}
+
+// openField decrypts the encrypted value of the field with the provided name in
+// place. Plaintext values are left as they are.
+func openField(aead cipher.AEAD, name string, field *string) error {
+	if !IsEncrypted(*field) {
+		return nil
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(*field, prefix))
+	if err != nil {
+		return fmt.Errorf("failed to decode %s: %w", name, err)
+	}
+
+	plaintext, err := open(aead, sealed, []byte(name))
+	if err != nil {
+		return fmt.Errorf("failed to decrypt %s: %w", name, err)
+	}
+
+	*field = string(plaintext)
+
+	return nil
+}
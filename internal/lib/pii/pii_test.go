@@ -0,0 +1,110 @@
+package pii
+
+import (
+	"encoding/base64"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+var (
+	key1 = base64.StdEncoding.EncodeToString([]byte(strings.Repeat("1", keySize)))
+	key2 = base64.StdEncoding.EncodeToString([]byte(strings.Repeat("2", keySize)))
+)
+
+func newTestCipher(t *testing.T, keys map[string]string, current string) *Cipher {
+	t.Helper()
+
+	kms, err := NewLocalKMS(keys, current)
+	require.NoError(t, err)
+
+	c, err := NewCipher(kms, "index-key")
+	require.NoError(t, err)
+
+	return c
+}
+
+func TestCipher_RoundTrip(t *testing.T) {
+	c := newTestCipher(t, map[string]string{"1": key1}, "1")
+
+	user := models.User{Email: "john@example.com", Name: "John", Surname: "Doe"}
+
+	require.NoError(t, c.Encrypt(&user))
+	require.NotNil(t, user.DataKey)
+	assert.True(t, IsEncrypted(user.Email))
+	assert.True(t, IsEncrypted(user.Name))
+	assert.Empty(t, user.PhoneNumber)
+
+	require.NoError(t, c.Decrypt(&user))
+	assert.Equal(t, "john@example.com", user.Email)
+	assert.Equal(t, "John", user.Name)
+	assert.Equal(t, "Doe", user.Surname)
+}
+
+func TestCipher_SwappedFieldsAreRejected(t *testing.T) {
+	c := newTestCipher(t, map[string]string{"1": key1}, "1")
+
+	user := models.User{Name: "John", Surname: "Doe"}
+	require.NoError(t, c.Encrypt(&user))
+
+	user.Name, user.Surname = user.Surname, user.Name
+
+	require.Error(t, c.Decrypt(&user))
+}
+
+func TestCipher_PlaintextIsReadable(t *testing.T) {
+	c := newTestCipher(t, map[string]string{"1": key1}, "1")
+
+	user := models.User{Email: "john@example.com"}
+	require.NoError(t, c.Decrypt(&user))
+	assert.Equal(t, "john@example.com", user.Email)
+}
+
+func TestCipher_Reencrypt(t *testing.T) {
+	old := newTestCipher(t, map[string]string{"1": key1}, "1")
+
+	user := models.User{Email: "john@example.com"}
+	require.NoError(t, old.Encrypt(&user))
+
+	rotated := newTestCipher(t, map[string]string{"1": key1, "2": key2}, "2")
+	require.NoError(t, rotated.Reencrypt(&user))
+	assert.Equal(t, "2", user.DataKey.KeyID)
+
+	retired := newTestCipher(t, map[string]string{"2": key2}, "2")
+	require.NoError(t, retired.Decrypt(&user))
+	assert.Equal(t, "john@example.com", user.Email)
+}
+
+func TestCipher_ReencryptEmailChange(t *testing.T) {
+	old := newTestCipher(t, map[string]string{"1": key1}, "1")
+
+	user := models.User{
+		Email:       "john@example.com",
+		EmailChange: &models.EmailChange{Email: "new@example.com", PreviousEmail: "old@example.com"},
+	}
+	require.NoError(t, old.Encrypt(&user))
+	require.NoError(t, old.EncryptEmailChange(&user))
+	assert.True(t, IsEncrypted(user.EmailChange.Email))
+	assert.True(t, IsEncrypted(user.EmailChange.PreviousEmail))
+
+	previous := user
+
+	rotated := newTestCipher(t, map[string]string{"1": key1, "2": key2}, "2")
+	require.NoError(t, rotated.Reencrypt(&user))
+	assert.NotEqual(t, previous.EmailChange.Email, user.EmailChange.Email, "the previous copy is kept")
+
+	retired := newTestCipher(t, map[string]string{"2": key2}, "2")
+	require.NoError(t, retired.DecryptEmailChange(&user))
+	assert.Equal(t, "new@example.com", user.EmailChange.Email)
+	assert.Equal(t, "old@example.com", user.EmailChange.PreviousEmail)
+}
+
+func TestCipher_BlindIndex(t *testing.T) {
+	c := newTestCipher(t, map[string]string{"1": key1}, "1")
+
+	assert.Equal(t, c.BlindIndex("john@example.com"), c.BlindIndex("john@example.com"))
+	assert.NotEqual(t, c.BlindIndex("john@example.com"), c.BlindIndex("jane@example.com"))
+	assert.NotContains(t, c.BlindIndex("john@example.com"), "john")
+}
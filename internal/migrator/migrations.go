@@ -0,0 +1,546 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/email"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/phone"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/pii"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+// All returns every migration of the service database in the order they are applied.
+func All() []Migration {
+	return []Migration{
+		{
+			Version:     1,
+			Description: "create unique index on user email",
+			Up:          createIndex(config.UserCollection, "email_unique", bson.D{{Key: "email", Value: 1}}, true),
+			Down:        dropIndex(config.UserCollection, "email_unique"),
+		},
+		{
+			Version:     2,
+			Description: "create unique index on user id",
+			Up:          createIndex(config.UserCollection, "user_id_unique", bson.D{{Key: "user_id", Value: 1}}, true),
+			Down:        dropIndex(config.UserCollection, "user_id_unique"),
+		},
+		{
+			Version:     3,
+			Description: "seed user id sequence counter",
+			Up:          seedUserSequence,
+			Down:        removeUserSequence,
+		},
+		{
+			Version:     4,
+			Description: "replace null family lists with empty arrays",
+			Up:          normalizeFamilyIDs,
+			Down:        noop,
+		},
+		{
+			Version:     5,
+			Description: "create session indexes",
+			Up: func(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+				if err := createIndex(config.SessionCollection, "session_id_unique",
+					bson.D{{Key: "session_id", Value: 1}}, true)(ctx, db, cfg); err != nil {
+					return err
+				}
+				return createIndex(config.SessionCollection, "user_id",
+					bson.D{{Key: "user_id", Value: 1}}, false)(ctx, db, cfg)
+			},
+			Down: func(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+				if err := dropIndex(config.SessionCollection, "user_id")(ctx, db, cfg); err != nil {
+					return err
+				}
+				return dropIndex(config.SessionCollection, "session_id_unique")(ctx, db, cfg)
+			},
+		},
+		{
+			Version:     6,
+			Description: "backfill normalized user emails",
+			Up:          backfillNormalizedEmails,
+			Down:        noop,
+		},
+		{
+			Version:     7,
+			Description: "enforce uniqueness on normalized email instead of raw email",
+			Up: func(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+				if err := createIndex(config.UserCollection, "email_normalized_unique",
+					bson.D{{Key: "email_normalized", Value: 1}}, true)(ctx, db, cfg); err != nil {
+					return err
+				}
+				return dropIndex(config.UserCollection, "email_unique")(ctx, db, cfg)
+			},
+			Down: func(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+				if err := createIndex(config.UserCollection, "email_unique",
+					bson.D{{Key: "email", Value: 1}}, true)(ctx, db, cfg); err != nil {
+					return err
+				}
+				return dropIndex(config.UserCollection, "email_normalized_unique")(ctx, db, cfg)
+			},
+		},
+		{
+			Version:     8,
+			Description: "create index for purging soft-deleted users",
+			Up: createIndex(config.UserCollection, "deleted_at",
+				bson.D{{Key: "deleted_at", Value: 1}}, false),
+			Down: dropIndex(config.UserCollection, "deleted_at"),
+		},
+		{
+			Version:     9,
+			Description: "create login history indexes",
+			Up: func(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+				if err := createIndex(config.LoginHistoryCollection, "user_id_created_at",
+					bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}, false)(ctx, db, cfg); err != nil {
+					return err
+				}
+				return createTTLIndex(config.LoginHistoryCollection, "created_at_ttl",
+					"created_at", cfg.LoginHistory.Retention)(ctx, db, cfg)
+			},
+			Down: func(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+				if err := dropIndex(config.LoginHistoryCollection, "created_at_ttl")(ctx, db, cfg); err != nil {
+					return err
+				}
+				return dropIndex(config.LoginHistoryCollection, "user_id_created_at")(ctx, db, cfg)
+			},
+		},
+		{
+			Version:     10,
+			Description: "backfill user versions for optimistic concurrency",
+			Up:          backfillUserVersions,
+			Down:        noop,
+		},
+		{
+			Version:     11,
+			Description: "create email change token indexes",
+			Up: func(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+				if err := createIndex(config.UserCollection, "email_change_confirm_token",
+					bson.D{{Key: "email_change.confirm_token_hash", Value: 1}}, false)(ctx, db, cfg); err != nil {
+					return err
+				}
+				return createIndex(config.UserCollection, "email_change_cancel_token",
+					bson.D{{Key: "email_change.cancel_token_hash", Value: 1}}, false)(ctx, db, cfg)
+			},
+			Down: func(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+				if err := dropIndex(config.UserCollection, "email_change_cancel_token")(ctx, db, cfg); err != nil {
+					return err
+				}
+				return dropIndex(config.UserCollection, "email_change_confirm_token")(ctx, db, cfg)
+			},
+		},
+		{
+			Version:     12,
+			Description: "format user phone numbers in E.164",
+			Up:          backfillPhoneNumbers,
+			Down:        noop,
+		},
+		{
+			Version:     13,
+			Description: "index verified phone numbers for sign-in",
+			Up: func(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+				if err := backfillNormalizedPhones(ctx, db, cfg); err != nil {
+					return err
+				}
+				return createPartialUniqueIndex(config.UserCollection, "phone_normalized_verified_unique",
+					bson.D{{Key: "phone_normalized", Value: 1}},
+					bson.D{{Key: "phone_verified", Value: true}})(ctx, db, cfg)
+			},
+			Down: dropIndex(config.UserCollection, "phone_normalized_verified_unique"),
+		},
+		{
+			Version:     14,
+			Description: "create avatar file index",
+			Up: createBucketIndex(config.AvatarBucket, "avatar_user_upload_size",
+				bson.D{
+					{Key: "metadata.user_id", Value: 1},
+					{Key: "metadata.upload_id", Value: 1},
+					{Key: "metadata.size", Value: 1},
+				}),
+			Down: dropBucketIndex(config.AvatarBucket, "avatar_user_upload_size"),
+		},
+		{
+			Version:     15,
+			Description: "create audit indexes",
+			Up: func(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+				if err := createIndex(config.AuditCollection, "user_id_created_at",
+					bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}, false)(ctx, db, cfg); err != nil {
+					return err
+				}
+				return createIndex(config.AuditCollection, "actor_id_created_at",
+					bson.D{{Key: "actor_id", Value: 1}, {Key: "created_at", Value: -1}}, false)(ctx, db, cfg)
+			},
+			Down: func(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+				if err := dropIndex(config.AuditCollection, "actor_id_created_at")(ctx, db, cfg); err != nil {
+					return err
+				}
+				return dropIndex(config.AuditCollection, "user_id_created_at")(ctx, db, cfg)
+			},
+		},
+		{
+			Version:     16,
+			Description: "create access token indexes",
+			Up: func(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+				if err := createIndex(config.AccessTokenCollection, "token_id_unique",
+					bson.D{{Key: "token_id", Value: 1}}, true)(ctx, db, cfg); err != nil {
+					return err
+				}
+				if err := createIndex(config.AccessTokenCollection, "hash_unique",
+					bson.D{{Key: "hash", Value: 1}}, true)(ctx, db, cfg); err != nil {
+					return err
+				}
+				return createIndex(config.AccessTokenCollection, "user_id_created_at",
+					bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}, false)(ctx, db, cfg)
+			},
+			Down: func(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+				if err := dropIndex(config.AccessTokenCollection, "user_id_created_at")(ctx, db, cfg); err != nil {
+					return err
+				}
+				if err := dropIndex(config.AccessTokenCollection, "hash_unique")(ctx, db, cfg); err != nil {
+					return err
+				}
+				return dropIndex(config.AccessTokenCollection, "token_id_unique")(ctx, db, cfg)
+			},
+		},
+		{
+			Version:     17,
+			Description: "rebuild normalized email index without collation",
+			Up: func(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+				if err := dropCollatedIndex(config.UserCollection, "email_normalized_unique")(ctx, db, cfg); err != nil {
+					return err
+				}
+				return createIndex(config.UserCollection, "email_normalized_unique",
+					bson.D{{Key: "email_normalized", Value: 1}}, true)(ctx, db, cfg)
+			},
+			Down: noop,
+		},
+	}
+}
+
+func createIndex(
+	collection, name string,
+	keys bson.D,
+	unique bool,
+) func(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+	return func(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+		_, err := db.Collection(cfg.Mongo.Collections[collection]).Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    keys,
+			Options: options.Index().SetName(name).SetUnique(unique),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create index %s: %w", name, err)
+		}
+
+		return nil
+	}
+}
+
+func createPartialUniqueIndex(
+	collection, name string,
+	keys, partialFilter bson.D,
+) func(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+	return func(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+		_, err := db.Collection(cfg.Mongo.Collections[collection]).Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    keys,
+			Options: options.Index().SetName(name).SetUnique(true).SetPartialFilterExpression(partialFilter),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create index %s: %w", name, err)
+		}
+
+		return nil
+	}
+}
+
+// dropCollatedIndex drops the index with the provided name if it was built with a
+// collation. Queries that do not specify the same collation cannot use such an
+// index.
+func dropCollatedIndex(collection, name string) func(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+	return func(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+		cur, err := db.Collection(cfg.Mongo.Collections[collection]).Indexes().List(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list indexes: %w", err)
+		}
+
+		var indexes []bson.M
+		if err = cur.All(ctx, &indexes); err != nil {
+			return fmt.Errorf("failed to decode indexes: %w", err)
+		}
+
+		for _, index := range indexes {
+			if index["name"] != name {
+				continue
+			}
+			if _, collated := index["collation"]; !collated {
+				return nil
+			}
+			return dropIndex(collection, name)(ctx, db, cfg)
+		}
+
+		return nil
+	}
+}
+
+func createTTLIndex(
+	collection, name, field string,
+	ttl time.Duration,
+) func(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+	return func(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+		_, err := db.Collection(cfg.Mongo.Collections[collection]).Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: field, Value: 1}},
+			Options: options.Index().SetName(name).SetExpireAfterSeconds(int32(ttl.Seconds())),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create index %s: %w", name, err)
+		}
+
+		return nil
+	}
+}
+
+func dropIndex(
+	collection, name string,
+) func(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+	return func(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+		if _, err := db.Collection(cfg.Mongo.Collections[collection]).Indexes().DropOne(ctx, name); err != nil {
+			return fmt.Errorf("failed to drop index %s: %w", name, err)
+		}
+
+		return nil
+	}
+}
+
+// createBucketIndex creates an index on the files collection of the GridFS bucket.
+func createBucketIndex(
+	bucket, name string,
+	keys bson.D,
+) func(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+	return func(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+		_, err := db.Collection(cfg.Mongo.Collections[bucket]+".files").Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    keys,
+			Options: options.Index().SetName(name),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create index %s: %w", name, err)
+		}
+
+		return nil
+	}
+}
+
+func dropBucketIndex(
+	bucket, name string,
+) func(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+	return func(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+		if _, err := db.Collection(cfg.Mongo.Collections[bucket]+".files").Indexes().DropOne(ctx, name); err != nil {
+			return fmt.Errorf("failed to drop index %s: %w", name, err)
+		}
+
+		return nil
+	}
+}
+
+// seedUserSequence creates the sequence document used to generate user IDs if it
+// does not exist yet. The counter starts right after the greatest existing user ID.
+func seedUserSequence(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+	var last models.User
+
+	nextID := int64(1)
+
+	res := db.Collection(cfg.Mongo.Collections[config.UserCollection]).FindOne(ctx, bson.D{},
+		options.FindOne().SetSort(bson.D{{Key: "user_id", Value: -1}}))
+	switch err := res.Err(); {
+	case errors.Is(err, mongo.ErrNoDocuments):
+	case err != nil:
+		return fmt.Errorf("failed to find the last user: %w", err)
+	default:
+		if err = res.Decode(&last); err != nil {
+			return fmt.Errorf("failed to decode the last user: %w", err)
+		}
+		nextID = last.ID + 1
+	}
+
+	filter := bson.D{
+		{Key: "collection_name", Value: cfg.Mongo.Collections[config.UserCollection]},
+	}
+
+	update := bson.M{
+		"$setOnInsert": bson.M{"counter": nextID},
+	}
+
+	_, err := db.Collection(cfg.Mongo.Collections[config.SequenceCollection]).
+		UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to seed user sequence: %w", err)
+	}
+
+	return nil
+}
+
+func removeUserSequence(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+	filter := bson.D{
+		{Key: "collection_name", Value: cfg.Mongo.Collections[config.UserCollection]},
+	}
+
+	if _, err := db.Collection(cfg.Mongo.Collections[config.SequenceCollection]).DeleteOne(ctx, filter); err != nil {
+		return fmt.Errorf("failed to remove user sequence: %w", err)
+	}
+
+	return nil
+}
+
+func normalizeFamilyIDs(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+	filter := bson.D{
+		{Key: "family_ids", Value: nil},
+	}
+
+	update := bson.M{
+		"$set": bson.M{"family_ids": bson.A{}},
+	}
+
+	if _, err := db.Collection(cfg.Mongo.Collections[config.UserCollection]).UpdateMany(ctx, filter, update); err != nil {
+		return fmt.Errorf("failed to normalize family lists: %w", err)
+	}
+
+	return nil
+}
+
+// backfillNormalizedEmails stores the canonical and normalized form of the email
+// of every user that does not have a normalized email yet.
+func backfillNormalizedEmails(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+	coll := db.Collection(cfg.Mongo.Collections[config.UserCollection])
+	normalizer := email.NewNormalizer(cfg.Email.ProviderRules)
+
+	filter := bson.D{
+		{Key: "email_normalized", Value: bson.M{"$in": bson.A{nil, ""}}},
+	}
+
+	cur, err := coll.Find(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to find users: %w", err)
+	}
+	defer func() {
+		_ = cur.Close(ctx)
+	}()
+
+	for cur.Next(ctx) {
+		var user models.User
+		if err = cur.Decode(&user); err != nil {
+			return fmt.Errorf("failed to decode user: %w", err)
+		}
+
+		update := bson.M{
+			"$set": bson.M{
+				"email":            normalizer.Canonical(user.Email),
+				"email_normalized": normalizer.Normalize(user.Email),
+			},
+		}
+
+		if _, err = coll.UpdateOne(ctx, bson.D{{Key: "user_id", Value: user.ID}}, update); err != nil {
+			return fmt.Errorf("failed to update user %d: %w", user.ID, err)
+		}
+	}
+
+	if err = cur.Err(); err != nil {
+		return fmt.Errorf("failed to iterate users: %w", err)
+	}
+
+	return nil
+}
+
+func backfillUserVersions(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+	coll := db.Collection(cfg.Mongo.Collections[config.UserCollection])
+
+	filter := bson.D{
+		{Key: "version", Value: bson.M{"$exists": false}},
+	}
+
+	update := bson.M{
+		"$set": bson.M{"version": 0},
+	}
+
+	if _, err := coll.UpdateMany(ctx, filter, update); err != nil {
+		return fmt.Errorf("failed to backfill user versions: %w", err)
+	}
+
+	return nil
+}
+
+// backfillPhoneNumbers formats the stored phone numbers in E.164. Numbers that
+// cannot be parsed and encrypted numbers are left as they are.
+func backfillPhoneNumbers(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+	coll := db.Collection(cfg.Mongo.Collections[config.UserCollection])
+	normalizer := phone.NewNormalizer(cfg.Phone.DefaultRegion)
+
+	filter := bson.D{
+		{Key: "phone_number", Value: bson.M{"$nin": bson.A{nil, ""}}},
+	}
+
+	cur, err := coll.Find(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to find users: %w", err)
+	}
+	defer func() {
+		_ = cur.Close(ctx)
+	}()
+
+	for cur.Next(ctx) {
+		var user models.User
+		if err = cur.Decode(&user); err != nil {
+			return fmt.Errorf("failed to decode user: %w", err)
+		}
+
+		if pii.IsEncrypted(user.PhoneNumber) {
+			continue
+		}
+
+		phoneNumber, normalizeErr := normalizer.Normalize(user.PhoneNumber)
+		if normalizeErr != nil || phoneNumber == user.PhoneNumber {
+			continue
+		}
+
+		update := bson.M{
+			"$set": bson.M{"phone_number": phoneNumber},
+		}
+
+		if _, err = coll.UpdateOne(ctx, bson.D{{Key: "user_id", Value: user.ID}}, update); err != nil {
+			return fmt.Errorf("failed to update user %d: %w", user.ID, err)
+		}
+	}
+
+	if err = cur.Err(); err != nil {
+		return fmt.Errorf("failed to iterate users: %w", err)
+	}
+
+	return nil
+}
+
+// backfillNormalizedPhones copies phone numbers formatted in E.164 to the normalized
+// phone number used for sign-in. Encrypted numbers get their blind index from the
+// rotate-keys command.
+func backfillNormalizedPhones(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+	coll := db.Collection(cfg.Mongo.Collections[config.UserCollection])
+
+	filter := bson.D{
+		{Key: "phone_number", Value: bson.M{"$regex": `^\+[0-9]+$`}},
+		{Key: "phone_normalized", Value: bson.M{"$exists": false}},
+	}
+
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{"phone_normalized": "$phone_number"}}},
+	}
+
+	if _, err := coll.UpdateMany(ctx, filter, update); err != nil {
+		return fmt.Errorf("failed to backfill normalized phone numbers: %w", err)
+	}
+
+	return nil
+}
+
+func noop(context.Context, *mongo.Database, *config.Config) error {
+	return nil
+}
@@ -0,0 +1,240 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+var (
+	ErrNoDown            = errors.New("migration cannot be reverted")
+	ErrPendingMigrations = errors.New("database has pending migrations")
+)
+
+// Migration is a single versioned change of the database schema or data.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, db *mongo.Database, cfg *config.Config) error
+	Down        func(ctx context.Context, db *mongo.Database, cfg *config.Config) error
+}
+
+// record is the document stored for every applied migration.
+type record struct {
+	Version     int       `bson:"version"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"applied_at"`
+}
+
+type Migrator struct {
+	log        *slog.Logger
+	db         *mongo.Database
+	cfg        *config.Config
+	migrations []Migration
+	dryRun     bool
+}
+
+// New creates and returns a new instance of the Migrator for the provided database.
+// In dry-run mode the migrator only logs the migrations it would apply or revert.
+func New(
+	log *slog.Logger,
+	client *mongo.Client,
+	cfg *config.Config,
+	migrations []Migration,
+	dryRun bool,
+) *Migrator {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+
+	return &Migrator{
+		log:        log,
+		db:         client.Database(cfg.Mongo.DBName),
+		cfg:        cfg,
+		migrations: sorted,
+		dryRun:     dryRun,
+	}
+}
+
+// Up applies every pending migration with a version up to the target version.
+// A target of 0 applies all pending migrations.
+func (m *Migrator) Up(ctx context.Context, target int) error {
+	const op = "migrator.Up"
+
+	log := m.log.With(slog.String("op", op), slog.Bool("dry_run", m.dryRun))
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, migration := range m.migrations {
+		if target > 0 && migration.Version > target {
+			break
+		}
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		log.Info("applying migration",
+			slog.Int("version", migration.Version),
+			slog.String("description", migration.Description))
+
+		if m.dryRun {
+			continue
+		}
+
+		if err = migration.Up(ctx, m.db, m.cfg); err != nil {
+			return fmt.Errorf("%s: migration %d: %w", op, migration.Version, err)
+		}
+
+		if _, err = m.collection().InsertOne(ctx, record{
+			Version:     migration.Version,
+			Description: migration.Description,
+			AppliedAt:   time.Now().UTC(),
+		}); err != nil {
+			return fmt.Errorf("%s: failed to record migration %d: %w", op, migration.Version, err)
+		}
+	}
+
+	log.Info("migrations are up to date")
+
+	return nil
+}
+
+// Down reverts the provided number of most recently applied migrations.
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	const op = "migrator.Down"
+
+	log := m.log.With(slog.String("op", op), slog.Bool("dry_run", m.dryRun))
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for i := len(m.migrations) - 1; i >= 0 && steps > 0; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		steps--
+
+		log.Info("reverting migration",
+			slog.Int("version", migration.Version),
+			slog.String("description", migration.Description))
+
+		if migration.Down == nil {
+			return fmt.Errorf("%s: migration %d: %w", op, migration.Version, ErrNoDown)
+		}
+
+		if m.dryRun {
+			continue
+		}
+
+		if err = migration.Down(ctx, m.db, m.cfg); err != nil {
+			return fmt.Errorf("%s: migration %d: %w", op, migration.Version, err)
+		}
+
+		if _, err = m.collection().DeleteOne(ctx, bson.D{{Key: "version", Value: migration.Version}}); err != nil {
+			return fmt.Errorf("%s: failed to remove migration record %d: %w", op, migration.Version, err)
+		}
+	}
+
+	return nil
+}
+
+// Status logs every known migration along with the time it was applied.
+func (m *Migrator) Status(ctx context.Context) error {
+	const op = "migrator.Status"
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, migration := range m.migrations {
+		attrs := []any{
+			slog.Int("version", migration.Version),
+			slog.String("description", migration.Description),
+		}
+
+		if rec, ok := applied[migration.Version]; ok {
+			attrs = append(attrs, slog.Time("applied_at", rec.AppliedAt))
+			m.log.Info("applied", attrs...)
+		} else {
+			m.log.Info("pending", attrs...)
+		}
+	}
+
+	return nil
+}
+
+// Pending returns the versions of the migrations that are not applied yet.
+func (m *Migrator) Pending(ctx context.Context) ([]int, error) {
+	const op = "migrator.Pending"
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var pending []int
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration.Version)
+		}
+	}
+
+	return pending, nil
+}
+
+// CheckUpToDate returns ErrPendingMigrations if any known migration is not applied,
+// so the service never serves a database missing the indexes it relies on.
+func (m *Migrator) CheckUpToDate(ctx context.Context) error {
+	const op = "migrator.CheckUpToDate"
+
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(pending) > 0 {
+		return fmt.Errorf("%s: %w: %v, run the migrate command", op, ErrPendingMigrations, pending)
+	}
+
+	return nil
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[int]record, error) {
+	var records []record
+
+	cur, err := m.collection().Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{Key: "version", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+
+	if err = cur.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("failed to decode applied migrations: %w", err)
+	}
+
+	applied := make(map[int]record, len(records))
+	for _, rec := range records {
+		applied[rec.Version] = rec
+	}
+
+	return applied, nil
+}
+
+func (m *Migrator) collection() *mongo.Collection {
+	return m.db.Collection(m.cfg.Mongo.Collections[config.MigrationCollection])
+}
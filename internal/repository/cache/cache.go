@@ -0,0 +1,350 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/repository"
+	"log/slog"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Stats is a snapshot of the cache metrics.
+type Stats struct {
+	LocalHits     int64
+	RemoteHits    int64
+	Misses        int64
+	Invalidations int64
+	Errors        int64
+}
+
+type metrics struct {
+	localHits     atomic.Int64
+	remoteHits    atomic.Int64
+	misses        atomic.Int64
+	invalidations atomic.Int64
+	errors        atomic.Int64
+}
+
+// CachedRepository is a read-through cache over repository.Repository. User lookups
+// by ID are served from the in-process LRU tier first, then from the optional remote
+// (Redis) tier, and only then from the underlying repository. Every write path that
+// changes a user invalidates the cached entry in both tiers.
+//
+// The local tier cannot be invalidated by writes made through other instances of the
+// service, so its TTL bounds how stale a local entry can be and should be kept short.
+//
+// A lookup that reads the user before a concurrent write and stores it after the
+// write's invalidation caches the old user until the TTL expires, so the cache is
+// disabled by default and should only be enabled where such staleness is acceptable.
+type CachedRepository struct {
+	repository.Repository
+	log       *slog.Logger
+	local     Store
+	remote    Store
+	localTTL  time.Duration
+	remoteTTL time.Duration
+	metrics   metrics
+}
+
+// New creates and returns a new instance of the CachedRepository. Either tier may be
+// nil, in which case it is skipped.
+func New(
+	log *slog.Logger,
+	repo repository.Repository,
+	local Store,
+	localTTL time.Duration,
+	remote Store,
+	remoteTTL time.Duration,
+) *CachedRepository {
+	return &CachedRepository{
+		Repository: repo,
+		log:        log,
+		local:      local,
+		remote:     remote,
+		localTTL:   localTTL,
+		remoteTTL:  remoteTTL,
+	}
+}
+
+// Stats returns a snapshot of the cache hit, miss and invalidation counters.
+func (c *CachedRepository) Stats() Stats {
+	return Stats{
+		LocalHits:     c.metrics.localHits.Load(),
+		RemoteHits:    c.metrics.remoteHits.Load(),
+		Misses:        c.metrics.misses.Load(),
+		Invalidations: c.metrics.invalidations.Load(),
+		Errors:        c.metrics.errors.Load(),
+	}
+}
+
+// GetUserInfo returns the user with the provided user ID from the cache, loading it
+// from the underlying repository on a miss.
+func (c *CachedRepository) GetUserInfo(ctx context.Context, userID int64) (models.User, error) {
+	return c.getUser(ctx, userID)
+}
+
+// IsAdmin checks the role of the cached user with the provided user ID.
+func (c *CachedRepository) IsAdmin(ctx context.Context, userID int64) (bool, error) {
+	user, err := c.getUser(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return user.Role == models.AdminRole, nil
+}
+
+func (c *CachedRepository) UpdateUserInfo(
+	ctx context.Context,
+	userID int64,
+	updatedUser *models.User,
+	fields []string,
+	expectedVersion *int64) error {
+	defer c.invalidate(ctx, userID)
+	return c.Repository.UpdateUserInfo(ctx, userID, updatedUser, fields, expectedVersion)
+}
+
+func (c *CachedRepository) UpdatePassHash(
+	ctx context.Context,
+	userID int64,
+	passHash string,
+	pepperVersion int) error {
+	defer c.invalidate(ctx, userID)
+	return c.Repository.UpdatePassHash(ctx, userID, passHash, pepperVersion)
+}
+
+func (c *CachedRepository) UpdateLastLogin(ctx context.Context, userID int64, at time.Time) error {
+	defer c.invalidate(ctx, userID)
+	return c.Repository.UpdateLastLogin(ctx, userID, at)
+}
+
+func (c *CachedRepository) ChangePassword(
+	ctx context.Context,
+	userID int64,
+	newPasswordHash string,
+	pepperVersion, historySize int) error {
+	defer c.invalidate(ctx, userID)
+	return c.Repository.ChangePassword(ctx, userID, newPasswordHash, pepperVersion, historySize)
+}
+
+func (c *CachedRepository) AddFamily(ctx context.Context, userID, familyID int64) error {
+	defer c.invalidate(ctx, userID)
+	return c.Repository.AddFamily(ctx, userID, familyID)
+}
+
+func (c *CachedRepository) DeleteFamily(ctx context.Context, userID, familyID int64) error {
+	defer c.invalidate(ctx, userID)
+	return c.Repository.DeleteFamily(ctx, userID, familyID)
+}
+
+func (c *CachedRepository) DeleteUser(ctx context.Context, userID int64) error {
+	defer c.invalidate(ctx, userID)
+	return c.Repository.DeleteUser(ctx, userID)
+}
+
+func (c *CachedRepository) RestoreUser(ctx context.Context, userID int64, deletedAfter time.Time) error {
+	defer c.invalidate(ctx, userID)
+	return c.Repository.RestoreUser(ctx, userID, deletedAfter)
+}
+
+func (c *CachedRepository) SetStatus(
+	ctx context.Context,
+	userID int64,
+	status models.AccountStatus,
+	reason string,
+	until *time.Time) error {
+	defer c.invalidate(ctx, userID)
+	return c.Repository.SetStatus(ctx, userID, status, reason, until)
+}
+
+func (c *CachedRepository) SetEmailChange(
+	ctx context.Context,
+	userID int64,
+	change *models.EmailChange) error {
+	defer c.invalidate(ctx, userID)
+	return c.Repository.SetEmailChange(ctx, userID, change)
+}
+
+func (c *CachedRepository) ConfirmEmailChange(ctx context.Context, tokenHash string, now time.Time) (int64, error) {
+	userID, err := c.Repository.ConfirmEmailChange(ctx, tokenHash, now)
+	if err == nil {
+		c.invalidate(ctx, userID)
+	}
+	return userID, err
+}
+
+func (c *CachedRepository) CancelEmailChange(ctx context.Context, tokenHash string, now time.Time) (int64, error) {
+	userID, err := c.Repository.CancelEmailChange(ctx, tokenHash, now)
+	if err == nil {
+		c.invalidate(ctx, userID)
+	}
+	return userID, err
+}
+
+func (c *CachedRepository) SetPhoneVerification(
+	ctx context.Context,
+	userID int64,
+	verification *models.PhoneVerification) error {
+	defer c.invalidate(ctx, userID)
+	return c.Repository.SetPhoneVerification(ctx, userID, verification)
+}
+
+func (c *CachedRepository) UsePhoneVerificationAttempt(
+	ctx context.Context,
+	userID int64,
+	now time.Time,
+	maxAttempts int) (models.PhoneVerification, error) {
+	defer c.invalidate(ctx, userID)
+	return c.Repository.UsePhoneVerificationAttempt(ctx, userID, now, maxAttempts)
+}
+
+func (c *CachedRepository) ConfirmPhone(ctx context.Context, userID int64, codeHash string) error {
+	defer c.invalidate(ctx, userID)
+	return c.Repository.ConfirmPhone(ctx, userID, codeHash)
+}
+
+func (c *CachedRepository) SetAttributes(ctx context.Context, userID int64, attrs []models.Attribute) error {
+	defer c.invalidate(ctx, userID)
+	return c.Repository.SetAttributes(ctx, userID, attrs)
+}
+
+func (c *CachedRepository) SaveAvatar(
+	ctx context.Context,
+	userID int64,
+	avatar *models.Avatar,
+	images map[int][]byte) error {
+	defer c.invalidate(ctx, userID)
+	return c.Repository.SaveAvatar(ctx, userID, avatar, images)
+}
+
+func (c *CachedRepository) DeleteAvatar(ctx context.Context, userID int64) error {
+	defer c.invalidate(ctx, userID)
+	return c.Repository.DeleteAvatar(ctx, userID)
+}
+
+func (c *CachedRepository) PurgeUser(ctx context.Context, userID int64) error {
+	defer c.invalidate(ctx, userID)
+	return c.Repository.PurgeUser(ctx, userID)
+}
+
+// getUser looks the user up in the local tier, then in the remote tier, and finally
+// in the underlying repository, filling the tiers that missed. Failures of the
+// remote tier are logged and treated as misses.
+func (c *CachedRepository) getUser(ctx context.Context, userID int64) (models.User, error) {
+	const op = "cache.getUser"
+
+	log := c.log.With(
+		slog.String("op", op),
+	)
+
+	key := userKey(userID)
+
+	if user, ok := c.lookup(ctx, log, c.local, key); ok {
+		c.metrics.localHits.Add(1)
+		return user, nil
+	}
+
+	if user, ok := c.lookup(ctx, log, c.remote, key); ok {
+		c.metrics.remoteHits.Add(1)
+		c.store(ctx, log, c.local, key, user, c.localTTL)
+		return user, nil
+	}
+
+	c.metrics.misses.Add(1)
+
+	user, err := c.Repository.GetUserInfo(ctx, userID)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	c.store(ctx, log, c.remote, key, user, c.remoteTTL)
+	c.store(ctx, log, c.local, key, user, c.localTTL)
+
+	return user, nil
+}
+
+func (c *CachedRepository) lookup(
+	ctx context.Context,
+	log *slog.Logger,
+	tier Store,
+	key string) (models.User, bool) {
+	if tier == nil {
+		return models.User{}, false
+	}
+
+	raw, ok, err := tier.Get(ctx, key)
+	if err != nil {
+		c.metrics.errors.Add(1)
+		log.Warn("failed to read from cache", sl.Err(err))
+		return models.User{}, false
+	}
+	if !ok {
+		return models.User{}, false
+	}
+
+	var user models.User
+	if err = json.Unmarshal(raw, &user); err != nil {
+		c.metrics.errors.Add(1)
+		log.Warn("failed to decode cached user", sl.Err(err))
+		return models.User{}, false
+	}
+
+	return user, true
+}
+
+func (c *CachedRepository) store(
+	ctx context.Context,
+	log *slog.Logger,
+	tier Store,
+	key string,
+	user models.User,
+	ttl time.Duration) {
+	if tier == nil {
+		return
+	}
+
+	raw, err := json.Marshal(user)
+	if err != nil {
+		c.metrics.errors.Add(1)
+		log.Warn("failed to encode user", sl.Err(err))
+		return
+	}
+
+	if err = tier.Set(ctx, key, raw, ttl); err != nil {
+		c.metrics.errors.Add(1)
+		log.Warn("failed to write to cache", sl.Err(err))
+	}
+}
+
+// invalidate removes the cached user with the provided user ID from both tiers.
+// It runs after the write regardless of its outcome, since a failed write may
+// still have been applied.
+func (c *CachedRepository) invalidate(ctx context.Context, userID int64) {
+	const op = "cache.invalidate"
+
+	log := c.log.With(
+		slog.String("op", op),
+	)
+
+	key := userKey(userID)
+
+	c.metrics.invalidations.Add(1)
+
+	for _, tier := range []Store{c.local, c.remote} {
+		if tier == nil {
+			continue
+		}
+		if err := tier.Delete(ctx, key); err != nil {
+			c.metrics.errors.Add(1)
+			log.Error("failed to invalidate cached user",
+				slog.Int64("user_id", userID), sl.Err(err))
+		}
+	}
+}
+
+func userKey(userID int64) string {
+	return "user:" + strconv.FormatInt(userID, 10)
+}
@@ -0,0 +1,187 @@
+package cache
+
+import (
+	"context"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/repository"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+type fakeRepo struct {
+	repository.Repository
+	users map[int64]models.User
+	reads int
+}
+
+func (r *fakeRepo) GetUserInfo(_ context.Context, userID int64) (models.User, error) {
+	r.reads++
+	user, ok := r.users[userID]
+	if !ok {
+		return models.User{}, grpcerror.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (r *fakeRepo) UpdateUserInfo(
+	_ context.Context,
+	userID int64,
+	updatedUser *models.User,
+	_ []string,
+	_ *int64) error {
+	user := r.users[userID]
+	user.Name = updatedUser.Name
+	r.users[userID] = user
+	return nil
+}
+
+func (r *fakeRepo) AddFamily(_ context.Context, userID, familyID int64) error {
+	user := r.users[userID]
+	user.FamilyIDs = append(user.FamilyIDs, familyID)
+	r.users[userID] = user
+	return nil
+}
+
+func newTestCache(t *testing.T) (*CachedRepository, *fakeRepo, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	repo := &fakeRepo{users: map[int64]models.User{
+		1: {ID: 1, Name: "John", Role: models.UserRole},
+		2: {ID: 2, Name: "Admin", Role: models.AdminRole},
+	}}
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	c := New(log, repo,
+		NewLRUStore(10), time.Minute,
+		NewRedisStore(client, "sso:"), time.Hour)
+
+	return c, repo, mr
+}
+
+func TestCachedRepository_ReadThrough(t *testing.T) {
+	ctx := context.Background()
+	c, repo, mr := newTestCache(t)
+
+	user, err := c.GetUserInfo(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "John", user.Name)
+	assert.True(t, mr.Exists("sso:user:1"))
+
+	user, err = c.GetUserInfo(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "John", user.Name)
+
+	isAdmin, err := c.IsAdmin(ctx, 1)
+	require.NoError(t, err)
+	assert.False(t, isAdmin)
+
+	assert.Equal(t, 1, repo.reads)
+	assert.Equal(t, Stats{LocalHits: 2, Misses: 1}, c.Stats())
+}
+
+func TestCachedRepository_RemoteTier(t *testing.T) {
+	ctx := context.Background()
+	c, repo, _ := newTestCache(t)
+
+	_, err := c.GetUserInfo(ctx, 2)
+	require.NoError(t, err)
+
+	// Simulate another instance of the service with an empty local tier.
+	require.NoError(t, c.local.Delete(ctx, userKey(2)))
+
+	isAdmin, err := c.IsAdmin(ctx, 2)
+	require.NoError(t, err)
+	assert.True(t, isAdmin)
+
+	assert.Equal(t, 1, repo.reads)
+	assert.Equal(t, int64(1), c.Stats().RemoteHits)
+}
+
+func TestCachedRepository_Invalidation(t *testing.T) {
+	ctx := context.Background()
+	c, repo, mr := newTestCache(t)
+
+	_, err := c.GetUserInfo(ctx, 1)
+	require.NoError(t, err)
+
+	require.NoError(t, c.UpdateUserInfo(ctx, 1, &models.User{Name: "Jack"}, []string{models.FieldName}, nil))
+	assert.False(t, mr.Exists("sso:user:1"))
+
+	user, err := c.GetUserInfo(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Jack", user.Name)
+
+	require.NoError(t, c.AddFamily(ctx, 1, 42))
+
+	user, err = c.GetUserInfo(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{42}, user.FamilyIDs)
+
+	assert.Equal(t, 3, repo.reads)
+	assert.Equal(t, int64(2), c.Stats().Invalidations)
+}
+
+func TestCachedRepository_NotFoundIsNotCached(t *testing.T) {
+	ctx := context.Background()
+	c, repo, _ := newTestCache(t)
+
+	for i := 0; i < 2; i++ {
+		_, err := c.GetUserInfo(ctx, 404)
+		require.ErrorIs(t, err, grpcerror.ErrUserNotFound)
+	}
+
+	assert.Equal(t, 2, repo.reads)
+}
+
+func TestCachedRepository_RedisUnavailable(t *testing.T) {
+	ctx := context.Background()
+	c, repo, mr := newTestCache(t)
+
+	mr.Close()
+
+	user, err := c.GetUserInfo(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "John", user.Name)
+
+	_, err = c.GetUserInfo(ctx, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, repo.reads)
+	assert.Positive(t, c.Stats().Errors)
+}
+
+func TestLRUStore_EvictionAndExpiry(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	s := NewLRUStore(2)
+	s.now = func() time.Time { return now }
+
+	require.NoError(t, s.Set(ctx, "a", []byte("1"), time.Minute))
+	require.NoError(t, s.Set(ctx, "b", []byte("2"), time.Minute))
+
+	_, ok, _ := s.Get(ctx, "a")
+	require.True(t, ok)
+
+	require.NoError(t, s.Set(ctx, "c", []byte("3"), time.Minute))
+
+	_, ok, _ = s.Get(ctx, "b")
+	assert.False(t, ok, "least recently used entry must be evicted")
+
+	now = now.Add(2 * time.Minute)
+
+	_, ok, _ = s.Get(ctx, "a")
+	assert.False(t, ok, "expired entry must not be returned")
+}
@@ -0,0 +1,205 @@
+package encrypted
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/pii"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/repository"
+	"log/slog"
+)
+
+// EncryptedRepository encrypts the personal data of users before it is written to
+// the underlying repository and decrypts it after it is read, so that the services
+// only ever see plaintext. The normalized email is stored as a blind index.
+type EncryptedRepository struct {
+	repository.Repository
+	log    *slog.Logger
+	cipher *pii.Cipher
+}
+
+// New creates and returns a new instance of the EncryptedRepository.
+func New(log *slog.Logger, repo repository.Repository, cipher *pii.Cipher) *EncryptedRepository {
+	return &EncryptedRepository{
+		Repository: repo,
+		log:        log,
+		cipher:     cipher,
+	}
+}
+
+// GetUserByEmail looks the user up by the blind index of the normalized email. Users
+// that were not encrypted yet are looked up by the normalized email itself.
+func (r *EncryptedRepository) GetUserByEmail(ctx context.Context, normalizedEmail string) (models.User, error) {
+	const op = "encrypted.GetUserByEmail"
+
+	return r.lookup(ctx, op, normalizedEmail, r.Repository.GetUserByEmail)
+}
+
+// GetUserByPhone looks the user up by the blind index of the normalized phone
+// number. Users that were not encrypted yet are looked up by the number itself.
+func (r *EncryptedRepository) GetUserByPhone(ctx context.Context, normalizedPhone string) (models.User, error) {
+	const op = "encrypted.GetUserByPhone"
+
+	return r.lookup(ctx, op, normalizedPhone, r.Repository.GetUserByPhone)
+}
+
+// lookup finds the user by the blind index of the provided normalized value or by
+// the value itself. Both queries always run, so that a miss takes as long as a hit
+// and the lookup does not reveal whether the user exists.
+func (r *EncryptedRepository) lookup(
+	ctx context.Context,
+	op, normalized string,
+	find func(ctx context.Context, normalized string) (models.User, error),
+) (models.User, error) {
+	user, err := find(ctx, r.cipher.BlindIndex(normalized))
+	plainUser, plainErr := find(ctx, normalized)
+	if errors.Is(err, grpcerror.ErrUserNotFound) {
+		user, err = plainUser, plainErr
+	}
+	if err != nil {
+		return models.User{}, err
+	}
+
+	if err = r.decrypt(op, &user); err != nil {
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
+// CreateUser encrypts the user and stores the blind indexes of its normalized email
+// and phone number. Users stored before encryption was enabled hold the normalized
+// email itself until rotate-keys has run, which the unique index cannot match with
+// a blind index, so that form is checked first. New users are never stored with
+// the plaintext form, so the check cannot race with another sign-up.
+func (r *EncryptedRepository) CreateUser(ctx context.Context, user *models.User) (int64, error) {
+	const op = "encrypted.CreateUser"
+
+	exists, err := r.Repository.EmailExists(ctx, user.EmailNormalized)
+	if err != nil {
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+	if exists {
+		return -1, grpcerror.ErrUserExists
+	}
+
+	encrypted := *user
+	encrypted.DataKey = nil
+	encrypted.EmailNormalized = r.cipher.BlindIndex(user.EmailNormalized)
+	if encrypted.PhoneNormalized != "" {
+		encrypted.PhoneNormalized = r.cipher.BlindIndex(user.PhoneNormalized)
+	}
+
+	if err := r.cipher.Encrypt(&encrypted); err != nil {
+		r.log.Error("failed to encrypt user", slog.String("op", op), sl.Err(err))
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := r.Repository.CreateUser(ctx, &encrypted)
+	if err != nil {
+		return -1, err
+	}
+
+	user.ID = encrypted.ID
+	user.Role = encrypted.Role
+	user.FamilyIDs = encrypted.FamilyIDs
+
+	return id, nil
+}
+
+func (r *EncryptedRepository) GetUserInfo(ctx context.Context, userID int64) (models.User, error) {
+	const op = "encrypted.GetUserInfo"
+
+	user, err := r.Repository.GetUserInfo(ctx, userID)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	if err = r.decrypt(op, &user); err != nil {
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
+// UpdateUserInfo encrypts the updated fields with the data key of the stored user.
+// Empty fields stay empty, so that clearing a field is not encrypted into a value.
+func (r *EncryptedRepository) UpdateUserInfo(
+	ctx context.Context,
+	userID int64,
+	updatedUser *models.User,
+	fields []string,
+	expectedVersion *int64) error {
+	const op = "encrypted.UpdateUserInfo"
+
+	stored, err := r.Repository.GetUserInfo(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	encrypted := *updatedUser
+	encrypted.DataKey = stored.DataKey
+	if encrypted.Email != "" {
+		encrypted.EmailNormalized = r.cipher.BlindIndex(updatedUser.EmailNormalized)
+	}
+	if encrypted.PhoneNormalized != "" {
+		encrypted.PhoneNormalized = r.cipher.BlindIndex(updatedUser.PhoneNormalized)
+	}
+
+	if err = r.cipher.Encrypt(&encrypted); err != nil {
+		r.log.Error("failed to encrypt user", slog.String("op", op), sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return r.Repository.UpdateUserInfo(ctx, userID, &encrypted, fields, expectedVersion)
+}
+
+// SetEmailChange encrypts the new email of the change as an email with the data key
+// of the stored user and stores the blind index of its normalized form, so that the
+// change can be applied by copying the values. Users without a data key get one.
+func (r *EncryptedRepository) SetEmailChange(
+	ctx context.Context,
+	userID int64,
+	change *models.EmailChange) error {
+	const op = "encrypted.SetEmailChange"
+
+	stored, err := r.Repository.GetUserInfo(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	user := models.User{
+		Email:   change.Email,
+		DataKey: stored.DataKey,
+	}
+
+	if err = r.cipher.Encrypt(&user); err != nil {
+		r.log.Error("failed to encrypt email change", slog.String("op", op), sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if stored.DataKey == nil {
+		if err = r.Repository.UpdateUserInfo(ctx, userID, &models.User{DataKey: user.DataKey}, nil, nil); err != nil {
+			return err
+		}
+	}
+
+	encrypted := *change
+	encrypted.Email = user.Email
+	encrypted.EmailNormalized = r.cipher.BlindIndex(change.EmailNormalized)
+
+	return r.Repository.SetEmailChange(ctx, userID, &encrypted)
+}
+
+func (r *EncryptedRepository) decrypt(op string, user *models.User) error {
+	if err := r.cipher.Decrypt(user); err != nil {
+		r.log.Error("failed to decrypt user",
+			slog.String("op", op), slog.Int64("user_id", user.ID), sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package encrypted
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/pii"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+type fakeRepo struct {
+	repository.Repository
+	emails  map[string]bool
+	users   map[string]models.User
+	created []models.User
+	queries int
+}
+
+func (r *fakeRepo) GetUserByEmail(_ context.Context, normalizedEmail string) (models.User, error) {
+	r.queries++
+	user, ok := r.users[normalizedEmail]
+	if !ok {
+		return models.User{}, grpcerror.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (r *fakeRepo) EmailExists(_ context.Context, normalizedEmail string) (bool, error) {
+	return r.emails[normalizedEmail], nil
+}
+
+func (r *fakeRepo) CreateUser(_ context.Context, user *models.User) (int64, error) {
+	if r.emails[user.EmailNormalized] {
+		return -1, grpcerror.ErrUserExists
+	}
+	r.emails[user.EmailNormalized] = true
+	r.created = append(r.created, *user)
+	return int64(len(r.created)), nil
+}
+
+func newTestRepository(t *testing.T, repo *fakeRepo) *EncryptedRepository {
+	t.Helper()
+
+	kms, err := pii.NewLocalKMS(map[string]string{
+		"1": base64.StdEncoding.EncodeToString([]byte(strings.Repeat("1", 32))),
+	}, "1")
+	require.NoError(t, err)
+
+	cipher, err := pii.NewCipher(kms, "index-key")
+	require.NoError(t, err)
+
+	return New(slog.New(slog.NewTextHandler(io.Discard, nil)), repo, cipher)
+}
+
+func TestCreateUser_PlaintextEmailExists(t *testing.T) {
+	repo := &fakeRepo{emails: map[string]bool{"john@example.com": true}}
+	r := newTestRepository(t, repo)
+	ctx := context.Background()
+
+	_, err := r.CreateUser(ctx, &models.User{Email: "John@example.com", EmailNormalized: "john@example.com"})
+	assert.True(t, errors.Is(err, grpcerror.ErrUserExists))
+	assert.Empty(t, repo.created)
+
+	_, err = r.CreateUser(ctx, &models.User{Email: "jane@example.com", EmailNormalized: "jane@example.com"})
+	require.NoError(t, err)
+	require.Len(t, repo.created, 1)
+	assert.NotEqual(t, "jane@example.com", repo.created[0].EmailNormalized)
+	assert.True(t, pii.IsEncrypted(repo.created[0].Email))
+}
+
+func TestGetUserByEmail_SameQueriesForHitAndMiss(t *testing.T) {
+	repo := &fakeRepo{users: map[string]models.User{}}
+	r := newTestRepository(t, repo)
+	ctx := context.Background()
+
+	repo.users[r.cipher.BlindIndex("john@example.com")] = models.User{ID: 1}
+	repo.users["jane@example.com"] = models.User{ID: 2}
+
+	tests := []struct {
+		name  string
+		email string
+		id    int64
+		err   error
+	}{
+		{"blind index", "john@example.com", 1, nil},
+		{"not encrypted yet", "jane@example.com", 2, nil},
+		{"unknown user", "jack@example.com", 0, grpcerror.ErrUserNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo.queries = 0
+
+			user, err := r.GetUserByEmail(ctx, tt.email)
+			assert.True(t, errors.Is(err, tt.err), err)
+			assert.Equal(t, tt.id, user.ID)
+			assert.Equal(t, 2, repo.queries)
+		})
+	}
+}
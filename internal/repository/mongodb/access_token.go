@@ -0,0 +1,223 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log/slog"
+	"time"
+)
+
+// CreateAccessToken stores a new personal access token in the MongoDB database.
+// It assigns a unique token ID and returns it.
+func (m *MongoRepository) CreateAccessToken(ctx context.Context, token *models.AccessToken) (string, error) {
+	const op = "access_token.mongo.CreateAccessToken"
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.AccessTokenCollection])
+
+	token.ID = primitive.NewObjectID().Hex()
+
+	if _, err := coll.InsertOne(ctx, token); err != nil {
+		log.Error("failed to insert access token", sl.Err(err))
+		return "", fmt.Errorf("failed to insert access token: %w", err)
+	}
+
+	return token.ID, nil
+}
+
+// CountAccessTokens returns how many tokens of the user with the provided user ID
+// are neither revoked nor expired at the provided time.
+func (m *MongoRepository) CountAccessTokens(ctx context.Context, userID int64, now time.Time) (int64, error) {
+	const op = "access_token.mongo.CountAccessTokens"
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.AccessTokenCollection])
+
+	filter := bson.D{
+		{Key: "user_id", Value: userID},
+		{Key: "revoked", Value: false},
+		{Key: "expires_at", Value: bson.M{"$gt": now}},
+	}
+
+	count, err := coll.CountDocuments(ctx, filter)
+	if err != nil {
+		log.Error("failed to count access tokens", sl.Err(err))
+		return 0, fmt.Errorf("failed to count access tokens: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetAccessTokens retrieves the tokens of the user with the provided user ID that
+// were not revoked, expired ones included, the most recently created tokens first.
+func (m *MongoRepository) GetAccessTokens(ctx context.Context, userID int64) ([]models.AccessToken, error) {
+	const op = "access_token.mongo.GetAccessTokens"
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.AccessTokenCollection])
+
+	filter := bson.D{
+		{Key: "user_id", Value: userID},
+		{Key: "revoked", Value: false},
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cur, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		log.Error("failed to search access tokens", sl.Err(err))
+		return nil, fmt.Errorf("failed to search access tokens: %w", err)
+	}
+
+	tokens := make([]models.AccessToken, 0)
+	if err = cur.All(ctx, &tokens); err != nil {
+		log.Error("failed to decode access tokens", sl.Err(err))
+		return nil, fmt.Errorf("failed to decode access tokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// GetAccessToken retrieves the token with the provided ID, revoked or not.
+func (m *MongoRepository) GetAccessToken(ctx context.Context, tokenID string) (models.AccessToken, error) {
+	return m.findAccessToken(ctx, "access_token.mongo.GetAccessToken",
+		bson.D{{Key: "token_id", Value: tokenID}})
+}
+
+// GetAccessTokenByHash retrieves the token with the provided hash, revoked or not.
+func (m *MongoRepository) GetAccessTokenByHash(ctx context.Context, hash string) (models.AccessToken, error) {
+	return m.findAccessToken(ctx, "access_token.mongo.GetAccessTokenByHash",
+		bson.D{{Key: "hash", Value: hash}})
+}
+
+func (m *MongoRepository) findAccessToken(ctx context.Context, op string, filter bson.D) (models.AccessToken, error) {
+	var token models.AccessToken
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.AccessTokenCollection])
+
+	err := coll.FindOne(ctx, filter).Decode(&token)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return models.AccessToken{}, grpcerror.ErrTokenNotFound
+	}
+	if err != nil {
+		log.Error("failed to find access token", sl.Err(err))
+		return models.AccessToken{}, fmt.Errorf("failed to find access token: %w", err)
+	}
+
+	return token, nil
+}
+
+// TouchAccessToken sets the last-use time of the token with the provided ID.
+func (m *MongoRepository) TouchAccessToken(ctx context.Context, tokenID string, at time.Time) error {
+	const op = "access_token.mongo.TouchAccessToken"
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.AccessTokenCollection])
+
+	filter := bson.D{
+		{Key: "token_id", Value: tokenID},
+	}
+
+	update := bson.M{
+		"$set": bson.M{"last_used_at": at.UTC()},
+	}
+
+	if _, err := coll.UpdateOne(ctx, filter, update); err != nil {
+		log.Error("failed to update access token", sl.Err(err))
+		return fmt.Errorf("failed to update access token: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeAccessToken marks the token with the provided ID as revoked.
+func (m *MongoRepository) RevokeAccessToken(ctx context.Context, tokenID string) error {
+	const op = "access_token.mongo.RevokeAccessToken"
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.AccessTokenCollection])
+
+	filter := bson.D{
+		{Key: "token_id", Value: tokenID},
+		{Key: "revoked", Value: false},
+	}
+
+	update := bson.M{
+		"$set": bson.M{"revoked": true},
+	}
+
+	res, err := coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		log.Error("failed to revoke access token", sl.Err(err))
+		return fmt.Errorf("failed to revoke access token: %w", err)
+	}
+
+	if res.MatchedCount == 0 {
+		return grpcerror.ErrTokenNotFound
+	}
+
+	return nil
+}
+
+// RevokeAllAccessTokens marks every token of the user with the provided user ID as
+// revoked.
+func (m *MongoRepository) RevokeAllAccessTokens(ctx context.Context, userID int64) error {
+	const op = "access_token.mongo.RevokeAllAccessTokens"
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.AccessTokenCollection])
+
+	filter := bson.D{
+		{Key: "user_id", Value: userID},
+		{Key: "revoked", Value: false},
+	}
+
+	update := bson.M{
+		"$set": bson.M{"revoked": true},
+	}
+
+	if _, err := coll.UpdateMany(ctx, filter, update); err != nil {
+		log.Error("failed to revoke access tokens", sl.Err(err))
+		return fmt.Errorf("failed to revoke access tokens: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	"go.mongodb.org/mongo-driver/bson"
+	"log/slog"
+)
+
+// SetAttributes sets the provided custom attributes of the user with the provided
+// user ID in a single update. Attributes with a nil value are removed. If an
+// attribute is listed more than once, the last value wins.
+func (m *MongoRepository) SetAttributes(ctx context.Context, userID int64, attrs []models.Attribute) error {
+	const op = "attributes.mongo.SetAttributes"
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.UserCollection])
+
+	filter := bson.D{
+		{Key: "user_id", Value: userID},
+		{Key: "deleted_at", Value: nil},
+	}
+
+	set := bson.M{}
+	unset := bson.M{}
+	for _, attr := range attrs {
+		path := "attributes." + attr.Namespace + "." + attr.Name
+		if attr.Value == nil {
+			delete(set, path)
+			unset[path] = ""
+		} else {
+			delete(unset, path)
+			set[path] = attr.Value
+		}
+	}
+
+	update := bson.M{
+		"$inc": bson.M{"version": 1},
+	}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	res, err := coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		log.Error("failed to set attributes", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if res.MatchedCount == 0 {
+		return grpcerror.ErrUserNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	"log/slog"
+)
+
+// RecordAuditEvent stores the event in the audit collection. Audit events are
+// kept when the user is purged.
+func (m *MongoRepository) RecordAuditEvent(ctx context.Context, event *models.AuditEvent) error {
+	const op = "audit.mongo.RecordAuditEvent"
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.AuditCollection])
+
+	if _, err := coll.InsertOne(ctx, event); err != nil {
+		log.Error("failed to insert audit event", sl.Err(err))
+		return fmt.Errorf("failed to insert audit event: %w", err)
+	}
+
+	return nil
+}
@@ -2,21 +2,23 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
 	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
 	"go.mongodb.org/mongo-driver/bson"
-	"golang.org/x/crypto/bcrypt"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"log/slog"
+	"time"
 )
 
-// Login authenticates a user by verifying the provided email and password against
-// the stored user data in MongoDB. If the authentication is successful, it returns
-// the authenticated user; otherwise, it returns an error indicating the failure.
-func (m *MongoRepository) Login(ctx context.Context, email, passwordSalted string) (models.User, error) {
-	const op = "auth.mongo.Login"
+// GetUserByEmail retrieves the user with the provided normalized email from the MongoDB
+// database, including the password hash, so that the caller can verify credentials.
+func (m *MongoRepository) GetUserByEmail(ctx context.Context, normalizedEmail string) (models.User, error) {
+	const op = "auth.mongo.GetUserByEmail"
 
 	var user models.User
 
@@ -28,7 +30,8 @@ func (m *MongoRepository) Login(ctx context.Context, email, passwordSalted strin
 		m.Config.Collections[config.UserCollection])
 
 	filter := bson.D{
-		{"email", email},
+		{Key: "email_normalized", Value: normalizedEmail},
+		{Key: "deleted_at", Value: nil},
 	}
 
 	res := coll.FindOne(ctx, filter)
@@ -41,18 +44,77 @@ func (m *MongoRepository) Login(ctx context.Context, email, passwordSalted strin
 		return models.User{}, fmt.Errorf("failed to decode user: %w", err)
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PassHash), []byte(passwordSalted)); err != nil {
+	return user, nil
+}
+
+// EmailExists reports whether a user with the provided normalized email is stored,
+// soft-deleted users included, as they still hold the email in the unique index.
+func (m *MongoRepository) EmailExists(ctx context.Context, normalizedEmail string) (bool, error) {
+	const op = "auth.mongo.EmailExists"
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.UserCollection])
+
+	filter := bson.D{
+		{Key: "email_normalized", Value: normalizedEmail},
+	}
+
+	count, err := coll.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	if err != nil {
+		log.Error("failed to count users", sl.Err(err))
+		return false, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// GetUserByPhone retrieves the user with the provided normalized phone number from
+// the MongoDB database. Only verified phone numbers are matched, as unverified ones
+// may belong to someone else.
+func (m *MongoRepository) GetUserByPhone(ctx context.Context, normalizedPhone string) (models.User, error) {
+	const op = "auth.mongo.GetUserByPhone"
+
+	var user models.User
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.UserCollection])
+
+	filter := bson.D{
+		{Key: "phone_normalized", Value: normalizedPhone},
+		{Key: "phone_verified", Value: true},
+		{Key: "deleted_at", Value: nil},
+	}
+
+	res := coll.FindOne(ctx, filter)
+	if res.Err() != nil {
 		return models.User{}, grpcerror.ErrUserNotFound
 	}
 
+	if err := res.Decode(&user); err != nil {
+		log.Error("failed to decode user", sl.Err(err))
+		return models.User{}, fmt.Errorf("failed to decode user: %w", err)
+	}
+
 	return user, nil
 }
 
-// CreateUser creates a new user in the MongoDB database. It first checks if a user
-// with the provided email already exists, and if not, it assigns a new user ID,
-// sets the user's role, and inserts the user into the database.
-func (m *MongoRepository) CreateUser(ctx context.Context, user *models.User) (int64, error) {
-	const op = "auth.mongo.CreateUser"
+// UpdatePassHash replaces the password hash of the user with the provided user ID
+// and records the version of the pepper it was created with.
+func (m *MongoRepository) UpdatePassHash(
+	ctx context.Context,
+	userID int64,
+	passHash string,
+	pepperVersion int) error {
+	const op = "auth.mongo.UpdatePassHash"
+
 	log := m.log.With(
 		slog.String("op", op),
 	)
@@ -61,66 +123,154 @@ func (m *MongoRepository) CreateUser(ctx context.Context, user *models.User) (in
 		m.Config.Collections[config.UserCollection])
 
 	filter := bson.D{
-		{"email", user.Email},
+		{Key: "user_id", Value: userID},
+		{Key: "deleted_at", Value: nil},
 	}
 
-	curEmail, err := coll.Find(ctx, filter)
+	update := bson.M{
+		"$set": bson.M{
+			"pass_hash":      passHash,
+			"pepper_version": pepperVersion,
+		},
+	}
+
+	res, err := coll.UpdateOne(ctx, filter, update)
 	if err != nil {
-		log.Error("failed to search in db", sl.Err(err))
-		return -1, fmt.Errorf("failed to search in db: %w", err)
+		log.Error("failed to update password hash", sl.Err(err))
+		return fmt.Errorf("failed to update password hash: %w", err)
 	}
-	defer func() {
-		_ = curEmail.Close(context.Background())
-	}()
 
-	if curEmail.RemainingBatchLength() > 0 {
-		return -1, grpcerror.ErrUserExists
+	if res.MatchedCount == 0 {
+		return grpcerror.ErrUserNotFound
 	}
 
-	id, err := m.getNewUniqueUserId()
-	if err != nil {
-		return -1, err
+	return nil
+}
+
+// UpdateLastLogin records the time of the last successful sign-in of the user with
+// the provided user ID.
+func (m *MongoRepository) UpdateLastLogin(ctx context.Context, userID int64, at time.Time) error {
+	const op = "auth.mongo.UpdateLastLogin"
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.UserCollection])
+
+	filter := bson.D{
+		{Key: "user_id", Value: userID},
+		{Key: "deleted_at", Value: nil},
 	}
 
-	user.ID = id
-	user.Role = models.UserRole
+	update := bson.M{
+		"$set": bson.M{"last_login_at": at.UTC()},
+	}
 
-	_, err = coll.InsertOne(ctx, user)
+	res, err := coll.UpdateOne(ctx, filter, update)
 	if err != nil {
-		log.Error("failed to insert user", sl.Err(err))
-		return -1, fmt.Errorf("failed to insert user: %w", err)
+		log.Error("failed to update last login time", sl.Err(err))
+		return fmt.Errorf("failed to update last login time: %w", err)
 	}
 
-	return id, nil
+	if res.MatchedCount == 0 {
+		return grpcerror.ErrUserNotFound
+	}
+
+	return nil
 }
 
-// getNewUniqueUserId generates a new unique user ID
-func (m *MongoRepository) getNewUniqueUserId() (int64, error) {
-	var seq models.Sequence
+// CountUsersByPepperVersion returns the number of users whose password hash was
+// created with each pepper version. Users without a recorded version use version 0.
+func (m *MongoRepository) CountUsersByPepperVersion(ctx context.Context) (map[int]int64, error) {
+	const op = "auth.mongo.CountUsersByPepperVersion"
+
+	var rows []struct {
+		Version int   `bson:"_id"`
+		Count   int64 `bson:"count"`
+	}
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
 
 	coll := m.Db.Database(m.Config.DBName).Collection(
-		m.Config.Collections[config.SequenceCollection])
+		m.Config.Collections[config.UserCollection])
 
-	filter := bson.D{
-		{"collection_name", m.Config.Collections[config.UserCollection]},
+	pipeline := bson.A{
+		bson.M{"$group": bson.M{
+			"_id":   bson.M{"$ifNull": bson.A{"$pepper_version", 0}},
+			"count": bson.M{"$sum": 1},
+		}},
 	}
 
-	update := bson.D{
-		{"$inc", bson.D{
-			{"counter", 1},
-		},
-		},
+	cur, err := coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		log.Error("failed to count users by pepper version", sl.Err(err))
+		return nil, fmt.Errorf("failed to count users by pepper version: %w", err)
 	}
 
-	res := coll.FindOneAndUpdate(context.TODO(), filter, update)
-	if res.Err() != nil {
-		return -1, fmt.Errorf("failed to get id: %w", res.Err())
+	if err = cur.All(ctx, &rows); err != nil {
+		log.Error("failed to decode pepper usage", sl.Err(err))
+		return nil, fmt.Errorf("failed to decode pepper usage: %w", err)
 	}
 
-	err := res.Decode(&seq)
+	usage := make(map[int]int64, len(rows))
+	for _, row := range rows {
+		usage[row.Version] = row.Count
+	}
+
+	return usage, nil
+}
+
+// CreateUser creates a new user in the MongoDB database. It assigns a new user ID,
+// sets the user's role, and inserts the user into the database. Email uniqueness is
+// enforced by the unique index on the normalized email, so a duplicate-key error on
+// that index is reported as ErrUserExists. Any other duplicate, e.g. a user ID taken
+// twice by the ID generator, is an internal error.
+func (m *MongoRepository) CreateUser(ctx context.Context, user *models.User) (int64, error) {
+	const op = "auth.mongo.CreateUser"
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.UserCollection])
+
+	id, err := m.idGen.NextID(ctx)
 	if err != nil {
-		return -1, fmt.Errorf("failed to decode sequence: %w", err)
+		return -1, err
+	}
+
+	user.ID = id
+	user.Role = models.UserRole
+	user.Status = models.StatusActive
+	if user.FamilyIDs == nil {
+		user.FamilyIDs = []int64{}
 	}
 
-	return seq.Counter, nil
+	_, err = coll.InsertOne(ctx, user)
+	if isDuplicateOn(err, emailUniqueIndex) {
+		return -1, grpcerror.ErrUserExists
+	}
+	if err != nil {
+		log.Error("failed to insert user", sl.Err(err))
+		return -1, fmt.Errorf("failed to insert user: %w", err)
+	}
+
+	return id, nil
+}
+
+// duplicateKeyCode is the MongoDB error code of a unique index violation.
+const duplicateKeyCode = 11000
+
+// emailUniqueIndex is the unique index on the normalized email created by the migrations.
+const emailUniqueIndex = "email_normalized_unique"
+
+// isDuplicateOn reports whether the provided error is a duplicate-key error on the
+// index with the provided name.
+func isDuplicateOn(err error, index string) bool {
+	var serverErr mongo.ServerError
+	return errors.As(err, &serverErr) && serverErr.HasErrorCodeWithMessage(duplicateKeyCode, index)
 }
@@ -0,0 +1,22 @@
+package mongodb
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+	"testing"
+)
+
+func TestIsDuplicateOn(t *testing.T) {
+	duplicate := func(index string) error {
+		return mongo.WriteException{WriteErrors: []mongo.WriteError{{
+			Code:    duplicateKeyCode,
+			Message: "E11000 duplicate key error collection: sso.user index: " + index + " dup key",
+		}}}
+	}
+
+	assert.True(t, isDuplicateOn(duplicate(emailUniqueIndex), emailUniqueIndex))
+	assert.False(t, isDuplicateOn(duplicate("user_id_unique"), emailUniqueIndex))
+	assert.False(t, isDuplicateOn(errors.New("network error"), emailUniqueIndex))
+	assert.False(t, isDuplicateOn(nil, emailUniqueIndex))
+}
@@ -0,0 +1,234 @@
+package mongodb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log/slog"
+)
+
+// SaveAvatar uploads the avatar images of the user with the provided user ID to
+// GridFS, one file per size, and makes them the current avatar of the user. The
+// images of the previous avatar are removed afterwards.
+func (m *MongoRepository) SaveAvatar(
+	ctx context.Context,
+	userID int64,
+	avatar *models.Avatar,
+	images map[int][]byte) error {
+	const op = "avatar.mongo.SaveAvatar"
+
+	log := m.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	bucket, err := m.avatarBucket(ctx)
+	if err != nil {
+		log.Error("failed to open avatar bucket", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for size, data := range images {
+		opts := options.GridFSUpload().SetMetadata(bson.D{
+			{Key: "user_id", Value: userID},
+			{Key: "upload_id", Value: avatar.UploadID},
+			{Key: "size", Value: size},
+			{Key: "content_type", Value: avatar.ContentType},
+		})
+
+		filename := fmt.Sprintf("%d/%s/%d", userID, avatar.UploadID, size)
+		if _, err = bucket.UploadFromStream(filename, bytes.NewReader(data), opts); err != nil {
+			log.Error("failed to upload avatar", sl.Err(err))
+			m.dropAvatarFiles(ctx, log, bucket, bson.D{{Key: "metadata.upload_id", Value: avatar.UploadID}})
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.UserCollection])
+
+	filter := bson.D{
+		{Key: "user_id", Value: userID},
+		{Key: "deleted_at", Value: nil},
+	}
+
+	update := bson.M{
+		"$set": bson.M{"avatar": avatar},
+		"$inc": bson.M{"version": 1},
+	}
+
+	res, err := coll.UpdateOne(ctx, filter, update)
+	if err != nil || res.MatchedCount == 0 {
+		m.dropAvatarFiles(ctx, log, bucket, bson.D{{Key: "metadata.upload_id", Value: avatar.UploadID}})
+	}
+	if err != nil {
+		log.Error("failed to set avatar", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if res.MatchedCount == 0 {
+		return grpcerror.ErrUserNotFound
+	}
+
+	m.dropAvatarFiles(ctx, log, bucket, bson.D{
+		{Key: "metadata.user_id", Value: userID},
+		{Key: "metadata.upload_id", Value: bson.M{"$ne": avatar.UploadID}},
+	})
+
+	return nil
+}
+
+// GetAvatarImage returns the avatar image of the provided size from the upload with
+// the provided upload ID of the user with the provided user ID.
+func (m *MongoRepository) GetAvatarImage(
+	ctx context.Context,
+	userID int64,
+	uploadID string,
+	size int) ([]byte, error) {
+	const op = "avatar.mongo.GetAvatarImage"
+
+	log := m.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	bucket, err := m.avatarBucket(ctx)
+	if err != nil {
+		log.Error("failed to open avatar bucket", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	filter := bson.D{
+		{Key: "metadata.user_id", Value: userID},
+		{Key: "metadata.upload_id", Value: uploadID},
+		{Key: "metadata.size", Value: size},
+	}
+
+	var file struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+
+	err = bucket.GetFilesCollection().FindOne(ctx, filter).Decode(&file)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, grpcerror.ErrAvatarNotFound
+	}
+	if err != nil {
+		log.Error("failed to find avatar", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var buf bytes.Buffer
+	if _, err = bucket.DownloadToStream(file.ID, &buf); err != nil {
+		log.Error("failed to download avatar", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// DeleteAvatar removes the avatar of the user with the provided user ID along with
+// all of its images.
+func (m *MongoRepository) DeleteAvatar(ctx context.Context, userID int64) error {
+	const op = "avatar.mongo.DeleteAvatar"
+
+	log := m.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.UserCollection])
+
+	filter := bson.D{
+		{Key: "user_id", Value: userID},
+		{Key: "deleted_at", Value: nil},
+	}
+
+	update := bson.M{
+		"$unset": bson.M{"avatar": ""},
+		"$inc":   bson.M{"version": 1},
+	}
+
+	res, err := coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		log.Error("failed to unset avatar", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if res.MatchedCount == 0 {
+		return grpcerror.ErrUserNotFound
+	}
+
+	bucket, err := m.avatarBucket(ctx)
+	if err != nil {
+		log.Error("failed to open avatar bucket", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	m.dropAvatarFiles(ctx, log, bucket, bson.D{{Key: "metadata.user_id", Value: userID}})
+
+	return nil
+}
+
+// avatarBucket opens the GridFS bucket of the avatars. Buckets are not safe for
+// concurrent use with deadlines, so a new one is opened for every operation and
+// takes the deadline of the context.
+func (m *MongoRepository) avatarBucket(ctx context.Context) (*gridfs.Bucket, error) {
+	bucket, err := gridfs.NewBucket(m.Db.Database(m.Config.DBName),
+		options.GridFSBucket().SetName(m.Config.Collections[config.AvatarBucket]))
+	if err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err = bucket.SetReadDeadline(deadline); err != nil {
+			return nil, err
+		}
+		if err = bucket.SetWriteDeadline(deadline); err != nil {
+			return nil, err
+		}
+	}
+
+	return bucket, nil
+}
+
+// deleteAvatarFiles removes the avatar files matching the provided filter.
+func (m *MongoRepository) deleteAvatarFiles(ctx context.Context, bucket *gridfs.Bucket, filter bson.D) error {
+	cursor, err := bucket.GetFilesCollection().Find(ctx, filter,
+		options.Find().SetProjection(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return fmt.Errorf("failed to find avatar files: %w", err)
+	}
+
+	var files []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err = cursor.All(ctx, &files); err != nil {
+		return fmt.Errorf("failed to decode avatar files: %w", err)
+	}
+
+	for _, file := range files {
+		if err = bucket.DeleteContext(ctx, file.ID); err != nil {
+			return fmt.Errorf("failed to delete avatar file %s: %w", file.ID.Hex(), err)
+		}
+	}
+
+	return nil
+}
+
+// dropAvatarFiles removes the avatar files matching the provided filter. Failures
+// are only logged: leftover files are not referenced by any user and are removed
+// with the next avatar of the user or when the user is purged.
+func (m *MongoRepository) dropAvatarFiles(ctx context.Context, log *slog.Logger, bucket *gridfs.Bucket, filter bson.D) {
+	if err := m.deleteAvatarFiles(ctx, bucket, filter); err != nil {
+		log.Warn("failed to remove avatar files", sl.Err(err))
+	}
+}
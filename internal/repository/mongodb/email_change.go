@@ -0,0 +1,145 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log/slog"
+	"time"
+)
+
+// SetEmailChange stores the requested email change of the user with the provided
+// user ID, replacing the previous one if any.
+func (m *MongoRepository) SetEmailChange(ctx context.Context, userID int64, change *models.EmailChange) error {
+	const op = "emailchange.mongo.SetEmailChange"
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.UserCollection])
+
+	filter := bson.D{
+		{Key: "user_id", Value: userID},
+		{Key: "deleted_at", Value: nil},
+	}
+
+	update := bson.M{
+		"$set": bson.M{"email_change": change},
+	}
+
+	res, err := coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		log.Error("failed to set email change", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if res.MatchedCount == 0 {
+		return grpcerror.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// ConfirmEmailChange applies the pending email change with the provided confirmation
+// token hash and returns the ID of the user it belongs to. The previous email is kept
+// in the change, so that it can be reverted until the change expires. The uniqueness
+// of the new email is enforced by the unique index on the normalized email.
+func (m *MongoRepository) ConfirmEmailChange(ctx context.Context, tokenHash string, now time.Time) (int64, error) {
+	const op = "emailchange.mongo.ConfirmEmailChange"
+
+	filter := bson.D{
+		{Key: "email_change.confirm_token_hash", Value: tokenHash},
+		{Key: "email_change.confirmed_at", Value: nil},
+		{Key: "email_change.expires_at", Value: bson.M{"$gt": now}},
+		{Key: "deleted_at", Value: nil},
+	}
+
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.D{
+			{Key: "email_change.previous_email", Value: "$email"},
+			{Key: "email_change.previous_email_normalized", Value: "$email_normalized"},
+			{Key: "email_change.confirmed_at", Value: now},
+			{Key: "email", Value: "$email_change.email"},
+			{Key: "email_normalized", Value: "$email_change.email_normalized"},
+			{Key: "version", Value: incrementedVersion},
+		}}},
+	}
+
+	return m.updateEmailChange(ctx, op, filter, update)
+}
+
+// CancelEmailChange cancels the email change with the provided cancellation token
+// hash and returns the ID of the user it belongs to. If the change was already
+// confirmed, the previous email is restored.
+func (m *MongoRepository) CancelEmailChange(ctx context.Context, tokenHash string, now time.Time) (int64, error) {
+	const op = "emailchange.mongo.CancelEmailChange"
+
+	filter := bson.D{
+		{Key: "email_change.cancel_token_hash", Value: tokenHash},
+		{Key: "email_change.expires_at", Value: bson.M{"$gt": now}},
+		{Key: "deleted_at", Value: nil},
+	}
+
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.D{
+			{Key: "email", Value: bson.M{
+				"$ifNull": bson.A{"$email_change.previous_email", "$email"}}},
+			{Key: "email_normalized", Value: bson.M{
+				"$ifNull": bson.A{"$email_change.previous_email_normalized", "$email_normalized"}}},
+			{Key: "version", Value: incrementedVersion},
+		}}},
+		{{Key: "$unset", Value: "email_change"}},
+	}
+
+	return m.updateEmailChange(ctx, op, filter, update)
+}
+
+// incrementedVersion is the pipeline expression of the next version of the user.
+var incrementedVersion = bson.M{ //nolint:gochecknoglobals
+	"$add": bson.A{bson.M{"$ifNull": bson.A{"$version", 0}}, 1},
+}
+
+// literal wraps a value of a pipeline update, so that it is never read as an expression.
+func literal(value any) bson.M {
+	return bson.M{"$literal": value}
+}
+
+func (m *MongoRepository) updateEmailChange(
+	ctx context.Context,
+	op string,
+	filter bson.D,
+	update mongo.Pipeline) (int64, error) {
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.UserCollection])
+
+	opts := options.FindOneAndUpdate().SetProjection(bson.M{"user_id": 1})
+
+	var user models.User
+
+	err := coll.FindOneAndUpdate(ctx, filter, update, opts).Decode(&user)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, grpcerror.ErrEmailChangeNotFound
+	}
+	if mongo.IsDuplicateKeyError(err) {
+		return 0, grpcerror.ErrUserExists
+	}
+	if err != nil {
+		log.Error("failed to update email change", sl.Err(err))
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user.ID, nil
+}
@@ -0,0 +1,104 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/idgen"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"sync"
+)
+
+var ErrUnknownIDStrategy = errors.New("unknown id generation strategy")
+
+// newIDGenerator creates the user ID generator selected by the configuration.
+func newIDGenerator(db *mongo.Client, cfg *config.MongoConfig, idCfg config.IDGeneratorConfig) (idgen.Generator, error) {
+	switch idCfg.Strategy {
+	case idgen.StrategySequence, "":
+		return &sequenceGenerator{db: db, cfg: cfg}, nil
+	case idgen.StrategyBlock:
+		if idCfg.BlockSize < 1 {
+			return nil, fmt.Errorf("%w: %d", idgen.ErrInvalidBlockSize, idCfg.BlockSize)
+		}
+		return &blockSequenceGenerator{
+			seq:       sequenceGenerator{db: db, cfg: cfg},
+			blockSize: idCfg.BlockSize,
+		}, nil
+	case idgen.StrategySnowflake:
+		return idgen.NewSnowflake(idCfg.NodeID)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownIDStrategy, idCfg.Strategy)
+	}
+}
+
+// sequenceGenerator takes user IDs one by one from the counter in the sequence collection.
+type sequenceGenerator struct {
+	db  *mongo.Client
+	cfg *config.MongoConfig
+}
+
+func (g *sequenceGenerator) NextID(ctx context.Context) (int64, error) {
+	return g.reserve(ctx, 1)
+}
+
+// reserve atomically increments the counter by n and returns its previous value,
+// so the IDs in [previous, previous+n) belong to the caller.
+func (g *sequenceGenerator) reserve(ctx context.Context, n int64) (int64, error) {
+	var seq models.Sequence
+
+	coll := g.db.Database(g.cfg.DBName).Collection(
+		g.cfg.Collections[config.SequenceCollection])
+
+	filter := bson.D{
+		{Key: "collection_name", Value: g.cfg.Collections[config.UserCollection]},
+	}
+
+	update := bson.D{
+		{Key: "$inc", Value: bson.D{
+			{Key: "counter", Value: n},
+		}},
+	}
+
+	res := coll.FindOneAndUpdate(ctx, filter, update)
+	if res.Err() != nil {
+		return -1, fmt.Errorf("failed to get id: %w", res.Err())
+	}
+
+	if err := res.Decode(&seq); err != nil {
+		return -1, fmt.Errorf("failed to decode sequence: %w", err)
+	}
+
+	return seq.Counter, nil
+}
+
+// blockSequenceGenerator reserves blocks of IDs from the sequence counter and hands
+// them out from memory, so only one in blockSize registrations hits the database.
+// IDs of a block that is not used up before the service stops are skipped.
+type blockSequenceGenerator struct {
+	mu        sync.Mutex
+	seq       sequenceGenerator
+	blockSize int64
+	next      int64
+	end       int64
+}
+
+func (g *blockSequenceGenerator) NextID(ctx context.Context) (int64, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.next >= g.end {
+		start, err := g.seq.reserve(ctx, g.blockSize)
+		if err != nil {
+			return -1, err
+		}
+		g.next, g.end = start, start+g.blockSize
+	}
+
+	id := g.next
+	g.next++
+
+	return id, nil
+}
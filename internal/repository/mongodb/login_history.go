@@ -0,0 +1,71 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log/slog"
+)
+
+// RecordLoginAttempt stores the sign-in attempt in the login history collection.
+// Old attempts are removed by the TTL index on the creation time.
+func (m *MongoRepository) RecordLoginAttempt(ctx context.Context, attempt *models.LoginAttempt) error {
+	const op = "loginhistory.mongo.RecordLoginAttempt"
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.LoginHistoryCollection])
+
+	if _, err := coll.InsertOne(ctx, attempt); err != nil {
+		log.Error("failed to insert login attempt", sl.Err(err))
+		return fmt.Errorf("failed to insert login attempt: %w", err)
+	}
+
+	return nil
+}
+
+// GetLoginHistory retrieves the sign-in attempts of the user with the provided user
+// ID, the most recent first. At most limit attempts are returned if limit is positive.
+func (m *MongoRepository) GetLoginHistory(
+	ctx context.Context,
+	userID int64,
+	limit int64) ([]models.LoginAttempt, error) {
+	const op = "loginhistory.mongo.GetLoginHistory"
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.LoginHistoryCollection])
+
+	filter := bson.D{
+		{Key: "user_id", Value: userID},
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	if limit > 0 {
+		opts.SetLimit(limit)
+	}
+
+	cur, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		log.Error("failed to search login history", sl.Err(err))
+		return nil, fmt.Errorf("failed to search login history: %w", err)
+	}
+
+	attempts := make([]models.LoginAttempt, 0)
+	if err = cur.All(ctx, &attempts); err != nil {
+		log.Error("failed to decode login history", sl.Err(err))
+		return nil, fmt.Errorf("failed to decode login history: %w", err)
+	}
+
+	return attempts, nil
+}
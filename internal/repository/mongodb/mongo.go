@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/idgen"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -14,13 +15,17 @@ type MongoRepository struct {
 	Db     *mongo.Client
 	Config *config.MongoConfig
 	log    *slog.Logger
+	idGen  idgen.Generator
 }
 
 // InitMongoRepository initializes a new MongoRepository instance with the provided
-// configuration, logger, and hash salt. It establishes a connection to the MongoDB
-// server, performs a ping to ensure connectivity, and returns the initialized
-// MongoRepository instance.
-func InitMongoRepository(cfg *config.MongoConfig, logger *slog.Logger) (
+// configuration, ID generation settings and logger. It establishes a connection to
+// the MongoDB server, performs a ping to ensure connectivity, sets up the user ID
+// generator and returns the initialized MongoRepository instance.
+func InitMongoRepository(
+	cfg *config.MongoConfig,
+	idCfg config.IDGeneratorConfig,
+	logger *slog.Logger) (
 	*MongoRepository, error) {
 	const op = "mongo.InitMongoRepository"
 
@@ -47,9 +52,17 @@ func InitMongoRepository(cfg *config.MongoConfig, logger *slog.Logger) (
 	}
 	log.Info("pinged successfully")
 
+	idGen, err := newIDGenerator(db, cfg, idCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize id generator: %w", err)
+	}
+
+	log.Info("id generator initialized", slog.String("strategy", idCfg.Strategy))
+
 	return &MongoRepository{
 		Db:     db,
 		Config: cfg,
 		log:    logger,
+		idGen:  idGen,
 	}, nil
 }
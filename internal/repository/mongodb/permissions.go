@@ -22,7 +22,10 @@ func (m *MongoRepository) IsAdmin(ctx context.Context, userId int64) (bool, erro
 		slog.String("op", op),
 	)
 
-	filter := bson.D{{"user_id", userId}}
+	filter := bson.D{
+		{"user_id", userId},
+		{Key: "deleted_at", Value: nil},
+	}
 
 	coll := m.Db.Database(m.Config.DBName).Collection(
 		m.Config.Collections[config.UserCollection])
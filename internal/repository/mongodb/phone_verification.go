@@ -0,0 +1,142 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log/slog"
+	"time"
+)
+
+// SetPhoneVerification stores the phone verification of the user with the provided
+// user ID, replacing the previous one if any.
+func (m *MongoRepository) SetPhoneVerification(
+	ctx context.Context,
+	userID int64,
+	verification *models.PhoneVerification) error {
+	const op = "phone.mongo.SetPhoneVerification"
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.UserCollection])
+
+	filter := bson.D{
+		{Key: "user_id", Value: userID},
+		{Key: "deleted_at", Value: nil},
+	}
+
+	update := bson.M{
+		"$set": bson.M{"phone_verification": verification},
+	}
+
+	res, err := coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		log.Error("failed to set phone verification", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if res.MatchedCount == 0 {
+		return grpcerror.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// UsePhoneVerificationAttempt counts an attempt to enter the code of the phone
+// verification of the user with the provided user ID and returns the verification.
+// ErrVerificationNotFound is returned if there is no verification, it has expired
+// or maxAttempts attempts were already used.
+func (m *MongoRepository) UsePhoneVerificationAttempt(
+	ctx context.Context,
+	userID int64,
+	now time.Time,
+	maxAttempts int) (models.PhoneVerification, error) {
+	const op = "phone.mongo.UsePhoneVerificationAttempt"
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.UserCollection])
+
+	filter := bson.D{
+		{Key: "user_id", Value: userID},
+		{Key: "deleted_at", Value: nil},
+		{Key: "phone_verification.expires_at", Value: bson.M{"$gt": now}},
+		{Key: "phone_verification.attempts", Value: bson.M{"$lt": maxAttempts}},
+	}
+
+	update := bson.M{
+		"$inc": bson.M{"phone_verification.attempts": 1},
+	}
+
+	opts := options.FindOneAndUpdate().
+		SetProjection(bson.M{"phone_verification": 1}).
+		SetReturnDocument(options.After)
+
+	var user models.User
+
+	err := coll.FindOneAndUpdate(ctx, filter, update, opts).Decode(&user)
+	if errors.Is(err, mongo.ErrNoDocuments) || (err == nil && user.PhoneVerification == nil) {
+		return models.PhoneVerification{}, grpcerror.ErrVerificationNotFound
+	}
+	if err != nil {
+		log.Error("failed to use phone verification attempt", sl.Err(err))
+		return models.PhoneVerification{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return *user.PhoneVerification, nil
+}
+
+// ConfirmPhone marks the phone number of the user with the provided user ID as
+// verified if the user has a phone verification with the provided code hash. As
+// the verification is dropped when the phone number changes, the verified number
+// is the one the code was sent to. A verified phone number identifies the user on
+// sign-in, so ErrPhoneInUse is returned if another user has already verified it.
+func (m *MongoRepository) ConfirmPhone(ctx context.Context, userID int64, codeHash string) error {
+	const op = "phone.mongo.ConfirmPhone"
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.UserCollection])
+
+	filter := bson.D{
+		{Key: "user_id", Value: userID},
+		{Key: "deleted_at", Value: nil},
+		{Key: "phone_verification.code_hash", Value: codeHash},
+	}
+
+	update := bson.M{
+		"$set":   bson.M{"phone_verified": true},
+		"$unset": bson.M{"phone_verification": ""},
+	}
+
+	res, err := coll.UpdateOne(ctx, filter, update)
+	if mongo.IsDuplicateKeyError(err) {
+		return grpcerror.ErrPhoneInUse
+	}
+	if err != nil {
+		log.Error("failed to confirm phone", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if res.MatchedCount == 0 {
+		return grpcerror.ErrVerificationNotFound
+	}
+
+	return nil
+}
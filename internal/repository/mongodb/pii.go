@@ -0,0 +1,112 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	"go.mongodb.org/mongo-driver/bson"
+	"log/slog"
+)
+
+// IterateUsers calls fn for every user in the MongoDB database, stopping at the
+// first error returned by fn.
+func (m *MongoRepository) IterateUsers(ctx context.Context, fn func(user *models.User) error) error {
+	const op = "pii.mongo.IterateUsers"
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.UserCollection])
+
+	cur, err := coll.Find(ctx, bson.D{})
+	if err != nil {
+		log.Error("failed to search users", sl.Err(err))
+		return fmt.Errorf("failed to search users: %w", err)
+	}
+	defer func() {
+		_ = cur.Close(ctx)
+	}()
+
+	for cur.Next(ctx) {
+		var user models.User
+		if err = cur.Decode(&user); err != nil {
+			log.Error("failed to decode user", sl.Err(err))
+			return fmt.Errorf("failed to decode user: %w", err)
+		}
+
+		if err = fn(&user); err != nil {
+			return err
+		}
+	}
+
+	return cur.Err()
+}
+
+// ReplaceUserPII stores the re-encrypted personal data of the user along with the
+// re-encrypted emails of its pending email change, provided that neither was
+// changed since the user was read as previous. It reports whether the user was
+// updated.
+func (m *MongoRepository) ReplaceUserPII(ctx context.Context, previous, user *models.User) (bool, error) {
+	const op = "pii.mongo.ReplaceUserPII"
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.UserCollection])
+
+	filter := bson.M{
+		"user_id":          previous.ID,
+		"email":            previous.Email,
+		"email_normalized": previous.EmailNormalized,
+		"phone_number":     previous.PhoneNumber,
+		"name":             previous.Name,
+		"surname":          previous.Surname,
+		"data_key":         previous.DataKey,
+		"email_change":     nil,
+	}
+
+	set := bson.M{
+		"email":            user.Email,
+		"email_normalized": user.EmailNormalized,
+		"phone_normalized": user.PhoneNormalized,
+		"phone_number":     user.PhoneNumber,
+		"name":             user.Name,
+		"surname":          user.Surname,
+		"data_key":         user.DataKey,
+	}
+
+	if previous.EmailChange != nil && user.EmailChange != nil {
+		delete(filter, "email_change")
+		filter["email_change.email"] = previous.EmailChange.Email
+		filter["email_change.confirm_token_hash"] = previous.EmailChange.ConfirmTokenHash
+		filter["email_change.confirmed_at"] = previous.EmailChange.ConfirmedAt
+
+		set["email_change.email"] = user.EmailChange.Email
+		set["email_change.email_normalized"] = user.EmailChange.EmailNormalized
+
+		// The previous email is stored only once the change is confirmed.
+		if previous.EmailChange.PreviousEmail != "" {
+			filter["email_change.previous_email"] = previous.EmailChange.PreviousEmail
+			set["email_change.previous_email"] = user.EmailChange.PreviousEmail
+			set["email_change.previous_email_normalized"] = user.EmailChange.PreviousEmailNormalized
+		}
+	}
+
+	update := bson.M{
+		"$set": set,
+	}
+
+	res, err := coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		log.Error("failed to update personal data", sl.Err(err))
+		return false, fmt.Errorf("failed to update personal data: %w", err)
+	}
+
+	return res.MatchedCount == 1, nil
+}
@@ -0,0 +1,120 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"log/slog"
+	"time"
+)
+
+// GetUsersToPurge marks the users that were soft-deleted before deletedBefore as
+// being purged, so that they can no longer be restored, and returns every user
+// marked so far, including the ones left over by an interrupted purge.
+func (m *MongoRepository) GetUsersToPurge(ctx context.Context, deletedBefore time.Time) ([]models.User, error) {
+	const op = "purge.mongo.GetUsersToPurge"
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.UserCollection])
+
+	filter := bson.D{
+		{Key: "deleted_at", Value: bson.M{"$lte": deletedBefore}},
+	}
+
+	update := bson.M{
+		"$set": bson.M{"purging": true},
+	}
+
+	if _, err := coll.UpdateMany(ctx, filter, update); err != nil {
+		log.Error("failed to mark users for purge", sl.Err(err))
+		return nil, fmt.Errorf("failed to mark users for purge: %w", err)
+	}
+
+	cur, err := coll.Find(ctx, bson.D{{Key: "purging", Value: true}})
+	if err != nil {
+		log.Error("failed to search users to purge", sl.Err(err))
+		return nil, fmt.Errorf("failed to search users to purge: %w", err)
+	}
+
+	users := make([]models.User, 0)
+	if err = cur.All(ctx, &users); err != nil {
+		log.Error("failed to decode users", sl.Err(err))
+		return nil, fmt.Errorf("failed to decode users: %w", err)
+	}
+
+	return users, nil
+}
+
+// PurgeUser permanently removes the user with the provided user ID along with its
+// avatar, sessions, access tokens and login history. Only users marked by
+// GetUsersToPurge are removed. The user document goes last, so that a failed purge
+// is retried while the user is still marked.
+func (m *MongoRepository) PurgeUser(ctx context.Context, userID int64) error {
+	const op = "purge.mongo.PurgeUser"
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	db := m.Db.Database(m.Config.DBName)
+
+	filter := bson.D{
+		{Key: "user_id", Value: userID},
+		{Key: "purging", Value: true},
+	}
+
+	if err := db.Collection(m.Config.Collections[config.UserCollection]).
+		FindOne(ctx, filter).Err(); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil
+		}
+		log.Error("failed to find user to purge", sl.Err(err), slog.Int64("user_id", userID))
+		return fmt.Errorf("failed to find user to purge: %w", err)
+	}
+
+	bucket, err := m.avatarBucket(ctx)
+	if err != nil {
+		log.Error("failed to open avatar bucket", sl.Err(err), slog.Int64("user_id", userID))
+		return fmt.Errorf("failed to purge avatar: %w", err)
+	}
+
+	if err = m.deleteAvatarFiles(ctx, bucket, bson.D{{Key: "metadata.user_id", Value: userID}}); err != nil {
+		log.Error("failed to purge avatar", sl.Err(err), slog.Int64("user_id", userID))
+		return fmt.Errorf("failed to purge avatar: %w", err)
+	}
+
+	if _, err := db.Collection(m.Config.Collections[config.SessionCollection]).
+		DeleteMany(ctx, bson.D{{Key: "user_id", Value: userID}}); err != nil {
+		log.Error("failed to purge sessions", sl.Err(err), slog.Int64("user_id", userID))
+		return fmt.Errorf("failed to purge sessions: %w", err)
+	}
+
+	if _, err := db.Collection(m.Config.Collections[config.AccessTokenCollection]).
+		DeleteMany(ctx, bson.D{{Key: "user_id", Value: userID}}); err != nil {
+		log.Error("failed to purge access tokens", sl.Err(err), slog.Int64("user_id", userID))
+		return fmt.Errorf("failed to purge access tokens: %w", err)
+	}
+
+	if _, err := db.Collection(m.Config.Collections[config.LoginHistoryCollection]).
+		DeleteMany(ctx, bson.D{{Key: "user_id", Value: userID}}); err != nil {
+		log.Error("failed to purge login history", sl.Err(err), slog.Int64("user_id", userID))
+		return fmt.Errorf("failed to purge login history: %w", err)
+	}
+
+	if _, err := db.Collection(m.Config.Collections[config.UserCollection]).
+		DeleteOne(ctx, filter); err != nil {
+		log.Error("failed to purge user", sl.Err(err), slog.Int64("user_id", userID))
+		return fmt.Errorf("failed to purge user: %w", err)
+	}
+
+	return nil
+}
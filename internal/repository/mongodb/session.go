@@ -0,0 +1,231 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log/slog"
+	"time"
+)
+
+// CreateSession stores a new session record in the MongoDB database.
+// It assigns a unique session ID and returns it.
+func (m *MongoRepository) CreateSession(ctx context.Context, session *models.Session) (string, error) {
+	const op = "session.mongo.CreateSession"
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.SessionCollection])
+
+	session.ID = primitive.NewObjectID().Hex()
+
+	if _, err := coll.InsertOne(ctx, session); err != nil {
+		log.Error("failed to insert session", sl.Err(err))
+		return "", fmt.Errorf("failed to insert session: %w", err)
+	}
+
+	return session.ID, nil
+}
+
+// GetSessions retrieves all active (not revoked) sessions of the user with the
+// provided user ID, the most recently used sessions first.
+func (m *MongoRepository) GetSessions(ctx context.Context, userID int64) ([]models.Session, error) {
+	const op = "session.mongo.GetSessions"
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.SessionCollection])
+
+	filter := bson.D{
+		{Key: "user_id", Value: userID},
+		{Key: "revoked", Value: false},
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "last_seen_at", Value: -1}})
+
+	cur, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		log.Error("failed to search sessions", sl.Err(err))
+		return nil, fmt.Errorf("failed to search sessions: %w", err)
+	}
+
+	sessions := make([]models.Session, 0)
+	if err = cur.All(ctx, &sessions); err != nil {
+		log.Error("failed to decode sessions", sl.Err(err))
+		return nil, fmt.Errorf("failed to decode sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// GetAllSessions retrieves every session of the user with the provided user ID,
+// including the revoked ones, the oldest sessions first.
+func (m *MongoRepository) GetAllSessions(ctx context.Context, userID int64) ([]models.Session, error) {
+	const op = "session.mongo.GetAllSessions"
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.SessionCollection])
+
+	filter := bson.D{
+		{Key: "user_id", Value: userID},
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+
+	cur, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		log.Error("failed to search sessions", sl.Err(err))
+		return nil, fmt.Errorf("failed to search sessions: %w", err)
+	}
+
+	sessions := make([]models.Session, 0)
+	if err = cur.All(ctx, &sessions); err != nil {
+		log.Error("failed to decode sessions", sl.Err(err))
+		return nil, fmt.Errorf("failed to decode sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// GetSession retrieves the session with the provided ID, revoked or not, without
+// updating its last-seen time.
+func (m *MongoRepository) GetSession(ctx context.Context, sessionID string) (models.Session, error) {
+	const op = "session.mongo.GetSession"
+
+	var session models.Session
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.SessionCollection])
+
+	filter := bson.D{
+		{Key: "session_id", Value: sessionID},
+	}
+
+	err := coll.FindOne(ctx, filter).Decode(&session)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return models.Session{}, grpcerror.ErrSessionNotFound
+	}
+	if err != nil {
+		log.Error("failed to find session", sl.Err(err))
+		return models.Session{}, fmt.Errorf("failed to find session: %w", err)
+	}
+
+	return session, nil
+}
+
+// TouchSession sets the last-seen time of the session with the provided ID.
+func (m *MongoRepository) TouchSession(ctx context.Context, sessionID string, at time.Time) error {
+	const op = "session.mongo.TouchSession"
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.SessionCollection])
+
+	filter := bson.D{
+		{Key: "session_id", Value: sessionID},
+	}
+
+	update := bson.M{
+		"$set": bson.M{"last_seen_at": at.UTC()},
+	}
+
+	if _, err := coll.UpdateOne(ctx, filter, update); err != nil {
+		log.Error("failed to update session", sl.Err(err))
+		return fmt.Errorf("failed to update session: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeSession marks the session with the provided ID as revoked. Only sessions
+// that belong to the user with the provided user ID can be revoked.
+func (m *MongoRepository) RevokeSession(ctx context.Context, userID int64, sessionID string) error {
+	const op = "session.mongo.RevokeSession"
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.SessionCollection])
+
+	filter := bson.D{
+		{Key: "session_id", Value: sessionID},
+		{Key: "user_id", Value: userID},
+		{Key: "revoked", Value: false},
+	}
+
+	update := bson.M{
+		"$set": bson.M{"revoked": true},
+	}
+
+	res, err := coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		log.Error("failed to revoke session", sl.Err(err))
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	if res.MatchedCount == 0 {
+		return grpcerror.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// RevokeAllOtherSessions marks every session of the user with the provided user ID
+// as revoked, except for the current one.
+func (m *MongoRepository) RevokeAllOtherSessions(
+	ctx context.Context,
+	userID int64,
+	currentSessionID string) error {
+	const op = "session.mongo.RevokeAllOtherSessions"
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.SessionCollection])
+
+	filter := bson.D{
+		{Key: "user_id", Value: userID},
+		{Key: "session_id", Value: bson.M{"$ne": currentSessionID}},
+		{Key: "revoked", Value: false},
+	}
+
+	update := bson.M{
+		"$set": bson.M{"revoked": true},
+	}
+
+	if _, err := coll.UpdateMany(ctx, filter, update); err != nil {
+		log.Error("failed to revoke sessions", sl.Err(err))
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	return nil
+}
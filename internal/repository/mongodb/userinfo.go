@@ -2,14 +2,17 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
 	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
 	"go.mongodb.org/mongo-driver/bson"
-	"golang.org/x/crypto/bcrypt"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"log/slog"
+	"time"
 )
 
 // GetUserInfo retrieves user information for the user with the provided user ID
@@ -28,6 +31,7 @@ func (m *MongoRepository) GetUserInfo(ctx context.Context, userID int64) (models
 
 	filter := bson.D{
 		{"user_id", userID},
+		{Key: "deleted_at", Value: nil},
 	}
 
 	singleRes := coll.FindOne(ctx, filter)
@@ -41,22 +45,25 @@ func (m *MongoRepository) GetUserInfo(ctx context.Context, userID int64) (models
 	}
 
 	res.PassHash = ""
+	res.PasswordHistory = nil
 
 	return res, nil
 }
 
-// UpdateUserInfo updates user information for the user with the provided user ID
-// in the MongoDB database. It first verifies the existence of the user, then merges
-// the updatedUser fields with the existing user data, ensuring that non-updated
-// fields are preserved.
+// UpdateUserInfo sets the provided profile fields of the user with the provided
+// user ID to the values of updatedUser, so a field can also be cleared, and
+// increments the version of the user. Updating the phone number resets its
+// verification. If expectedVersion is provided, the update
+// is applied only if the stored version matches it, and ErrVersionConflict is
+// returned otherwise.
 func (m *MongoRepository) UpdateUserInfo(
 	ctx context.Context,
 	userID int64,
-	updatedUser *models.User) error {
+	updatedUser *models.User,
+	fields []string,
+	expectedVersion *int64) error {
 	const op = "userinfo.mongo.UpdateUserInfo"
 
-	var user models.User
-
 	log := m.log.With(
 		slog.String("op", op),
 	)
@@ -65,51 +72,78 @@ func (m *MongoRepository) UpdateUserInfo(
 		m.Config.Collections[config.UserCollection])
 
 	filter := bson.D{
-		{"user_id", userID},
+		{Key: "user_id", Value: userID},
+		{Key: "deleted_at", Value: nil},
 	}
 
-	res := coll.FindOne(ctx, filter)
-	if res.Err() != nil {
-		return grpcerror.ErrUserNotFound
+	if expectedVersion != nil {
+		filter = append(filter, bson.E{Key: "version", Value: versionFilter(*expectedVersion)})
 	}
 
-	if err := res.Decode(&user); err != nil {
-		log.Error("failed to decode user", sl.Err(err))
-		return fmt.Errorf("failed to decode user: %w", err)
+	// The update is a pipeline so that the verification of the phone number is
+	// only reset when the number changes. Values are wrapped in $literal, as
+	// strings starting with "$" would otherwise be read as field paths.
+	set := bson.D{{Key: "version", Value: incrementedVersion}}
+	for _, field := range fields {
+		switch field {
+		case models.FieldEmail:
+			set = append(set,
+				bson.E{Key: "email", Value: literal(updatedUser.Email)},
+				bson.E{Key: "email_normalized", Value: literal(updatedUser.EmailNormalized)})
+		case models.FieldPhoneNumber:
+			phoneChanged := bson.M{"$ne": bson.A{"$phone_normalized", literal(updatedUser.PhoneNormalized)}}
+			set = append(set,
+				bson.E{Key: "phone_number", Value: literal(updatedUser.PhoneNumber)},
+				bson.E{Key: "phone_normalized", Value: literal(updatedUser.PhoneNormalized)},
+				bson.E{Key: "phone_verified", Value: bson.M{
+					"$cond": bson.A{phoneChanged, false, "$phone_verified"}}},
+				bson.E{Key: "phone_verification", Value: bson.M{
+					"$cond": bson.A{phoneChanged, "$$REMOVE", "$phone_verification"}}})
+		case models.FieldName:
+			set = append(set, bson.E{Key: "name", Value: literal(updatedUser.Name)})
+		case models.FieldSurname:
+			set = append(set, bson.E{Key: "surname", Value: literal(updatedUser.Surname)})
+		default:
+			return fmt.Errorf("%w: %s", grpcerror.ErrInvalidFieldMask, field)
+		}
 	}
 
-	if updatedUser.Email == "" {
-		updatedUser.Email = user.Email
+	if updatedUser.DataKey != nil {
+		set = append(set, bson.E{Key: "data_key", Value: literal(updatedUser.DataKey)})
 	}
 
-	checkUpdateInfo(&user, updatedUser)
+	update := mongo.Pipeline{{{Key: "$set", Value: set}}}
 
-	update := bson.M{
-		"$set": bson.M{
-			"email":        updatedUser.Email,
-			"phone_number": updatedUser.PhoneNumber,
-			"name":         updatedUser.Name,
-			"surname":      updatedUser.Surname,
-		},
+	res, err := coll.UpdateOne(ctx, filter, update)
+	if mongo.IsDuplicateKeyError(err) {
+		return grpcerror.ErrUserExists
 	}
-
-	if _, err := coll.UpdateOne(ctx, filter, update); err != nil {
+	if err != nil {
 		log.Error("failed to update user info", sl.Err(err))
 		return fmt.Errorf("failed to update user info: %w", err)
 	}
 
+	if res.MatchedCount == 0 {
+		return m.conditionError(ctx, userID, grpcerror.ErrVersionConflict)
+	}
+
 	return nil
 }
 
-// ChangePassword updates the password for the user with the provided user ID in
-// the MongoDB database. It verifies the old password, and if successful, replaces
-// it with the new password hash.
-func (m *MongoRepository) ChangePassword(
-	ctx context.Context,
-	userID int64,
-	oldPasswordSalted,
-	newPasswordHash string) error {
-	const op = "userinfo.mongo.ChangePassword"
+// versionFilter matches the expected version. Users created before versioning
+// have no version and are matched as version 0.
+func versionFilter(expected int64) interface{} {
+	if expected == 0 {
+		return bson.M{"$in": bson.A{0, nil}}
+	}
+
+	return expected
+}
+
+// GetPassHash retrieves the password hash of the user with the provided user ID
+// from the MongoDB database along with the version of the pepper it was created with.
+func (m *MongoRepository) GetPassHash(ctx context.Context, userID int64) (string, int, error) {
+	const op = "userinfo.mongo.GetPassHash"
 
 	var user models.User
 
@@ -121,38 +155,118 @@ func (m *MongoRepository) ChangePassword(
 		m.Config.Collections[config.UserCollection])
 
 	filter := bson.D{
-		{"user_id", userID},
+		{Key: "user_id", Value: userID},
+		{Key: "deleted_at", Value: nil},
 	}
 
 	res := coll.FindOne(ctx, filter)
 	if res.Err() != nil {
-		return grpcerror.ErrUserNotFound
+		return "", 0, grpcerror.ErrUserNotFound
 	}
 
 	if err := res.Decode(&user); err != nil {
 		log.Error("failed to decode user", sl.Err(err))
-		return fmt.Errorf("failed to decode user: %w", err)
+		return "", 0, fmt.Errorf("failed to decode user: %w", err)
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PassHash), []byte(oldPasswordSalted)); err != nil {
-		log.Info(grpcerror.ErrInvalidPassword.Error(), slog.Int64("user_id", userID))
-		return grpcerror.ErrInvalidPassword
+	return user.PassHash, user.PepperVersion, nil
+}
+
+// GetPasswordHistory retrieves the previous password hashes of the user with the
+// provided user ID, the most recent first. The current hash is not included.
+func (m *MongoRepository) GetPasswordHistory(ctx context.Context, userID int64) ([]models.PasswordHash, error) {
+	const op = "userinfo.mongo.GetPasswordHistory"
+
+	var user models.User
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.UserCollection])
+
+	filter := bson.D{
+		{Key: "user_id", Value: userID},
+		{Key: "deleted_at", Value: nil},
 	}
 
-	update := bson.M{
-		"$set": bson.M{"pass_hash": newPasswordHash},
+	res := coll.FindOne(ctx, filter)
+	if res.Err() != nil {
+		return nil, grpcerror.ErrUserNotFound
+	}
+
+	if err := res.Decode(&user); err != nil {
+		log.Error("failed to decode user", sl.Err(err))
+		return nil, fmt.Errorf("failed to decode user: %w", err)
 	}
 
-	if _, err := coll.UpdateOne(ctx, filter, update); err != nil {
+	return user.PasswordHistory, nil
+}
+
+// ChangePassword replaces the password hash for the user with the provided user ID
+// in the MongoDB database. The replaced hash is atomically pushed to the front of
+// the password history, which is trimmed to historySize entries. The old password
+// must be verified by the caller.
+func (m *MongoRepository) ChangePassword(
+	ctx context.Context,
+	userID int64,
+	newPasswordHash string,
+	pepperVersion int,
+	historySize int) error {
+	const op = "userinfo.mongo.ChangePassword"
+
+	if historySize <= 0 {
+		return m.UpdatePassHash(ctx, userID, newPasswordHash, pepperVersion)
+	}
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.UserCollection])
+
+	filter := bson.D{
+		{Key: "user_id", Value: userID},
+		{Key: "deleted_at", Value: nil},
+	}
+
+	previous := bson.M{
+		"hash":           "$pass_hash",
+		"pepper_version": bson.M{"$ifNull": bson.A{"$pepper_version", 0}},
+	}
+
+	update := bson.A{
+		bson.M{"$set": bson.M{
+			"password_history": bson.M{"$slice": bson.A{
+				bson.M{"$concatArrays": bson.A{
+					bson.A{previous},
+					bson.M{"$ifNull": bson.A{"$password_history", bson.A{}}},
+				}},
+				historySize,
+			}},
+			"pass_hash":      newPasswordHash,
+			"pepper_version": pepperVersion,
+		}},
+	}
+
+	res, err := coll.UpdateOne(ctx, filter, update)
+	if err != nil {
 		log.Error("failed to change password", sl.Err(err))
 		return fmt.Errorf("failed to update password: %w", err)
 	}
 
+	if res.MatchedCount == 0 {
+		return grpcerror.ErrUserNotFound
+	}
+
 	return nil
 }
 
-// DeleteUser removes a user from the MongoDB database using the provided user ID.
-// It first checks if the user exists, and if found, deletes the user from the database.
+// DeleteUser soft-deletes the user with the provided user ID: the user is marked
+// as deleted, hidden from lookups and sign-in, and all of its sessions are revoked.
+// The user is removed for good by PurgeUser once the grace period is over.
 func (m *MongoRepository) DeleteUser(ctx context.Context, userID int64) error {
 	const op = "userinfo.mongo.DeleteUser"
 
@@ -164,114 +278,244 @@ func (m *MongoRepository) DeleteUser(ctx context.Context, userID int64) error {
 		m.Config.Collections[config.UserCollection])
 
 	filter := bson.D{
-		{"user_id", userID},
+		{Key: "user_id", Value: userID},
+		{Key: "deleted_at", Value: nil},
 	}
 
-	if res := coll.FindOne(ctx, filter); res.Err() != nil {
-		return grpcerror.ErrUserNotFound
+	update := bson.M{
+		"$set": bson.M{"deleted_at": time.Now().UTC()},
 	}
 
-	if _, err := coll.DeleteOne(ctx, filter); err != nil {
+	res, err := coll.UpdateOne(ctx, filter, update)
+	if err != nil {
 		log.Error("failed to delete user", sl.Err(err), slog.Int64("user_id", userID))
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
 
+	if res.MatchedCount == 0 {
+		return grpcerror.ErrUserNotFound
+	}
+
+	sessions := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.SessionCollection])
+
+	_, err = sessions.UpdateMany(ctx,
+		bson.D{{Key: "user_id", Value: userID}},
+		bson.M{"$set": bson.M{"revoked": true}})
+	if err != nil {
+		log.Error("failed to revoke sessions of deleted user",
+			sl.Err(err), slog.Int64("user_id", userID))
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
 	return nil
 }
 
-func (m *MongoRepository) AddFamily(ctx context.Context, user *models.User, familyID int64) error {
-	const op = "userinfo.mongo.AddFamily"
+// RestoreUser reverts the soft deletion of the user with the provided user ID,
+// provided that the user was deleted after deletedAfter and is not being purged.
+func (m *MongoRepository) RestoreUser(ctx context.Context, userID int64, deletedAfter time.Time) error {
+	const op = "userinfo.mongo.RestoreUser"
+
+	var user models.User
 
 	log := m.log.With(
 		slog.String("op", op),
 	)
 
-	user.FamilyIDs = append(user.FamilyIDs, familyID)
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.UserCollection])
+
+	filter := bson.D{
+		{Key: "user_id", Value: userID},
+		{Key: "deleted_at", Value: bson.M{"$gt": deletedAfter}},
+		{Key: "purging", Value: bson.M{"$ne": true}},
+	}
+
+	update := bson.M{
+		"$unset": bson.M{"deleted_at": ""},
+	}
+
+	res, err := coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		log.Error("failed to restore user", sl.Err(err), slog.Int64("user_id", userID))
+		return fmt.Errorf("failed to restore user: %w", err)
+	}
+
+	if res.MatchedCount == 1 {
+		return nil
+	}
+
+	err = coll.FindOne(ctx, bson.D{{Key: "user_id", Value: userID}}).Decode(&user)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return grpcerror.ErrUserNotFound
+	}
+	if err != nil {
+		log.Error("failed to decode user", sl.Err(err))
+		return fmt.Errorf("failed to decode user: %w", err)
+	}
+
+	if user.DeletedAt == nil {
+		return grpcerror.ErrUserNotDeleted
+	}
+
+	return grpcerror.ErrRestoreExpired
+}
+
+// SetStatus changes the account status of the user with the provided user ID.
+// The reason and the suspension expiry are removed when they are not provided.
+func (m *MongoRepository) SetStatus(
+	ctx context.Context,
+	userID int64,
+	status models.AccountStatus,
+	reason string,
+	until *time.Time) error {
+	const op = "userinfo.mongo.SetStatus"
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
 
 	coll := m.Db.Database(m.Config.DBName).Collection(
 		m.Config.Collections[config.UserCollection])
 
 	filter := bson.D{
-		{"user_id", user.ID},
+		{Key: "user_id", Value: userID},
+		{Key: "deleted_at", Value: nil},
+	}
+
+	set := bson.M{"status": status}
+	unset := bson.M{}
+
+	if reason != "" {
+		set["status_reason"] = reason
+	} else {
+		unset["status_reason"] = ""
+	}
+
+	if until != nil {
+		set["suspended_until"] = until.UTC()
+	} else {
+		unset["suspended_until"] = ""
 	}
 
-	update := bson.D{
-		{"$set", bson.D{
-			{"family_ids", user.FamilyIDs}},
-		},
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
 	}
 
-	_, err := coll.UpdateOne(ctx, filter, update)
+	res, err := coll.UpdateOne(ctx, filter, update)
 	if err != nil {
-		log.Error("failed to add family", sl.Err(err))
-		return fmt.Errorf("%s: %w", op, err)
+		log.Error("failed to set user status", sl.Err(err), slog.Int64("user_id", userID))
+		return fmt.Errorf("failed to set user status: %w", err)
+	}
+
+	if res.MatchedCount == 0 {
+		return grpcerror.ErrUserNotFound
 	}
 
 	return nil
 }
 
-func (m *MongoRepository) DeleteFamily(ctx context.Context, user *models.User, familyID int64) error {
-	const op = "userinfo.mongo.DeleteFamily"
+// AddFamily atomically adds the family ID to the family list of the user with the
+// provided user ID. The update is conditional on the family not being in the list
+// yet, so concurrent additions and removals never overwrite each other. A missing
+// or null family list, as stored for users signed up before families existed,
+// is treated as empty.
+func (m *MongoRepository) AddFamily(ctx context.Context, userID, familyID int64) error {
+	const op = "userinfo.mongo.AddFamily"
 
 	log := m.log.With(
 		slog.String("op", op),
 	)
 
-	newFam := removeFamily(user.FamilyIDs, familyID)
-
 	coll := m.Db.Database(m.Config.DBName).Collection(
 		m.Config.Collections[config.UserCollection])
 
 	filter := bson.D{
-		{"user_id", user.ID},
+		{Key: "user_id", Value: userID},
+		{Key: "deleted_at", Value: nil},
+		{Key: "family_ids", Value: bson.M{"$ne": familyID}},
 	}
 
-	update := bson.D{
-		{"$set", bson.D{
-			{"family_ids", newFam}},
-		},
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"family_ids": bson.M{"$setUnion": bson.A{
+				bson.M{"$ifNull": bson.A{"$family_ids", bson.A{}}},
+				bson.A{familyID},
+			}},
+		}}},
 	}
 
-	_, err := coll.UpdateOne(ctx, filter, update)
+	res, err := coll.UpdateOne(ctx, filter, update)
 	if err != nil {
-		log.Error("failed to delete family", sl.Err(err))
+		log.Error("failed to add family", sl.Err(err))
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	if res.ModifiedCount == 0 {
+		return m.conditionError(ctx, userID, grpcerror.ErrUserInFamily)
+	}
+
 	return nil
 }
 
-func removeFamily(families []int64, familyID int64) []int64 {
-	if len(families) == 1 {
-		return []int64{}
+// DeleteFamily atomically removes the family ID from the family list of the user
+// with the provided user ID. The update is conditional on the family being in the list.
+func (m *MongoRepository) DeleteFamily(ctx context.Context, userID, familyID int64) error {
+	const op = "userinfo.mongo.DeleteFamily"
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.UserCollection])
+
+	filter := bson.D{
+		{Key: "user_id", Value: userID},
+		{Key: "deleted_at", Value: nil},
+		{Key: "family_ids", Value: familyID},
 	}
 
-	for i, fID := range families {
-		if fID == familyID {
-			return append(families[:i], families[i+1:]...)
-		}
+	update := bson.M{
+		"$pull": bson.M{"family_ids": familyID},
+	}
+
+	res, err := coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		log.Error("failed to delete family", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if res.ModifiedCount == 0 {
+		return m.conditionError(ctx, userID, grpcerror.ErrUserNotInFamily)
 	}
 
 	return nil
 }
 
-// checkUpdateInfo ensures that the provided updateInfo object contains valid
-// information for updating a user. If any field in updateInfo is empty, it is
-// replaced with the corresponding field from the original user object.
-func checkUpdateInfo(user, updateInfo *models.User) {
-	if updateInfo.Email == "" {
-		updateInfo.Email = user.Email
-	}
+// conditionError resolves why a conditional update did not match the user: either
+// the user does not exist, or the condition (family membership, version) was not met.
+func (m *MongoRepository) conditionError(ctx context.Context, userID int64, conditionErr error) error {
+	const op = "userinfo.mongo.conditionError"
+
+	coll := m.Db.Database(m.Config.DBName).Collection(
+		m.Config.Collections[config.UserCollection])
 
-	if updateInfo.Name == "" {
-		updateInfo.Name = user.Name
+	filter := bson.D{
+		{Key: "user_id", Value: userID},
+		{Key: "deleted_at", Value: nil},
 	}
 
-	if updateInfo.Surname == "" {
-		updateInfo.Surname = user.Surname
+	count, err := coll.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	if err != nil {
+		m.log.Error("failed to check user existence", slog.String("op", op), sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	if updateInfo.PhoneNumber == "" {
-		updateInfo.PhoneNumber = user.PhoneNumber
+	if count == 0 {
+		return grpcerror.ErrUserNotFound
 	}
+
+	return conditionErr
 }
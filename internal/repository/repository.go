@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
 )
@@ -10,11 +11,25 @@ type Repository interface {
 	AuthRepository
 	PermissionsRepository
 	UserInfoRepository
+	SessionRepository
+	PurgeRepository
+	LoginHistoryRepository
+	EmailChangeRepository
+	PhoneVerificationRepository
+	AttributeRepository
+	AvatarRepository
+	AuditRepository
+	AccessTokenRepository
 }
 
 type AuthRepository interface {
-	Login(ctx context.Context, email, passHash string) (models.User, error)
+	GetUserByEmail(ctx context.Context, normalizedEmail string) (models.User, error)
+	GetUserByPhone(ctx context.Context, normalizedPhone string) (models.User, error)
+	EmailExists(ctx context.Context, normalizedEmail string) (bool, error)
 	CreateUser(ctx context.Context, user *models.User) (int64, error)
+	UpdatePassHash(ctx context.Context, userID int64, passHash string, pepperVersion int) error
+	CountUsersByPepperVersion(ctx context.Context) (map[int]int64, error)
+	UpdateLastLogin(ctx context.Context, userID int64, at time.Time) error
 }
 
 type PermissionsRepository interface {
@@ -23,9 +38,79 @@ type PermissionsRepository interface {
 
 type UserInfoRepository interface {
 	GetUserInfo(ctx context.Context, userID int64) (models.User, error)
-	UpdateUserInfo(ctx context.Context, userID int64, updatedUser *models.User) error
-	ChangePassword(ctx context.Context, userID int64, oldPassword, newPasswordHash string) error
-	AddFamily(ctx context.Context, user *models.User, familyID int64) error
-	DeleteFamily(ctx context.Context, user *models.User, familyID int64) error
+	UpdateUserInfo(
+		ctx context.Context,
+		userID int64,
+		updatedUser *models.User,
+		fields []string,
+		expectedVersion *int64) error
+	GetPassHash(ctx context.Context, userID int64) (string, int, error)
+	GetPasswordHistory(ctx context.Context, userID int64) ([]models.PasswordHash, error)
+	ChangePassword(ctx context.Context, userID int64, newPasswordHash string, pepperVersion, historySize int) error
+	AddFamily(ctx context.Context, userID, familyID int64) error
+	DeleteFamily(ctx context.Context, userID, familyID int64) error
 	DeleteUser(ctx context.Context, userID int64) error
+	RestoreUser(ctx context.Context, userID int64, deletedAfter time.Time) error
+	SetStatus(ctx context.Context, userID int64, status models.AccountStatus, reason string, until *time.Time) error
+}
+
+type SessionRepository interface {
+	CreateSession(ctx context.Context, session *models.Session) (string, error)
+	GetSessions(ctx context.Context, userID int64) ([]models.Session, error)
+	GetAllSessions(ctx context.Context, userID int64) ([]models.Session, error)
+	GetSession(ctx context.Context, sessionID string) (models.Session, error)
+	TouchSession(ctx context.Context, sessionID string, at time.Time) error
+	RevokeSession(ctx context.Context, userID int64, sessionID string) error
+	RevokeAllOtherSessions(ctx context.Context, userID int64, currentSessionID string) error
+}
+
+type PurgeRepository interface {
+	GetUsersToPurge(ctx context.Context, deletedBefore time.Time) ([]models.User, error)
+	PurgeUser(ctx context.Context, userID int64) error
+}
+
+type EmailChangeRepository interface {
+	SetEmailChange(ctx context.Context, userID int64, change *models.EmailChange) error
+	ConfirmEmailChange(ctx context.Context, tokenHash string, now time.Time) (int64, error)
+	CancelEmailChange(ctx context.Context, tokenHash string, now time.Time) (int64, error)
+}
+
+type PhoneVerificationRepository interface {
+	SetPhoneVerification(ctx context.Context, userID int64, verification *models.PhoneVerification) error
+	UsePhoneVerificationAttempt(
+		ctx context.Context,
+		userID int64,
+		now time.Time,
+		maxAttempts int) (models.PhoneVerification, error)
+	ConfirmPhone(ctx context.Context, userID int64, codeHash string) error
+}
+
+type AttributeRepository interface {
+	SetAttributes(ctx context.Context, userID int64, attrs []models.Attribute) error
+}
+
+type AvatarRepository interface {
+	SaveAvatar(ctx context.Context, userID int64, avatar *models.Avatar, images map[int][]byte) error
+	GetAvatarImage(ctx context.Context, userID int64, uploadID string, size int) ([]byte, error)
+	DeleteAvatar(ctx context.Context, userID int64) error
+}
+
+type AuditRepository interface {
+	RecordAuditEvent(ctx context.Context, event *models.AuditEvent) error
+}
+
+type AccessTokenRepository interface {
+	CreateAccessToken(ctx context.Context, token *models.AccessToken) (string, error)
+	CountAccessTokens(ctx context.Context, userID int64, now time.Time) (int64, error)
+	GetAccessTokens(ctx context.Context, userID int64) ([]models.AccessToken, error)
+	GetAccessToken(ctx context.Context, tokenID string) (models.AccessToken, error)
+	GetAccessTokenByHash(ctx context.Context, hash string) (models.AccessToken, error)
+	TouchAccessToken(ctx context.Context, tokenID string, at time.Time) error
+	RevokeAccessToken(ctx context.Context, tokenID string) error
+	RevokeAllAccessTokens(ctx context.Context, userID int64) error
+}
+
+type LoginHistoryRepository interface {
+	RecordLoginAttempt(ctx context.Context, attempt *models.LoginAttempt) error
+	GetLoginHistory(ctx context.Context, userID int64, limit int64) ([]models.LoginAttempt, error)
 }
@@ -0,0 +1,319 @@
+package accesstoken
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/clients"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/jwt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/token"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/repository"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const maxNameLength = 64
+
+type AccessTokenService struct {
+	log     *slog.Logger
+	users   repository.UserInfoRepository
+	tokens  repository.AccessTokenRepository
+	manager *jwt.Manager
+	clients *clients.Registry
+	cfg     *config.AccessTokenConfig
+	now     func() time.Time
+}
+
+// New creates and returns a new instance of the AccessTokenService
+func New(
+	log *slog.Logger,
+	users repository.UserInfoRepository,
+	tokens repository.AccessTokenRepository,
+	manager *jwt.Manager,
+	clientRegistry *clients.Registry,
+	cfg *config.AccessTokenConfig,
+) *AccessTokenService {
+	return &AccessTokenService{
+		log:     log,
+		users:   users,
+		tokens:  tokens,
+		manager: manager,
+		clients: clientRegistry,
+		cfg:     cfg,
+		now:     time.Now,
+	}
+}
+
+// CreateToken creates a personal access token for the user with the provided user
+// ID on behalf of the authenticated user making the request. A zero user ID stands
+// for the user making the request. Access tokens and impersonation tokens cannot be
+// used to create further tokens. It delegates to the CreateUserToken method.
+func (s *AccessTokenService) CreateToken(
+	ctx context.Context,
+	userID int64,
+	name string,
+	scopes []string,
+	ttl time.Duration) (string, models.AccessToken, error) {
+	claims, err := s.manager.GetClaims(ctx)
+	if err != nil {
+		return "", models.AccessToken{}, err
+	}
+
+	if _, ok := jwt.AccessTokenIDFromClaims(claims); ok {
+		return "", models.AccessToken{}, grpcerror.ErrForbidden
+	}
+	if _, impersonating, _ := jwt.ActorIDFromClaims(claims); impersonating {
+		return "", models.AccessToken{}, grpcerror.ErrImpersonationDenied
+	}
+
+	callerID, err := jwt.UserIDFromClaims(claims)
+	if err != nil {
+		return "", models.AccessToken{}, err
+	}
+
+	return s.CreateUserToken(ctx, callerID, userID, name, scopes, ttl)
+}
+
+// CreateUserToken creates a named personal access token with the provided scopes
+// for the user with the provided user ID on behalf of the caller with the provided
+// caller ID. Users create tokens for themselves, and admins for other users that
+// are not admins, e.g. service accounts. No scopes stand for every scope of the
+// service, and a zero TTL for the longest allowed one. The token is returned only
+// here; only its hash is stored.
+func (s *AccessTokenService) CreateUserToken(
+	ctx context.Context,
+	callerID, userID int64,
+	name string,
+	scopes []string,
+	ttl time.Duration) (string, models.AccessToken, error) {
+	const op = "accesstoken.service.CreateUserToken"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("caller_id", callerID),
+		slog.Int64("user_id", userID),
+	)
+
+	if userID == 0 {
+		userID = callerID
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxNameLength {
+		return "", models.AccessToken{}, grpcerror.ErrInvalidTokenName
+	}
+
+	if ttl == 0 {
+		ttl = s.cfg.MaxTTL
+	}
+	if ttl < 0 || ttl > s.cfg.MaxTTL {
+		return "", models.AccessToken{}, grpcerror.ErrInvalidTokenTTL
+	}
+
+	grant, err := s.clients.Resolve(models.Grant{Scopes: scopes})
+	if err != nil {
+		return "", models.AccessToken{}, err
+	}
+
+	if err = s.checkAccess(ctx, callerID, userID); err != nil {
+		return "", models.AccessToken{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := s.now().UTC()
+
+	count, err := s.tokens.CountAccessTokens(ctx, userID, now)
+	if err != nil {
+		return "", models.AccessToken{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if count >= int64(s.cfg.MaxPerUser) {
+		return "", models.AccessToken{}, grpcerror.ErrTooManyTokens
+	}
+
+	secret, err := token.Generate()
+	if err != nil {
+		log.Error("failed to generate access token", sl.Err(err))
+		return "", models.AccessToken{}, fmt.Errorf("%s: %w", op, err)
+	}
+	secret = models.AccessTokenPrefix + secret
+
+	accessToken := models.AccessToken{
+		UserID:    userID,
+		Name:      name,
+		Hash:      token.Hash(secret),
+		Scopes:    grant.Scopes,
+		CreatedBy: callerID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+
+	if _, err = s.tokens.CreateAccessToken(ctx, &accessToken); err != nil {
+		return "", models.AccessToken{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("access token created", slog.String("token_id", accessToken.ID))
+
+	accessToken.Hash = ""
+
+	return secret, accessToken, nil
+}
+
+// ListTokens returns the personal access tokens of the user with the provided user
+// ID that were not revoked. A zero user ID stands for the authenticated user making
+// the request. It delegates to the ListUserTokens method.
+func (s *AccessTokenService) ListTokens(ctx context.Context, userID int64) ([]models.AccessToken, error) {
+	callerID, err := s.manager.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.ListUserTokens(ctx, callerID, userID)
+}
+
+// ListUserTokens returns the personal access tokens of the user with the provided
+// user ID that were not revoked, expired ones included, to the caller with the
+// provided caller ID. The hashes of the tokens are left out.
+func (s *AccessTokenService) ListUserTokens(
+	ctx context.Context,
+	callerID, userID int64) ([]models.AccessToken, error) {
+	const op = "accesstoken.service.ListUserTokens"
+
+	if userID == 0 {
+		userID = callerID
+	}
+
+	if err := s.checkAccess(ctx, callerID, userID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	tokens, err := s.tokens.GetAccessTokens(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for i := range tokens {
+		tokens[i].Hash = ""
+	}
+
+	return tokens, nil
+}
+
+// RevokeToken revokes the personal access token with the provided ID on behalf of
+// the authenticated user making the request. It delegates to the RevokeUserToken
+// method.
+func (s *AccessTokenService) RevokeToken(ctx context.Context, tokenID string) error {
+	callerID, err := s.manager.GetUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.RevokeUserToken(ctx, callerID, tokenID)
+}
+
+// RevokeUserToken revokes the personal access token with the provided ID on behalf
+// of the caller with the provided caller ID. Tokens the caller cannot manage are
+// reported as not found.
+func (s *AccessTokenService) RevokeUserToken(ctx context.Context, callerID int64, tokenID string) error {
+	const op = "accesstoken.service.RevokeUserToken"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("caller_id", callerID),
+		slog.String("token_id", tokenID),
+	)
+
+	accessToken, err := s.tokens.GetAccessToken(ctx, tokenID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if accessToken.Revoked {
+		return grpcerror.ErrTokenNotFound
+	}
+
+	err = s.checkAccess(ctx, callerID, accessToken.UserID)
+	if errors.Is(err, grpcerror.ErrForbidden) {
+		return grpcerror.ErrTokenNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = s.tokens.RevokeAccessToken(ctx, tokenID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("access token revoked", slog.Int64("user_id", accessToken.UserID))
+
+	return nil
+}
+
+// Authenticate returns the personal access token the provided secret belongs to.
+// Unknown, revoked and expired tokens are rejected with ErrInvalidToken. The last
+// use of the token is stored at most once per touch interval; failing to store it
+// does not reject the token.
+func (s *AccessTokenService) Authenticate(ctx context.Context, secret string) (models.AccessToken, error) {
+	const op = "accesstoken.service.Authenticate"
+
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	if !strings.HasPrefix(secret, models.AccessTokenPrefix) {
+		return models.AccessToken{}, grpcerror.ErrInvalidToken
+	}
+
+	accessToken, err := s.tokens.GetAccessTokenByHash(ctx, token.Hash(secret))
+	if errors.Is(err, grpcerror.ErrTokenNotFound) {
+		return models.AccessToken{}, grpcerror.ErrInvalidToken
+	}
+	if err != nil {
+		return models.AccessToken{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := s.now()
+
+	if accessToken.Revoked || !now.Before(accessToken.ExpiresAt) {
+		return models.AccessToken{}, grpcerror.ErrInvalidToken
+	}
+
+	if accessToken.LastUsedAt == nil || now.Sub(*accessToken.LastUsedAt) >= s.cfg.TouchInterval {
+		if err = s.tokens.TouchAccessToken(ctx, accessToken.ID, now); err != nil {
+			log.Warn("failed to store last use of access token",
+				sl.Err(err), slog.String("token_id", accessToken.ID))
+		}
+	}
+
+	return accessToken, nil
+}
+
+// checkAccess verifies that the caller with the provided caller ID may manage the
+// tokens of the user with the provided user ID: users manage their own tokens, and
+// admins the tokens of users that are not admins.
+func (s *AccessTokenService) checkAccess(ctx context.Context, callerID, userID int64) error {
+	if callerID == userID {
+		return nil
+	}
+
+	caller, err := s.users.GetUserInfo(ctx, callerID)
+	if err != nil {
+		return err
+	}
+	if caller.Role != models.AdminRole {
+		return grpcerror.ErrForbidden
+	}
+
+	user, err := s.users.GetUserInfo(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.Role == models.AdminRole {
+		return grpcerror.ErrForbidden
+	}
+
+	return nil
+}
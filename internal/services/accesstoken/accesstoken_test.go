@@ -0,0 +1,280 @@
+package accesstoken
+
+import (
+	"context"
+	"errors"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/clients"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/jwt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services/servicetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	adminID      = 1
+	otherAdminID = 2
+	userID       = 3
+	otherUserID  = 4
+)
+
+type fakeRepo struct {
+	*servicetest.Repository
+	tokens  map[string]models.AccessToken
+	touched int
+}
+
+func (r *fakeRepo) CreateAccessToken(_ context.Context, token *models.AccessToken) (string, error) {
+	token.ID = strconv.Itoa(len(r.tokens) + 1)
+	r.tokens[token.ID] = *token
+	return token.ID, nil
+}
+
+func (r *fakeRepo) CountAccessTokens(_ context.Context, userID int64, now time.Time) (int64, error) {
+	var count int64
+	for _, token := range r.tokens {
+		if token.UserID == userID && !token.Revoked && token.ExpiresAt.After(now) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *fakeRepo) GetAccessTokens(_ context.Context, userID int64) ([]models.AccessToken, error) {
+	tokens := make([]models.AccessToken, 0)
+	for _, token := range r.tokens {
+		if token.UserID == userID && !token.Revoked {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens, nil
+}
+
+func (r *fakeRepo) GetAccessToken(_ context.Context, tokenID string) (models.AccessToken, error) {
+	token, ok := r.tokens[tokenID]
+	if !ok {
+		return models.AccessToken{}, grpcerror.ErrTokenNotFound
+	}
+	return token, nil
+}
+
+func (r *fakeRepo) GetAccessTokenByHash(_ context.Context, hash string) (models.AccessToken, error) {
+	for _, token := range r.tokens {
+		if token.Hash == hash {
+			return token, nil
+		}
+	}
+	return models.AccessToken{}, grpcerror.ErrTokenNotFound
+}
+
+func (r *fakeRepo) TouchAccessToken(_ context.Context, tokenID string, at time.Time) error {
+	token := r.tokens[tokenID]
+	token.LastUsedAt = &at
+	r.tokens[tokenID] = token
+	r.touched++
+	return nil
+}
+
+func (r *fakeRepo) RevokeAccessToken(_ context.Context, tokenID string) error {
+	token, ok := r.tokens[tokenID]
+	if !ok || token.Revoked {
+		return grpcerror.ErrTokenNotFound
+	}
+	token.Revoked = true
+	r.tokens[tokenID] = token
+	return nil
+}
+
+func newTestService(t *testing.T) (*AccessTokenService, *fakeRepo, *jwt.Manager) {
+	t.Helper()
+
+	repo := &fakeRepo{
+		Repository: servicetest.NewRepository(
+			models.User{ID: adminID, Role: models.AdminRole},
+			models.User{ID: otherAdminID, Role: models.AdminRole},
+			models.User{ID: userID, Role: models.UserRole},
+			models.User{ID: otherUserID, Role: models.UserRole},
+		),
+		tokens: make(map[string]models.AccessToken),
+	}
+
+	registry, err := clients.NewRegistry(&config.TokenConfig{
+		Audience: "sso",
+		Scopes:   []string{"profile:read", "profile:write"},
+	}, nil)
+	require.NoError(t, err)
+
+	manager := servicetest.Manager()
+
+	s := New(servicetest.Logger(), repo, repo, manager, registry, &config.AccessTokenConfig{
+		MaxTTL:        24 * time.Hour,
+		MaxPerUser:    2,
+		TouchInterval: time.Minute,
+	})
+
+	return s, repo, manager
+}
+
+func TestCreateUserToken(t *testing.T) {
+	s, repo, _ := newTestService(t)
+	ctx := context.Background()
+
+	secret, token, err := s.CreateUserToken(ctx, userID, 0, " deploy ", []string{"profile:read"}, time.Hour)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(secret, models.AccessTokenPrefix))
+	assert.Equal(t, "deploy", token.Name)
+	assert.Equal(t, int64(userID), token.UserID)
+	assert.Equal(t, []string{"profile:read"}, token.Scopes)
+	assert.Empty(t, token.Hash)
+
+	stored := repo.tokens[token.ID]
+	assert.NotEmpty(t, stored.Hash)
+	assert.NotContains(t, stored.Hash, secret)
+
+	_, token, err = s.CreateUserToken(ctx, adminID, userID, "service", nil, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"profile:read", "profile:write"}, token.Scopes)
+	assert.Equal(t, int64(adminID), token.CreatedBy)
+	assert.Equal(t, 24*time.Hour, token.ExpiresAt.Sub(token.CreatedAt))
+
+	_, _, err = s.CreateUserToken(ctx, userID, 0, "third", nil, 0)
+	assert.True(t, errors.Is(err, grpcerror.ErrTooManyTokens))
+}
+
+func TestCreateUserToken_Rejected(t *testing.T) {
+	s, _, _ := newTestService(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		callerID int64
+		userID   int64
+		token    string
+		scopes   []string
+		ttl      time.Duration
+		err      error
+	}{
+		{"empty name", userID, 0, " ", nil, 0, grpcerror.ErrInvalidTokenName},
+		{"long name", userID, 0, strings.Repeat("a", 65), nil, 0, grpcerror.ErrInvalidTokenName},
+		{"too long ttl", userID, 0, "a", nil, 25 * time.Hour, grpcerror.ErrInvalidTokenTTL},
+		{"negative ttl", userID, 0, "a", nil, -time.Hour, grpcerror.ErrInvalidTokenTTL},
+		{"unknown scope", userID, 0, "a", []string{"users:write"}, 0, grpcerror.ErrInvalidScope},
+		{"other user", userID, otherUserID, "a", nil, 0, grpcerror.ErrForbidden},
+		{"other admin", adminID, otherAdminID, "a", nil, 0, grpcerror.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := s.CreateUserToken(ctx, tt.callerID, tt.userID, tt.token, tt.scopes, tt.ttl)
+			assert.True(t, errors.Is(err, tt.err), err)
+		})
+	}
+}
+
+func TestCreateToken_WithAccessToken(t *testing.T) {
+	s, repo, _ := newTestService(t)
+
+	ctx := jwt.ContextWithClaims(context.Background(),
+		jwt.AccessTokenClaims(repo.Users[userID], "1", []string{"profile:read"}))
+
+	_, _, err := s.CreateToken(ctx, 0, "nested", nil, 0)
+	assert.True(t, errors.Is(err, grpcerror.ErrForbidden))
+}
+
+func TestCreateToken_Impersonated(t *testing.T) {
+	s, repo, manager := newTestService(t)
+
+	token, err := manager.NewImpersonationToken(repo.Users[userID], "session", adminID,
+		models.Grant{Audience: "sso"}, time.Minute)
+	require.NoError(t, err)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+
+	_, _, err = s.CreateToken(ctx, 0, "persist", nil, 0)
+	assert.True(t, errors.Is(err, grpcerror.ErrImpersonationDenied))
+}
+
+func TestAuthenticate(t *testing.T) {
+	s, repo, _ := newTestService(t)
+	ctx := context.Background()
+
+	now := time.Now()
+	s.now = func() time.Time { return now }
+
+	secret, created, err := s.CreateUserToken(ctx, userID, 0, "ci", nil, time.Hour)
+	require.NoError(t, err)
+
+	token, err := s.Authenticate(ctx, secret)
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, token.ID)
+	assert.Equal(t, 1, repo.touched)
+
+	_, err = s.Authenticate(ctx, secret)
+	require.NoError(t, err)
+	assert.Equal(t, 1, repo.touched, "last use is stored once per touch interval")
+
+	now = now.Add(2 * time.Minute)
+	_, err = s.Authenticate(ctx, secret)
+	require.NoError(t, err)
+	assert.Equal(t, 2, repo.touched)
+
+	for name, secret := range map[string]string{
+		"no prefix": strings.TrimPrefix(secret, models.AccessTokenPrefix),
+		"unknown":   models.AccessTokenPrefix + "unknown",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := s.Authenticate(ctx, secret)
+			assert.True(t, errors.Is(err, grpcerror.ErrInvalidToken))
+		})
+	}
+
+	now = now.Add(time.Hour)
+	_, err = s.Authenticate(ctx, secret)
+	assert.True(t, errors.Is(err, grpcerror.ErrInvalidToken), "expired")
+}
+
+func TestRevokeUserToken(t *testing.T) {
+	s, _, _ := newTestService(t)
+	ctx := context.Background()
+
+	secret, token, err := s.CreateUserToken(ctx, userID, 0, "ci", nil, 0)
+	require.NoError(t, err)
+
+	err = s.RevokeUserToken(ctx, otherUserID, token.ID)
+	assert.True(t, errors.Is(err, grpcerror.ErrTokenNotFound))
+
+	require.NoError(t, s.RevokeUserToken(ctx, adminID, token.ID))
+
+	err = s.RevokeUserToken(ctx, userID, token.ID)
+	assert.True(t, errors.Is(err, grpcerror.ErrTokenNotFound))
+
+	_, err = s.Authenticate(ctx, secret)
+	assert.True(t, errors.Is(err, grpcerror.ErrInvalidToken))
+
+	tokens, err := s.ListUserTokens(ctx, userID, 0)
+	require.NoError(t, err)
+	assert.Empty(t, tokens)
+}
+
+func TestListUserTokens(t *testing.T) {
+	s, _, _ := newTestService(t)
+	ctx := context.Background()
+
+	_, _, err := s.CreateUserToken(ctx, userID, 0, "ci", nil, 0)
+	require.NoError(t, err)
+
+	tokens, err := s.ListUserTokens(ctx, adminID, userID)
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	assert.Empty(t, tokens[0].Hash)
+
+	_, err = s.ListUserTokens(ctx, otherUserID, userID)
+	assert.True(t, errors.Is(err, grpcerror.ErrForbidden))
+}
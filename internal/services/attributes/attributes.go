@@ -0,0 +1,157 @@
+package attributes
+
+import (
+	"context"
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/attributes"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/jwt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/repository"
+	"log/slog"
+)
+
+type AttributeService struct {
+	log      *slog.Logger
+	users    repository.UserInfoRepository
+	attrs    repository.AttributeRepository
+	manager  *jwt.Manager
+	registry *attributes.Registry
+}
+
+// New creates and returns a new instance of the AttributeService
+func New(
+	log *slog.Logger,
+	users repository.UserInfoRepository,
+	attrs repository.AttributeRepository,
+	manager *jwt.Manager,
+	registry *attributes.Registry,
+) *AttributeService {
+	return &AttributeService{
+		log:      log,
+		users:    users,
+		attrs:    attrs,
+		manager:  manager,
+		registry: registry,
+	}
+}
+
+// GetAttributes returns the custom attributes of the user with the provided user ID
+// the authenticated user making the request may read. A zero user ID stands for the
+// user making the request. It delegates to the GetUserAttributes method.
+func (s *AttributeService) GetAttributes(ctx context.Context, userID int64) ([]models.Attribute, error) {
+	callerID, err := s.manager.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetUserAttributes(ctx, callerID, userID)
+}
+
+// GetUserAttributes returns the custom attributes of the user with the provided user
+// ID the caller with the provided caller ID may read, sorted by namespace and name.
+// Attributes the caller may not read are left out rather than reported.
+func (s *AttributeService) GetUserAttributes(
+	ctx context.Context,
+	callerID, userID int64) ([]models.Attribute, error) {
+	const op = "attributes.service.GetUserAttributes"
+
+	if userID == 0 {
+		userID = callerID
+	}
+
+	role, err := s.callerRole(ctx, callerID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := s.users.GetUserInfo(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return s.registry.Readable(&user, userID == callerID, role), nil
+}
+
+// SetAttributes sets the provided custom attributes of the user with the provided
+// user ID on behalf of the authenticated user making the request. A zero user ID
+// stands for the user making the request. It delegates to the SetUserAttributes
+// method.
+func (s *AttributeService) SetAttributes(ctx context.Context, userID int64, attrs []models.Attribute) error {
+	callerID, err := s.manager.GetUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.SetUserAttributes(ctx, callerID, userID, attrs)
+}
+
+// SetUserAttributes validates the provided custom attributes against the registry
+// and sets them for the user with the provided user ID on behalf of the caller with
+// the provided caller ID. Attributes with a nil value are deleted. Either all of the
+// attributes are set or, if any of them is unknown, invalid or not writable by the
+// caller, none of them.
+func (s *AttributeService) SetUserAttributes(
+	ctx context.Context,
+	callerID, userID int64,
+	attrs []models.Attribute) error {
+	const op = "attributes.service.SetUserAttributes"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("caller_id", callerID),
+		slog.Int64("user_id", userID),
+	)
+
+	if userID == 0 {
+		userID = callerID
+	}
+
+	if len(attrs) == 0 {
+		return nil
+	}
+
+	role, err := s.callerRole(ctx, callerID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	validated := make([]models.Attribute, 0, len(attrs))
+	for _, attr := range attrs {
+		schema, ok := s.registry.Schema(attr.Namespace, attr.Name)
+		if !ok {
+			return fmt.Errorf("%w: %s.%s", grpcerror.ErrUnknownAttribute, attr.Namespace, attr.Name)
+		}
+
+		if !schema.CanWrite(userID == callerID, role) {
+			return grpcerror.ErrForbidden
+		}
+
+		if attr.Value != nil {
+			if attr.Value, err = schema.Coerce(attr.Value); err != nil {
+				return err
+			}
+		}
+
+		validated = append(validated, attr)
+	}
+
+	if err = s.attrs.SetAttributes(ctx, userID, validated); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("attributes set", slog.Int("count", len(validated)))
+
+	return nil
+}
+
+// callerRole returns the stored role of the caller, so that a demoted admin loses
+// access to the attributes of other users before the token of the admin expires.
+func (s *AttributeService) callerRole(ctx context.Context, callerID int64) (models.Role, error) {
+	caller, err := s.users.GetUserInfo(ctx, callerID)
+	if err != nil {
+		return "", err
+	}
+
+	return caller.Role, nil
+}
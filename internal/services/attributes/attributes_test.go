@@ -0,0 +1,128 @@
+package attributes
+
+import (
+	"context"
+	"errors"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/attributes"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services/servicetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+const (
+	ownerID = 1
+	otherID = 2
+	adminID = 3
+)
+
+type fakeRepo struct {
+	*servicetest.Repository
+}
+
+func (r *fakeRepo) SetAttributes(_ context.Context, userID int64, attrs []models.Attribute) error {
+	user, ok := r.Users[userID]
+	if !ok {
+		return grpcerror.ErrUserNotFound
+	}
+	if user.Attributes == nil {
+		user.Attributes = models.Attributes{}
+	}
+	for _, attr := range attrs {
+		if user.Attributes[attr.Namespace] == nil {
+			user.Attributes[attr.Namespace] = map[string]interface{}{}
+		}
+		if attr.Value == nil {
+			delete(user.Attributes[attr.Namespace], attr.Name)
+			continue
+		}
+		user.Attributes[attr.Namespace][attr.Name] = attr.Value
+	}
+	r.Users[userID] = user
+	return nil
+}
+
+func newTestService(t *testing.T) (*AttributeService, *fakeRepo) {
+	t.Helper()
+
+	registry, err := attributes.NewRegistry([]config.AttributeSchema{
+		{Namespace: "profile", Name: "locale", Type: "string", MaxLength: 5,
+			Read: []string{"user", "admin"}, Write: []string{"user", "admin"}},
+		{Namespace: "billing", Name: "tier", Type: "int",
+			Read: []string{"user", "admin"}, Write: []string{"admin"}},
+	})
+	require.NoError(t, err)
+
+	repo := &fakeRepo{servicetest.NewRepository(
+		models.User{ID: ownerID, Role: models.UserRole},
+		models.User{ID: otherID, Role: models.UserRole},
+		models.User{ID: adminID, Role: models.AdminRole},
+	)}
+
+	return New(servicetest.Logger(), repo, repo, nil, registry), repo
+}
+
+func TestSetUserAttributes_Owner(t *testing.T) {
+	s, _ := newTestService(t)
+	ctx := context.Background()
+
+	err := s.SetUserAttributes(ctx, ownerID, 0, []models.Attribute{
+		{Namespace: "profile", Name: "locale", Value: "be-BY"},
+	})
+	require.NoError(t, err)
+
+	attrs, err := s.GetUserAttributes(ctx, ownerID, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []models.Attribute{{Namespace: "profile", Name: "locale", Value: "be-BY"}}, attrs)
+
+	err = s.SetUserAttributes(ctx, ownerID, ownerID, []models.Attribute{
+		{Namespace: "profile", Name: "locale"},
+	})
+	require.NoError(t, err)
+
+	attrs, err = s.GetUserAttributes(ctx, ownerID, ownerID)
+	require.NoError(t, err)
+	assert.Empty(t, attrs)
+}
+
+func TestSetUserAttributes_Access(t *testing.T) {
+	s, repo := newTestService(t)
+	ctx := context.Background()
+
+	tier := []models.Attribute{{Namespace: "billing", Name: "tier", Value: float64(2)}}
+
+	err := s.SetUserAttributes(ctx, ownerID, ownerID, tier)
+	assert.True(t, errors.Is(err, grpcerror.ErrForbidden))
+
+	err = s.SetUserAttributes(ctx, otherID, ownerID, []models.Attribute{
+		{Namespace: "profile", Name: "locale", Value: "en"},
+	})
+	assert.True(t, errors.Is(err, grpcerror.ErrForbidden))
+
+	require.NoError(t, s.SetUserAttributes(ctx, adminID, ownerID, tier))
+	assert.Equal(t, int64(2), repo.Users[ownerID].Attributes["billing"]["tier"])
+
+	attrs, err := s.GetUserAttributes(ctx, otherID, ownerID)
+	require.NoError(t, err)
+	assert.Empty(t, attrs)
+}
+
+func TestSetUserAttributes_Invalid(t *testing.T) {
+	s, repo := newTestService(t)
+	ctx := context.Background()
+
+	err := s.SetUserAttributes(ctx, ownerID, ownerID, []models.Attribute{
+		{Namespace: "profile", Name: "nickname", Value: "x"},
+	})
+	assert.True(t, errors.Is(err, grpcerror.ErrUnknownAttribute))
+
+	err = s.SetUserAttributes(ctx, ownerID, ownerID, []models.Attribute{
+		{Namespace: "profile", Name: "locale", Value: "en"},
+		{Namespace: "profile", Name: "locale", Value: "too-long"},
+	})
+	assert.True(t, errors.Is(err, grpcerror.ErrInvalidAttribute))
+	assert.Nil(t, repo.Users[ownerID].Attributes)
+}
@@ -2,41 +2,109 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/attributes"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/clients"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/email"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/hasher"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/jwt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/meta"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/password"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/pepper"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/phone"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/repository"
-	"golang.org/x/crypto/bcrypt"
 	"log/slog"
+	"strings"
+	"time"
+)
+
+const (
+	dummyPassword = "dummy-password"
+
+	signUpAttemptSubject = "Sign-up attempt with your email"
+	signUpAttemptBody    = "Someone tried to create an account with your email address. " +
+		"If it was you, sign in or reset your password. Otherwise you can ignore this message."
 )
 
 type AuthService struct {
 	log      *slog.Logger
 	repo     repository.AuthRepository
-	hashSalt string
+	sessions repository.SessionRepository
+	history  repository.LoginHistoryRepository
+	hasher   hasher.PasswordHasher
+	peppers  *pepper.Peppers
+	policy   *password.Policy
+	emails   *email.Normalizer
+	phones   *phone.Normalizer
+	sender   email.Sender
 	manager  *jwt.Manager
+	registry *attributes.Registry
+	clients  *clients.Registry
+
+	// dummyHash is verified on failed sign-ins of unknown users, so that they
+	// take as long as sign-ins with a wrong password.
+	dummyHash string
+
+	enumerationSafe bool
 }
 
 // New creates and returns a new instance of the AuthService
 func New(
 	log *slog.Logger,
 	repo repository.AuthRepository,
+	sessions repository.SessionRepository,
+	history repository.LoginHistoryRepository,
 	manager *jwt.Manager,
-	hashSalt string,
+	passwordHasher hasher.PasswordHasher,
+	peppers *pepper.Peppers,
+	policy *password.Policy,
+	emails *email.Normalizer,
+	phones *phone.Normalizer,
+	sender email.Sender,
+	signUpCfg *config.SignUpConfig,
+	registry *attributes.Registry,
+	clientRegistry *clients.Registry,
 ) *AuthService {
+	const op = "auth.New"
+
+	dummyHash, err := passwordHasher.Hash(peppers.ApplyCurrent(dummyPassword))
+	if err != nil {
+		log.Error("failed to generate dummy password hash", slog.String("op", op), sl.Err(err))
+	}
+
 	return &AuthService{
 		log:      log,
 		repo:     repo,
+		sessions: sessions,
+		history:  history,
 		manager:  manager,
-		hashSalt: hashSalt,
+		hasher:   passwordHasher,
+		peppers:  peppers,
+		policy:   policy,
+		emails:   emails,
+		phones:   phones,
+		sender:   sender,
+		registry: registry,
+		clients:  clientRegistry,
+
+		dummyHash:       dummyHash,
+		enumerationSafe: signUpCfg.EnumerationSafe,
 	}
 }
 
-// SignIn authenticates a user with the provided email and password by first validating
-// the credentials against the authentication repository. If successful, it generates
-// a JWT token for the user and returns it.
-func (s *AuthService) SignIn(ctx context.Context, email, password string) (string, error) {
+// SignIn authenticates a user with the provided identifier, an email or a verified
+// phone number, and password by first validating the credentials against the stored
+// password hash and the pepper version it was created with. If successful, it transparently rehashes password hashes produced
+// by an outdated algorithm or pepper, records a new session for the user and returns
+// a JWT token bound to this session. Every attempt is recorded in the login history.
+// The token is issued for the requested audience and scopes, which are checked
+// against the client registry before the credentials.
+func (s *AuthService) SignIn(ctx context.Context, identifier, password string, grant models.Grant) (string, error) {
 	const op = "auth.SignIn"
 	log := s.log.With(
 		slog.String("op", op),
@@ -44,15 +112,76 @@ func (s *AuthService) SignIn(ctx context.Context, email, password string) (strin
 
 	log.Info("trying to log in user")
 
-	passSalted := password + s.hashSalt
-	user, err := s.repo.Login(ctx, email, passSalted)
+	grant, err := s.clients.Resolve(grant)
+	if err != nil {
+		log.Info("invalid grant requested", sl.Err(err))
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := s.findUser(ctx, identifier)
+	if errors.Is(err, grpcerror.ErrUserNotFound) {
+		s.verifyDummy(password)
+		s.recordAttempt(ctx, 0, "", models.LoginUnknownUser)
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
 	if err != nil {
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
 
+	passSalted, err := s.peppers.Apply(password, user.PepperVersion)
+	if err != nil {
+		log.Error("failed to apply pepper", sl.Err(err), slog.Int64("user_id", user.ID))
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	ok, err := s.hasher.Verify(user.PassHash, passSalted)
+	if err != nil {
+		log.Error("failed to verify password", sl.Err(err), slog.Int64("user_id", user.ID))
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	if !ok {
+		s.recordAttempt(ctx, user.ID, "", models.LoginInvalidPassword)
+		return "", fmt.Errorf("%s: %w", op, grpcerror.ErrUserNotFound)
+	}
+
+	if err = checkStatus(&user); err != nil {
+		log.Info("sign-in of inactive user rejected", sl.Err(err), slog.Int64("user_id", user.ID))
+		reason := models.LoginUserSuspended
+		if errors.Is(err, grpcerror.ErrUserDisabled) {
+			reason = models.LoginUserDisabled
+		}
+		s.recordAttempt(ctx, user.ID, "", reason)
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
 	log.Info("user successfully logged in")
 
-	token, err := s.manager.NewToken(user)
+	if s.hasher.NeedsRehash(user.PassHash) || user.PepperVersion != s.peppers.Current() {
+		s.rehash(ctx, user.ID, password)
+	}
+
+	now := time.Now().UTC()
+	sessionID, err := s.sessions.CreateSession(ctx, &models.Session{
+		UserID:     user.ID,
+		UserAgent:  meta.UserAgent(ctx),
+		IP:         meta.ClientIP(ctx),
+		CreatedAt:  now,
+		LastSeenAt: now,
+	})
+	if err != nil {
+		log.Error("failed to create session", sl.Err(err))
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("session created", slog.String("session_id", sessionID))
+
+	s.recordAttempt(ctx, user.ID, sessionID, "")
+
+	if err = s.repo.UpdateLastLogin(ctx, user.ID, now); err != nil {
+		log.Warn("failed to update last login time", sl.Err(err), slog.Int64("user_id", user.ID))
+	}
+
+	token, err := s.manager.NewToken(user, sessionID, grant, s.registry.Claims(&user))
 	if err != nil {
 		s.log.Error("failed to generate jwt-token", sl.Err(err))
 		return "", err
@@ -63,9 +192,12 @@ func (s *AuthService) SignIn(ctx context.Context, email, password string) (strin
 	return token, nil
 }
 
-// SignUp registers a new user by first generating a password hash, and then creating
+// SignUp registers a new user by first validating the password against the password
+// policy and generating a password hash, and then creating
 // a new user entry in the authentication repository. It returns the assigned user ID
-// upon successful registration.
+// upon successful registration. In the enumeration-safe mode it returns 0 instead,
+// and a sign-up with a registered email succeeds as well, notifying the owner of
+// the email.
 func (s *AuthService) SignUp(ctx context.Context, user *models.User) (int64, error) {
 	const op = "auth.SignUp"
 	log := s.log.With(
@@ -74,14 +206,30 @@ func (s *AuthService) SignUp(ctx context.Context, user *models.User) (int64, err
 
 	log.Info("registering user")
 
-	passHash, err := bcrypt.GenerateFromPassword([]byte(user.PassHash+s.hashSalt), bcrypt.DefaultCost)
+	user.Email = s.emails.Canonical(user.Email)
+	user.EmailNormalized = s.emails.Normalize(user.Email)
+	// the phone number is formatted in E.164 on validation of the request
+	user.PhoneNormalized = user.PhoneNumber
+
+	if err := s.policy.Validate("password", user.PassHash, user); err != nil {
+		log.Info("password rejected by policy", sl.Err(err))
+		return -1, err
+	}
+
+	passHash, err := s.hasher.Hash(s.peppers.ApplyCurrent(user.PassHash))
 	if err != nil {
 		log.Error("failed to generate password hash", sl.Err(err))
 		return -1, fmt.Errorf("%s: %w", op, err)
 	}
-	user.PassHash = string(passHash)
+	user.PassHash = passHash
+	user.PepperVersion = s.peppers.Current()
 
 	id, err := s.repo.CreateUser(ctx, user)
+	if errors.Is(err, grpcerror.ErrUserExists) && s.enumerationSafe {
+		log.Info("sign-up with registered email, notifying the owner")
+		s.notifyOwner(ctx, user.Email)
+		return 0, nil
+	}
 	if err != nil {
 		log.Error("failed to create user", sl.Err(err))
 		return -1, fmt.Errorf("%s: %w", op, err)
@@ -89,5 +237,123 @@ func (s *AuthService) SignUp(ctx context.Context, user *models.User) (int64, err
 
 	log.Info("user registered")
 
+	if s.enumerationSafe {
+		return 0, nil
+	}
+
 	return id, nil
 }
+
+// notifyOwner tells the owner of the email about the attempt to sign up with it.
+// The message is sent in the background, so that the response does not take longer
+// than the response to a successful sign-up.
+func (s *AuthService) notifyOwner(ctx context.Context, emailAddr string) {
+	const op = "auth.notifyOwner"
+
+	ctx = context.WithoutCancel(ctx)
+
+	go func() {
+		if err := s.sender.Send(ctx, emailAddr, signUpAttemptSubject, signUpAttemptBody); err != nil {
+			s.log.Error("failed to notify owner of the email", slog.String("op", op), sl.Err(err))
+		}
+	}()
+}
+
+// verifyDummy verifies the password against the dummy hash and discards the result.
+// It makes a sign-in of an unknown user as slow as a sign-in of a known user with a
+// wrong password, so that response times do not reveal registered identifiers.
+func (s *AuthService) verifyDummy(password string) {
+	if s.dummyHash == "" {
+		return
+	}
+
+	_, _ = s.hasher.Verify(s.dummyHash, s.peppers.ApplyCurrent(password))
+}
+
+// findUser looks the user up by the identifier. Identifiers containing "@" are
+// emails, anything else is a phone number. Identifiers that are not valid phone
+// numbers are not found, just like unknown emails and phone numbers, so that every
+// kind of identifier fails the same way.
+func (s *AuthService) findUser(ctx context.Context, identifier string) (models.User, error) {
+	if strings.Contains(identifier, "@") {
+		return s.repo.GetUserByEmail(ctx, s.emails.Normalize(identifier))
+	}
+
+	phoneNumber, err := s.phones.Normalize(identifier)
+	if err != nil {
+		return models.User{}, grpcerror.ErrUserNotFound
+	}
+
+	return s.repo.GetUserByPhone(ctx, phoneNumber)
+}
+
+// recordAttempt stores the sign-in attempt in the login history. The attempt is
+// successful if failureReason is empty. Failures are only logged, as they must not
+// change the outcome of the sign-in.
+func (s *AuthService) recordAttempt(ctx context.Context, userID int64, sessionID, failureReason string) {
+	const op = "auth.recordAttempt"
+
+	err := s.history.RecordLoginAttempt(ctx, &models.LoginAttempt{
+		UserID:        userID,
+		Success:       failureReason == "",
+		FailureReason: failureReason,
+		IP:            meta.ClientIP(ctx),
+		UserAgent:     meta.UserAgent(ctx),
+		SessionID:     sessionID,
+		CreatedAt:     time.Now().UTC(),
+	})
+	if err != nil {
+		s.log.Warn("failed to record login attempt",
+			slog.String("op", op), sl.Err(err), slog.Int64("user_id", userID))
+	}
+}
+
+// rehash replaces an outdated password hash of the user with the one produced by
+// the current hasher and pepper. Failures are only logged, as the user is already
+// authenticated.
+func (s *AuthService) rehash(ctx context.Context, userID int64, password string) {
+	const op = "auth.rehash"
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	passHash, err := s.hasher.Hash(s.peppers.ApplyCurrent(password))
+	if err != nil {
+		log.Error("failed to generate password hash", sl.Err(err))
+		return
+	}
+
+	if err = s.repo.UpdatePassHash(ctx, userID, passHash, s.peppers.Current()); err != nil {
+		log.Error("failed to update password hash", sl.Err(err), slog.Int64("user_id", userID))
+		return
+	}
+
+	log.Info("password hash migrated", slog.Int64("user_id", userID))
+}
+
+// OutdatedPepperUsage returns the number of users per pepper version for every
+// version other than the current one.
+func (s *AuthService) OutdatedPepperUsage(ctx context.Context) (map[int]int64, error) {
+	const op = "auth.OutdatedPepperUsage"
+
+	usage, err := s.repo.CountUsersByPepperVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	delete(usage, s.peppers.Current())
+
+	return usage, nil
+}
+
+// checkStatus returns an error if the user is suspended or disabled.
+func checkStatus(user *models.User) error {
+	switch user.EffectiveStatus(time.Now()) {
+	case models.StatusSuspended:
+		return grpcerror.ErrUserSuspended
+	case models.StatusDisabled:
+		return grpcerror.ErrUserDisabled
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"context"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/email"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/phone"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type fakeRepo struct {
+	repository.AuthRepository
+	byEmail map[string]models.User
+	byPhone map[string]models.User
+}
+
+func (r *fakeRepo) GetUserByEmail(_ context.Context, normalizedEmail string) (models.User, error) {
+	user, ok := r.byEmail[normalizedEmail]
+	if !ok {
+		return models.User{}, grpcerror.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (r *fakeRepo) GetUserByPhone(_ context.Context, normalizedPhone string) (models.User, error) {
+	user, ok := r.byPhone[normalizedPhone]
+	if !ok {
+		return models.User{}, grpcerror.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func TestFindUser(t *testing.T) {
+	user := models.User{ID: 1}
+
+	s := &AuthService{
+		repo: &fakeRepo{
+			byEmail: map[string]models.User{"john@example.com": user},
+			byPhone: map[string]models.User{"+375291234567": user},
+		},
+		emails: email.NewNormalizer(false),
+		phones: phone.NewNormalizer("BY"),
+	}
+
+	for _, identifier := range []string{"John@Example.com", "+375291234567", "8 029 123-45-67"} {
+		found, err := s.findUser(context.Background(), identifier)
+		require.NoError(t, err, identifier)
+		assert.Equal(t, user.ID, found.ID, identifier)
+	}
+
+	for _, identifier := range []string{"jane@example.com", "+375297654321", "john"} {
+		_, err := s.findUser(context.Background(), identifier)
+		assert.ErrorIs(t, err, grpcerror.ErrUserNotFound, identifier)
+	}
+}
@@ -0,0 +1,225 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/attributes"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/clients"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/email"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/hasher"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/password"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/pepper"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/phone"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/pii"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/repository"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/repository/encrypted"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services/servicetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeHistory struct {
+	repository.LoginHistoryRepository
+	attempts []models.LoginAttempt
+}
+
+func (r *fakeHistory) RecordLoginAttempt(_ context.Context, attempt *models.LoginAttempt) error {
+	r.attempts = append(r.attempts, *attempt)
+	return nil
+}
+
+type fakeUsers struct {
+	fakeRepo
+	created []models.User
+}
+
+func (r *fakeUsers) CreateUser(_ context.Context, user *models.User) (int64, error) {
+	if _, ok := r.byEmail[user.EmailNormalized]; ok {
+		return -1, grpcerror.ErrUserExists
+	}
+	r.created = append(r.created, *user)
+	return int64(len(r.created)), nil
+}
+
+// slowRepo answers every lookup after a fixed delay, so that the number of queries
+// a sign-in makes shows up in its duration.
+type slowRepo struct {
+	repository.Repository
+	users fakeRepo
+	delay time.Duration
+}
+
+func (r *slowRepo) GetUserByEmail(ctx context.Context, normalizedEmail string) (models.User, error) {
+	time.Sleep(r.delay)
+	return r.users.GetUserByEmail(ctx, normalizedEmail)
+}
+
+func (r *slowRepo) GetUserByPhone(ctx context.Context, normalizedPhone string) (models.User, error) {
+	time.Sleep(r.delay)
+	return r.users.GetUserByPhone(ctx, normalizedPhone)
+}
+
+type fakeSender struct {
+	sent chan string
+}
+
+func (s *fakeSender) Send(_ context.Context, to, _, _ string) error {
+	s.sent <- to
+	return nil
+}
+
+func newTestService(t *testing.T, enumerationSafe bool) (*AuthService, *fakeUsers, *fakeSender) {
+	t.Helper()
+
+	h, err := hasher.New(config.PasswordHashConfig{
+		Memory:      8 * 1024,
+		Iterations:  2,
+		Parallelism: 1,
+		SaltLength:  16,
+		KeyLength:   32,
+	})
+	require.NoError(t, err)
+
+	peppers, err := pepper.New(config.PepperConfig{Versions: map[int]string{0: "pepper"}})
+	require.NoError(t, err)
+
+	policy, err := password.NewPolicy(config.PasswordPolicyConfig{MinLength: 8, MaxLength: 128})
+	require.NoError(t, err)
+
+	passHash, err := h.Hash(peppers.ApplyCurrent("Secret-password1"))
+	require.NoError(t, err)
+
+	user := models.User{ID: 1, Email: "john@example.com", PassHash: passHash}
+
+	users := &fakeUsers{fakeRepo: fakeRepo{
+		byEmail: map[string]models.User{"john@example.com": user},
+		byPhone: map[string]models.User{},
+	}}
+	sender := &fakeSender{sent: make(chan string, 1)}
+
+	clientRegistry, err := clients.NewRegistry(&config.TokenConfig{Audience: "sso"}, nil)
+	require.NoError(t, err)
+
+	s := New(servicetest.Logger(), users, nil, &fakeHistory{}, nil, h, peppers, policy,
+		email.NewNormalizer(false), phone.NewNormalizer("BY"), sender,
+		&config.SignUpConfig{EnumerationSafe: enumerationSafe}, &attributes.Registry{}, clientRegistry)
+
+	return s, users, sender
+}
+
+// medianSignInDuration returns the median duration of failed sign-ins with the
+// provided identifier.
+func medianSignInDuration(t *testing.T, s *AuthService, identifier string) time.Duration {
+	t.Helper()
+
+	const runs = 15
+
+	durations := make([]time.Duration, runs)
+	for i := range durations {
+		start := time.Now()
+		_, err := s.SignIn(context.Background(), identifier, "Wrong-password1", models.Grant{})
+		durations[i] = time.Since(start)
+		require.ErrorIs(t, err, grpcerror.ErrUserNotFound)
+	}
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	return durations[runs/2]
+}
+
+func TestSignIn_FailureTiming(t *testing.T) {
+	if testing.Short() {
+		t.Skip("timing test")
+	}
+
+	s, _, _ := newTestService(t, false)
+
+	known := medianSignInDuration(t, s, "john@example.com")
+
+	for _, identifier := range []string{"jane@example.com", "+375297654321", "not-a-phone"} {
+		unknown := medianSignInDuration(t, s, identifier)
+
+		ratio := float64(unknown) / float64(known)
+		assert.InDelta(t, 1, ratio, 0.5,
+			"%s: unknown user %s, wrong password %s", identifier, unknown, known)
+	}
+}
+
+func TestSignIn_FailureTiming_Encrypted(t *testing.T) {
+	if testing.Short() {
+		t.Skip("timing test")
+	}
+
+	s, users, _ := newTestService(t, false)
+
+	kms, err := pii.NewLocalKMS(map[string]string{
+		"1": base64.StdEncoding.EncodeToString([]byte(strings.Repeat("1", 32))),
+	}, "1")
+	require.NoError(t, err)
+
+	cipher, err := pii.NewCipher(kms, "index-key")
+	require.NoError(t, err)
+
+	user := users.byEmail["john@example.com"]
+	require.NoError(t, cipher.Encrypt(&user))
+
+	repo := &slowRepo{
+		users: fakeRepo{
+			byEmail: map[string]models.User{cipher.BlindIndex("john@example.com"): user},
+			byPhone: map[string]models.User{},
+		},
+		delay: 20 * time.Millisecond,
+	}
+
+	encryptedService := *s
+	encryptedService.repo = encrypted.New(s.log, repo, cipher)
+
+	known := medianSignInDuration(t, &encryptedService, "john@example.com")
+
+	for _, identifier := range []string{"jane@example.com", "+375297654321"} {
+		unknown := medianSignInDuration(t, &encryptedService, identifier)
+
+		ratio := float64(unknown) / float64(known)
+		assert.InDelta(t, 1, ratio, 0.25,
+			"%s: unknown user %s, wrong password %s", identifier, unknown, known)
+	}
+}
+
+func TestSignUp_EnumerationSafe(t *testing.T) {
+	s, users, sender := newTestService(t, true)
+
+	newUser := &models.User{Email: "jane@example.com", PassHash: "Secret-password1"}
+	id, err := s.SignUp(context.Background(), newUser)
+	require.NoError(t, err)
+	assert.Zero(t, id)
+	assert.Len(t, users.created, 1)
+
+	existing := &models.User{Email: "John@Example.com", PassHash: "Secret-password1"}
+	id, err = s.SignUp(context.Background(), existing)
+	require.NoError(t, err)
+	assert.Zero(t, id)
+	assert.Len(t, users.created, 1)
+
+	select {
+	case to := <-sender.sent:
+		assert.Equal(t, "John@example.com", to)
+	case <-time.After(time.Second):
+		t.Fatal("owner of the email was not notified")
+	}
+}
+
+func TestSignUp_ExistingUser(t *testing.T) {
+	s, _, sender := newTestService(t, false)
+
+	existing := &models.User{Email: "john@example.com", PassHash: "Secret-password1"}
+	_, err := s.SignUp(context.Background(), existing)
+	require.ErrorIs(t, err, grpcerror.ErrUserExists)
+	assert.Empty(t, sender.sent)
+}
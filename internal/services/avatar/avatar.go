@@ -0,0 +1,181 @@
+package avatar
+
+import (
+	"context"
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/avatar"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/jwt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/token"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/repository"
+	"io"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+type AvatarService struct {
+	log       *slog.Logger
+	users     repository.UserInfoRepository
+	avatars   repository.AvatarRepository
+	manager   *jwt.Manager
+	processor *avatar.Processor
+	now       func() time.Time
+}
+
+// New creates and returns a new instance of the AvatarService
+func New(
+	log *slog.Logger,
+	users repository.UserInfoRepository,
+	avatars repository.AvatarRepository,
+	manager *jwt.Manager,
+	processor *avatar.Processor,
+) *AvatarService {
+	return &AvatarService{
+		log:       log,
+		users:     users,
+		avatars:   avatars,
+		manager:   manager,
+		processor: processor,
+		now:       time.Now,
+	}
+}
+
+// UploadAvatar replaces the avatar of the authenticated user making the request
+// with the image read from r. It delegates to the UploadUserAvatar method.
+func (s *AvatarService) UploadAvatar(ctx context.Context, r io.Reader) error {
+	userID, err := s.manager.GetUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.UploadUserAvatar(ctx, userID, r)
+}
+
+// UploadUserAvatar reads an image from r, stops as soon as it exceeds the size
+// limit, and stores it resized to every avatar size as the new avatar of the user
+// with the provided user ID. The previous avatar is removed.
+func (s *AvatarService) UploadUserAvatar(ctx context.Context, userID int64, r io.Reader) error {
+	const op = "avatar.service.UploadUserAvatar"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	data, err := io.ReadAll(io.LimitReader(r, s.processor.MaxSize()+1))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	contentType, images, err := s.processor.Process(data)
+	if err != nil {
+		log.Info("avatar rejected", sl.Err(err))
+		return err
+	}
+
+	uploadID, err := token.Generate()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	sizes := make([]int, 0, len(images))
+	for size := range images {
+		sizes = append(sizes, size)
+	}
+	sort.Ints(sizes)
+
+	err = s.avatars.SaveAvatar(ctx, userID, &models.Avatar{
+		UploadID:    uploadID,
+		ContentType: contentType,
+		Sizes:       sizes,
+		UpdatedAt:   s.now().UTC(),
+	}, images)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("avatar uploaded", slog.Int("bytes", len(data)))
+
+	return nil
+}
+
+// GetAvatar returns the avatar image of the user with the provided user ID closest
+// to the requested size. A zero user ID stands for the authenticated user making
+// the request. It delegates to the GetUserAvatar method.
+func (s *AvatarService) GetAvatar(ctx context.Context, userID int64, size int) (models.AvatarImage, error) {
+	if userID == 0 {
+		callerID, err := s.manager.GetUserIDFromContext(ctx)
+		if err != nil {
+			return models.AvatarImage{}, err
+		}
+		userID = callerID
+	}
+
+	return s.GetUserAvatar(ctx, userID, size)
+}
+
+// GetUserAvatar returns the avatar image of the user with the provided user ID.
+// The smallest stored size not smaller than the requested one is returned, or the
+// largest if every stored size is smaller. A zero size stands for the largest.
+func (s *AvatarService) GetUserAvatar(ctx context.Context, userID int64, size int) (models.AvatarImage, error) {
+	const op = "avatar.service.GetUserAvatar"
+
+	user, err := s.users.GetUserInfo(ctx, userID)
+	if err != nil {
+		return models.AvatarImage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if user.Avatar == nil || len(user.Avatar.Sizes) == 0 {
+		return models.AvatarImage{}, grpcerror.ErrAvatarNotFound
+	}
+
+	size = closestSize(user.Avatar.Sizes, size)
+
+	data, err := s.avatars.GetAvatarImage(ctx, userID, user.Avatar.UploadID, size)
+	if err != nil {
+		return models.AvatarImage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return models.AvatarImage{
+		ContentType: user.Avatar.ContentType,
+		Size:        size,
+		Data:        data,
+	}, nil
+}
+
+// DeleteAvatar removes the avatar of the authenticated user making the request.
+func (s *AvatarService) DeleteAvatar(ctx context.Context) error {
+	const op = "avatar.service.DeleteAvatar"
+
+	userID, err := s.manager.GetUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = s.avatars.DeleteAvatar(ctx, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.log.Info("avatar deleted", slog.String("op", op), slog.Int64("user_id", userID))
+
+	return nil
+}
+
+// closestSize picks the size to serve from the sorted stored sizes.
+func closestSize(sizes []int, requested int) int {
+	largest := sizes[len(sizes)-1]
+	if requested <= 0 {
+		return largest
+	}
+
+	for _, size := range sizes {
+		if size >= requested {
+			return size
+		}
+	}
+
+	return largest
+}
@@ -0,0 +1,91 @@
+package avatar
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/avatar"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services/servicetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"image"
+	"image/png"
+	"testing"
+)
+
+const userID = 1
+
+type fakeRepo struct {
+	*servicetest.Repository
+	images map[string]map[int][]byte
+}
+
+func (r *fakeRepo) SaveAvatar(_ context.Context, userID int64, a *models.Avatar, images map[int][]byte) error {
+	user := r.Users[userID]
+	user.Avatar = a
+	r.Users[userID] = user
+	r.images = map[string]map[int][]byte{a.UploadID: images}
+	return nil
+}
+
+func (r *fakeRepo) GetAvatarImage(_ context.Context, _ int64, uploadID string, size int) ([]byte, error) {
+	data, ok := r.images[uploadID][size]
+	if !ok {
+		return nil, grpcerror.ErrAvatarNotFound
+	}
+	return data, nil
+}
+
+func newTestService() (*AvatarService, *fakeRepo) {
+	repo := &fakeRepo{Repository: servicetest.NewRepository(models.User{ID: userID})}
+	processor := avatar.NewProcessor(&config.AvatarConfig{
+		MaxSize:   1 << 16,
+		MaxPixels: 512 * 512,
+		Sizes:     []int{32, 64},
+	})
+
+	return New(servicetest.Logger(), repo, repo, nil, processor), repo
+}
+
+func TestUploadUserAvatar(t *testing.T) {
+	s, repo := newTestService()
+	ctx := context.Background()
+
+	_, err := s.GetUserAvatar(ctx, userID, 0)
+	assert.True(t, errors.Is(err, grpcerror.ErrAvatarNotFound))
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 100, 80))))
+
+	require.NoError(t, s.UploadUserAvatar(ctx, userID, &buf))
+	require.NotNil(t, repo.Users[userID].Avatar)
+	assert.Equal(t, []int{32, 64}, repo.Users[userID].Avatar.Sizes)
+	assert.Equal(t, avatar.ContentTypePNG, repo.Users[userID].Avatar.ContentType)
+
+	img, err := s.GetUserAvatar(ctx, userID, 40)
+	require.NoError(t, err)
+	assert.Equal(t, 64, img.Size)
+	assert.Equal(t, avatar.ContentTypePNG, img.ContentType)
+	assert.NotEmpty(t, img.Data)
+}
+
+func TestUploadUserAvatar_TooLarge(t *testing.T) {
+	s, repo := newTestService()
+
+	err := s.UploadUserAvatar(context.Background(), userID, bytes.NewReader(make([]byte, 1<<20)))
+	assert.True(t, errors.Is(err, grpcerror.ErrAvatarTooLarge))
+	assert.Nil(t, repo.Users[userID].Avatar)
+}
+
+func TestClosestSize(t *testing.T) {
+	sizes := []int{64, 128, 256}
+
+	assert.Equal(t, 256, closestSize(sizes, 0))
+	assert.Equal(t, 64, closestSize(sizes, 10))
+	assert.Equal(t, 128, closestSize(sizes, 128))
+	assert.Equal(t, 256, closestSize(sizes, 200))
+	assert.Equal(t, 256, closestSize(sizes, 1024))
+}
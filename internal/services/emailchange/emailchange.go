@@ -0,0 +1,198 @@
+package emailchange
+
+import (
+	"context"
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/email"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/jwt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/token"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/repository"
+	"github.com/badoux/checkmail"
+	"log/slog"
+	"time"
+)
+
+const (
+	confirmSubject = "Confirm your new email"
+	confirmBody    = "Follow the link to confirm your new email address: %s%s\n" +
+		"The link expires at %s."
+	noticeSubject = "Your email is being changed"
+	noticeBody    = "A change of your email address to %s was requested. " +
+		"If it was not you, follow the link to cancel the change and sign out everywhere: %s%s\n" +
+		"The link expires at %s."
+)
+
+type EmailChangeService struct {
+	log      *slog.Logger
+	users    repository.UserInfoRepository
+	changes  repository.EmailChangeRepository
+	sessions repository.SessionRepository
+	tokens   repository.AccessTokenRepository
+	manager  *jwt.Manager
+	emails   *email.Normalizer
+	sender   email.Sender
+	cfg      *config.EmailConfig
+	now      func() time.Time
+}
+
+// New creates and returns a new instance of the EmailChangeService
+func New(
+	log *slog.Logger,
+	users repository.UserInfoRepository,
+	changes repository.EmailChangeRepository,
+	sessions repository.SessionRepository,
+	tokens repository.AccessTokenRepository,
+	manager *jwt.Manager,
+	emails *email.Normalizer,
+	sender email.Sender,
+	cfg *config.EmailConfig,
+) *EmailChangeService {
+	return &EmailChangeService{
+		log:      log,
+		users:    users,
+		changes:  changes,
+		sessions: sessions,
+		tokens:   tokens,
+		manager:  manager,
+		emails:   emails,
+		sender:   sender,
+		cfg:      cfg,
+		now:      time.Now,
+	}
+}
+
+// RequestEmailChange requests a change of the email of the authenticated user
+// making the request. It delegates to the RequestUserEmailChange method.
+func (s *EmailChangeService) RequestEmailChange(ctx context.Context, newEmail string) error {
+	userID, err := s.manager.GetUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.RequestUserEmailChange(ctx, userID, newEmail)
+}
+
+// RequestUserEmailChange stores the new email as a pending change of the user with
+// the provided user ID, sends a confirmation link to the new address and a notice
+// with a cancellation link to the current one. A previous pending change is
+// replaced. Whether the new email is taken is checked only on confirmation, so
+// that the request does not reveal which emails are registered.
+func (s *EmailChangeService) RequestUserEmailChange(ctx context.Context, userID int64, newEmail string) error {
+	const op = "emailchange.service.RequestUserEmailChange"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	newEmail = s.emails.Canonical(newEmail)
+	if err := checkmail.ValidateFormat(newEmail); err != nil {
+		return grpcerror.ErrInvalidEmail
+	}
+
+	user, err := s.users.GetUserInfo(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	normalized := s.emails.Normalize(newEmail)
+	if normalized == s.emails.Normalize(user.Email) {
+		log.Info("email is not changed")
+		return nil
+	}
+
+	confirmToken, err := token.Generate()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	cancelToken, err := token.Generate()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := s.now().UTC()
+
+	change := &models.EmailChange{
+		Email:            newEmail,
+		EmailNormalized:  normalized,
+		ConfirmTokenHash: token.Hash(confirmToken),
+		CancelTokenHash:  token.Hash(cancelToken),
+		RequestedAt:      now,
+		ExpiresAt:        now.Add(s.cfg.ChangeTTL),
+	}
+
+	if err = s.changes.SetEmailChange(ctx, userID, change); err != nil {
+		return err
+	}
+
+	expires := change.ExpiresAt.Format(time.RFC1123)
+
+	err = s.sender.Send(ctx, newEmail, confirmSubject,
+		fmt.Sprintf(confirmBody, s.cfg.ConfirmURL, confirmToken, expires))
+	if err != nil {
+		log.Error("failed to send confirmation", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.sender.Send(ctx, user.Email, noticeSubject,
+		fmt.Sprintf(noticeBody, newEmail, s.cfg.CancelURL, cancelToken, expires))
+	if err != nil {
+		log.Error("failed to send notice", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("email change requested")
+
+	return nil
+}
+
+// ConfirmEmailChange applies the email change the provided confirmation token was
+// issued for. ErrUserExists is returned if the new email was taken in the meantime.
+func (s *EmailChangeService) ConfirmEmailChange(ctx context.Context, confirmToken string) error {
+	const op = "emailchange.service.ConfirmEmailChange"
+
+	userID, err := s.changes.ConfirmEmailChange(ctx, token.Hash(confirmToken), s.now().UTC())
+	if err != nil {
+		return err
+	}
+
+	s.log.Info("email change confirmed", slog.String("op", op), slog.Int64("user_id", userID))
+
+	return nil
+}
+
+// CancelEmailChange cancels the email change the provided cancellation token was
+// issued for, restoring the previous email if the change was already confirmed.
+// As the change may have been requested with a stolen token, all sessions and
+// personal access tokens of the user are revoked.
+func (s *EmailChangeService) CancelEmailChange(ctx context.Context, cancelToken string) error {
+	const op = "emailchange.service.CancelEmailChange"
+
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	userID, err := s.changes.CancelEmailChange(ctx, token.Hash(cancelToken), s.now().UTC())
+	if err != nil {
+		return err
+	}
+
+	if err = s.sessions.RevokeAllOtherSessions(ctx, userID, ""); err != nil {
+		log.Error("failed to revoke sessions", slog.Int64("user_id", userID), sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = s.tokens.RevokeAllAccessTokens(ctx, userID); err != nil {
+		log.Error("failed to revoke access tokens", slog.Int64("user_id", userID), sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("email change cancelled", slog.Int64("user_id", userID))
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package emailchange
+
+import (
+	"context"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/email"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/token"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services/servicetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+const userID = 1
+
+type fakeRepo struct {
+	*servicetest.Repository
+	revoked bool
+	tokens  bool
+}
+
+func (r *fakeRepo) SetEmailChange(_ context.Context, userID int64, change *models.EmailChange) error {
+	user := r.Users[userID]
+	user.EmailChange = change
+	r.Users[userID] = user
+	return nil
+}
+
+func (r *fakeRepo) ConfirmEmailChange(_ context.Context, tokenHash string, now time.Time) (int64, error) {
+	user := r.Users[userID]
+	change := user.EmailChange
+	if change == nil || change.ConfirmTokenHash != tokenHash || !now.Before(change.ExpiresAt) {
+		return 0, grpcerror.ErrEmailChangeNotFound
+	}
+	user.Email, change.PreviousEmail = change.Email, user.Email
+	r.Users[userID] = user
+	return user.ID, nil
+}
+
+func (r *fakeRepo) CancelEmailChange(_ context.Context, tokenHash string, now time.Time) (int64, error) {
+	user := r.Users[userID]
+	change := user.EmailChange
+	if change == nil || change.CancelTokenHash != tokenHash || !now.Before(change.ExpiresAt) {
+		return 0, grpcerror.ErrEmailChangeNotFound
+	}
+	if change.PreviousEmail != "" {
+		user.Email = change.PreviousEmail
+	}
+	user.EmailChange = nil
+	r.Users[userID] = user
+	return user.ID, nil
+}
+
+func (r *fakeRepo) RevokeAllOtherSessions(_ context.Context, _ int64, _ string) error {
+	r.revoked = true
+	return nil
+}
+
+func (r *fakeRepo) RevokeAllAccessTokens(_ context.Context, _ int64) error {
+	r.tokens = true
+	return nil
+}
+
+type message struct {
+	to, body string
+}
+
+type fakeSender struct {
+	messages []message
+}
+
+func (s *fakeSender) Send(_ context.Context, to, _, body string) error {
+	s.messages = append(s.messages, message{to: to, body: body})
+	return nil
+}
+
+// tokenFrom extracts the token following the url from the message body.
+func tokenFrom(t *testing.T, body, url string) string {
+	t.Helper()
+
+	_, rest, ok := strings.Cut(body, url)
+	require.True(t, ok)
+
+	return strings.Fields(rest)[0]
+}
+
+func newTestService(t *testing.T) (*EmailChangeService, *fakeRepo, *fakeSender) {
+	t.Helper()
+
+	repo := &fakeRepo{Repository: servicetest.NewRepository(models.User{ID: userID, Email: "old@example.com"})}
+	sender := &fakeSender{}
+
+	cfg := &config.EmailConfig{
+		ChangeTTL:  time.Hour,
+		ConfirmURL: "https://sso/confirm?token=",
+		CancelURL:  "https://sso/cancel?token=",
+	}
+
+	s := New(servicetest.Logger(), repo, repo, repo, repo, nil, email.NewNormalizer(false), sender, cfg)
+
+	return s, repo, sender
+}
+
+func TestEmailChange_Confirm(t *testing.T) {
+	ctx := context.Background()
+	s, repo, sender := newTestService(t)
+
+	require.NoError(t, s.RequestUserEmailChange(ctx, 1, " new@Example.com "))
+
+	assert.Equal(t, "old@example.com", repo.Users[userID].Email)
+	require.Len(t, sender.messages, 2)
+	assert.Equal(t, "new@example.com", sender.messages[0].to)
+	assert.Equal(t, "old@example.com", sender.messages[1].to)
+
+	confirmToken := tokenFrom(t, sender.messages[0].body, s.cfg.ConfirmURL)
+	assert.Equal(t, token.Hash(confirmToken), repo.Users[userID].EmailChange.ConfirmTokenHash)
+
+	require.ErrorIs(t, s.ConfirmEmailChange(ctx, "wrong"), grpcerror.ErrEmailChangeNotFound)
+	require.NoError(t, s.ConfirmEmailChange(ctx, confirmToken))
+	assert.Equal(t, "new@example.com", repo.Users[userID].Email)
+	assert.False(t, repo.revoked)
+	assert.False(t, repo.tokens)
+}
+
+func TestEmailChange_CancelRevertsAndRevokesSessions(t *testing.T) {
+	ctx := context.Background()
+	s, repo, sender := newTestService(t)
+
+	require.NoError(t, s.RequestUserEmailChange(ctx, 1, "new@example.com"))
+
+	confirmToken := tokenFrom(t, sender.messages[0].body, s.cfg.ConfirmURL)
+	cancelToken := tokenFrom(t, sender.messages[1].body, s.cfg.CancelURL)
+
+	require.NoError(t, s.ConfirmEmailChange(ctx, confirmToken))
+	require.NoError(t, s.CancelEmailChange(ctx, cancelToken))
+
+	assert.Equal(t, "old@example.com", repo.Users[userID].Email)
+	assert.True(t, repo.revoked)
+	assert.True(t, repo.tokens, "personal access tokens are revoked too")
+}
+
+func TestEmailChange_Expired(t *testing.T) {
+	ctx := context.Background()
+	s, _, sender := newTestService(t)
+
+	require.NoError(t, s.RequestUserEmailChange(ctx, 1, "new@example.com"))
+
+	s.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+
+	confirmToken := tokenFrom(t, sender.messages[0].body, s.cfg.ConfirmURL)
+	require.ErrorIs(t, s.ConfirmEmailChange(ctx, confirmToken), grpcerror.ErrEmailChangeNotFound)
+}
+
+func TestEmailChange_InvalidEmail(t *testing.T) {
+	s, _, sender := newTestService(t)
+
+	err := s.RequestUserEmailChange(context.Background(), 1, "not-an-email")
+	require.ErrorIs(t, err, grpcerror.ErrInvalidEmail)
+	assert.Empty(t, sender.messages)
+}
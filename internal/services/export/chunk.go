@@ -0,0 +1,49 @@
+package export
+
+// chunkWriter splits everything written to it into chunks of at most size bytes
+// and passes every full chunk to send. The remainder is sent by Flush.
+type chunkWriter struct {
+	buf  []byte
+	size int
+	send func(chunk []byte) error
+}
+
+func newChunkWriter(size int, send func(chunk []byte) error) *chunkWriter {
+	return &chunkWriter{
+		buf:  make([]byte, 0, size),
+		size: size,
+		send: send,
+	}
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	written := 0
+
+	for len(p) > 0 {
+		n := copy(w.buf[len(w.buf):w.size], p)
+		w.buf = w.buf[:len(w.buf)+n]
+		p = p[n:]
+		written += n
+
+		if len(w.buf) == w.size {
+			if err := w.Flush(); err != nil {
+				return written, err
+			}
+		}
+	}
+
+	return written, nil
+}
+
+// Flush sends the buffered data, if any. The chunk passed to send is only valid
+// until send returns.
+func (w *chunkWriter) Flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	err := w.send(w.buf)
+	w.buf = w.buf[:0]
+
+	return err
+}
@@ -0,0 +1,238 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/jwt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/repository"
+	"io"
+	"log/slog"
+	"time"
+)
+
+// FormatVersion is the version of the archive layout.
+const FormatVersion = 1
+
+// DefaultChunkSize keeps every chunk well below the default gRPC message size limit.
+const DefaultChunkSize = 64 * 1024
+
+type ExportService struct {
+	log       *slog.Logger
+	users     repository.UserInfoRepository
+	sessions  repository.SessionRepository
+	history   repository.LoginHistoryRepository
+	manager   *jwt.Manager
+	chunkSize int
+}
+
+// New creates and returns a new instance of the ExportService
+func New(
+	log *slog.Logger,
+	users repository.UserInfoRepository,
+	sessions repository.SessionRepository,
+	history repository.LoginHistoryRepository,
+	manager *jwt.Manager,
+	chunkSize int,
+) *ExportService {
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+
+	return &ExportService{
+		log:       log,
+		users:     users,
+		sessions:  sessions,
+		history:   history,
+		manager:   manager,
+		chunkSize: chunkSize,
+	}
+}
+
+// ExportMyData streams the data archive of the authenticated user making the request.
+func (s *ExportService) ExportMyData(ctx context.Context, send func(chunk []byte) error) error {
+	userID, err := s.manager.GetUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.ExportUserData(ctx, userID, send)
+}
+
+// ExportUserData assembles the profile, family memberships, sessions and login history of the user
+// with the provided user ID into a JSON archive and streams it through send in
+// chunks of at most the configured chunk size. A chunk is only valid until send
+// returns. The chunks form a single JSON document only when concatenated.
+func (s *ExportService) ExportUserData(
+	ctx context.Context,
+	userID int64,
+	send func(chunk []byte) error) error {
+	const op = "export.service.ExportUserData"
+
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	user, err := s.users.GetUserInfo(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	sessions, err := s.sessions.GetAllSessions(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	history, err := s.history.GetLoginHistory(ctx, userID, 0)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	w := newChunkWriter(s.chunkSize, send)
+
+	if err = writeArchive(w, user, sessions, history, time.Now().UTC()); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = w.Flush(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user data exported", slog.Int64("user_id", userID))
+
+	return nil
+}
+
+type profile struct {
+	UserID       int64      `json:"user_id"`
+	Email        string     `json:"email"`
+	PhoneNumber  string     `json:"phone_number"`
+	Name         string     `json:"name"`
+	Surname      string     `json:"surname"`
+	Role         string     `json:"role"`
+	RegisteredAt time.Time  `json:"registered_at"`
+	LastLoginAt  *time.Time `json:"last_login_at,omitempty"`
+}
+
+type family struct {
+	FamilyID int64 `json:"family_id"`
+}
+
+type loginAttempt struct {
+	Success       bool      `json:"success"`
+	FailureReason string    `json:"failure_reason,omitempty"`
+	IP            string    `json:"ip"`
+	UserAgent     string    `json:"user_agent"`
+	MFAUsed       bool      `json:"mfa_used"`
+	SessionID     string    `json:"session_id,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type session struct {
+	SessionID  string    `json:"session_id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	Revoked    bool      `json:"revoked"`
+}
+
+// writeArchive writes the archive as a single JSON object. List sections are
+// encoded element by element, so long histories are never held in one buffer.
+func writeArchive(
+	w io.Writer,
+	user models.User,
+	sessions []models.Session,
+	history []models.LoginAttempt,
+	exportedAt time.Time) error {
+	enc := json.NewEncoder(w)
+
+	header := fmt.Sprintf(`{"format_version":%d,"exported_at":"%s","profile":`,
+		FormatVersion, exportedAt.Format(time.RFC3339))
+	if _, err := io.WriteString(w, header); err != nil {
+		return err
+	}
+
+	err := enc.Encode(profile{
+		UserID:       user.ID,
+		Email:        user.Email,
+		PhoneNumber:  user.PhoneNumber,
+		Name:         user.Name,
+		Surname:      user.Surname,
+		Role:         string(user.Role),
+		RegisteredAt: user.RegisteredAt,
+		LastLoginAt:  user.LastLoginAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	families := make([]family, 0, len(user.FamilyIDs))
+	for _, id := range user.FamilyIDs {
+		families = append(families, family{FamilyID: id})
+	}
+
+	if err = writeList(w, enc, "families", families); err != nil {
+		return err
+	}
+
+	exported := make([]session, 0, len(sessions))
+	for _, sess := range sessions {
+		exported = append(exported, session{
+			SessionID:  sess.ID,
+			UserAgent:  sess.UserAgent,
+			IP:         sess.IP,
+			CreatedAt:  sess.CreatedAt,
+			LastSeenAt: sess.LastSeenAt,
+			Revoked:    sess.Revoked,
+		})
+	}
+
+	if err = writeList(w, enc, "sessions", exported); err != nil {
+		return err
+	}
+
+	attempts := make([]loginAttempt, 0, len(history))
+	for _, attempt := range history {
+		attempts = append(attempts, loginAttempt{
+			Success:       attempt.Success,
+			FailureReason: attempt.FailureReason,
+			IP:            attempt.IP,
+			UserAgent:     attempt.UserAgent,
+			MFAUsed:       attempt.MFAUsed,
+			SessionID:     attempt.SessionID,
+			CreatedAt:     attempt.CreatedAt,
+		})
+	}
+
+	if err = writeList(w, enc, "login_history", attempts); err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "}")
+
+	return err
+}
+
+func writeList[T any](w io.Writer, enc *json.Encoder, name string, items []T) error {
+	if _, err := fmt.Fprintf(w, `,%q:[`, name); err != nil {
+		return err
+	}
+
+	for i, item := range items {
+		if i > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+
+		if err := enc.Encode(item); err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(w, "]")
+
+	return err
+}
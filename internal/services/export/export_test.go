@@ -0,0 +1,95 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/repository"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services/servicetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type fakeHistory struct {
+	repository.LoginHistoryRepository
+	attempts []models.LoginAttempt
+}
+
+func (r *fakeHistory) GetLoginHistory(_ context.Context, _ int64, _ int64) ([]models.LoginAttempt, error) {
+	return r.attempts, nil
+}
+
+type fakeSessions struct {
+	repository.SessionRepository
+	sessions []models.Session
+}
+
+func (r *fakeSessions) GetAllSessions(_ context.Context, _ int64) ([]models.Session, error) {
+	return r.sessions, nil
+}
+
+func TestExportUserData_Chunks(t *testing.T) {
+	const chunkSize = 128
+
+	user := models.User{
+		ID:        7,
+		Email:     "john@example.com",
+		Name:      "John",
+		Role:      models.UserRole,
+		FamilyIDs: []int64{1, 2},
+	}
+
+	sessions := make([]models.Session, 50)
+	for i := range sessions {
+		sessions[i] = models.Session{
+			ID:        fmt.Sprintf("session-%d", i),
+			UserID:    user.ID,
+			UserAgent: "grpc-go",
+			CreatedAt: time.Now().UTC(),
+			Revoked:   i%2 == 0,
+		}
+	}
+
+	history := []models.LoginAttempt{
+		{UserID: user.ID, Success: true, SessionID: "session-49"},
+		{UserID: user.ID, FailureReason: models.LoginInvalidPassword},
+	}
+
+	s := New(servicetest.Logger(),
+		servicetest.NewRepository(user),
+		&fakeSessions{sessions: sessions},
+		&fakeHistory{attempts: history},
+		nil, chunkSize)
+
+	var archive []byte
+	chunks := 0
+
+	err := s.ExportUserData(context.Background(), user.ID, func(chunk []byte) error {
+		require.LessOrEqual(t, len(chunk), chunkSize)
+		archive = append(archive, chunk...)
+		chunks++
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Greater(t, chunks, 1)
+
+	var decoded struct {
+		FormatVersion int     `json:"format_version"`
+		Profile       profile `json:"profile"`
+		Families      []family
+		Sessions      []session
+		LoginHistory  []loginAttempt `json:"login_history"`
+	}
+	require.NoError(t, json.Unmarshal(archive, &decoded))
+
+	assert.Equal(t, FormatVersion, decoded.FormatVersion)
+	assert.Equal(t, user.Email, decoded.Profile.Email)
+	assert.Equal(t, []family{{FamilyID: 1}, {FamilyID: 2}}, decoded.Families)
+	require.Len(t, decoded.Sessions, len(sessions))
+	assert.Equal(t, "session-49", decoded.Sessions[49].SessionID)
+	require.Len(t, decoded.LoginHistory, len(history))
+	assert.Equal(t, models.LoginInvalidPassword, decoded.LoginHistory[1].FailureReason)
+}
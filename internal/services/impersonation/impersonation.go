@@ -0,0 +1,169 @@
+package impersonation
+
+import (
+	"context"
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/clients"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/jwt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/meta"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/repository"
+	"log/slog"
+	"time"
+)
+
+type ImpersonationService struct {
+	log      *slog.Logger
+	users    repository.UserInfoRepository
+	sessions repository.SessionRepository
+	audit    repository.AuditRepository
+	manager  *jwt.Manager
+	clients  *clients.Registry
+	cfg      *config.ImpersonationConfig
+	now      func() time.Time
+}
+
+// New creates and returns a new instance of the ImpersonationService
+func New(
+	log *slog.Logger,
+	users repository.UserInfoRepository,
+	sessions repository.SessionRepository,
+	audit repository.AuditRepository,
+	manager *jwt.Manager,
+	clientRegistry *clients.Registry,
+	cfg *config.ImpersonationConfig,
+) *ImpersonationService {
+	return &ImpersonationService{
+		log:      log,
+		users:    users,
+		sessions: sessions,
+		audit:    audit,
+		manager:  manager,
+		clients:  clientRegistry,
+		cfg:      cfg,
+		now:      time.Now,
+	}
+}
+
+// Impersonate issues a token for the user with the provided user ID to the admin
+// making the request. Tokens issued by impersonation and personal access tokens
+// cannot be used to impersonate. It delegates to the ImpersonateUser method.
+func (s *ImpersonationService) Impersonate(ctx context.Context, userID int64, reason string) (string, error) {
+	claims, err := s.manager.GetClaims(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if _, impersonating, _ := jwt.ActorIDFromClaims(claims); impersonating {
+		return "", grpcerror.ErrImpersonationDenied
+	}
+	if _, ok := jwt.AccessTokenIDFromClaims(claims); ok {
+		return "", grpcerror.ErrForbidden
+	}
+
+	actorID, err := jwt.UserIDFromClaims(claims)
+	if err != nil {
+		return "", err
+	}
+
+	return s.ImpersonateUser(ctx, actorID, userID, reason)
+}
+
+// ImpersonateUser issues a short-lived token for the user with the provided user ID
+// to the admin with the provided actor ID. The token is bound to a new session of
+// the user that names the admin, carries the admin in the "act" claim, is issued
+// for the service itself with all its scopes and expires after the impersonation
+// token TTL. Admins cannot be impersonated. The start of
+// the impersonation is recorded in the audit trail along with the reason, and no
+// token is issued if it cannot be recorded.
+func (s *ImpersonationService) ImpersonateUser(
+	ctx context.Context,
+	actorID, userID int64,
+	reason string) (string, error) {
+	const op = "impersonation.service.ImpersonateUser"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("actor_id", actorID),
+		slog.Int64("user_id", userID),
+	)
+
+	actor, err := s.users.GetUserInfo(ctx, actorID)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	if actor.Role != models.AdminRole {
+		return "", grpcerror.ErrForbidden
+	}
+
+	user, err := s.users.GetUserInfo(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	if user.Role == models.AdminRole {
+		log.Warn("attempt to impersonate an admin")
+		return "", grpcerror.ErrImpersonateAdmin
+	}
+
+	switch user.EffectiveStatus(s.now()) {
+	case models.StatusSuspended:
+		return "", grpcerror.ErrUserSuspended
+	case models.StatusDisabled:
+		return "", grpcerror.ErrUserDisabled
+	}
+
+	grant, err := s.clients.Resolve(models.Grant{})
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := s.now().UTC()
+
+	sessionID, err := s.sessions.CreateSession(ctx, &models.Session{
+		UserID:     userID,
+		UserAgent:  meta.UserAgent(ctx),
+		IP:         meta.ClientIP(ctx),
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ActorID:    actorID,
+	})
+	if err != nil {
+		log.Error("failed to create session", sl.Err(err))
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.audit.RecordAuditEvent(ctx, &models.AuditEvent{
+		Action:    models.AuditImpersonationStarted,
+		ActorID:   actorID,
+		UserID:    userID,
+		SessionID: sessionID,
+		Reason:    reason,
+		IP:        meta.ClientIP(ctx),
+		UserAgent: meta.UserAgent(ctx),
+		CreatedAt: now,
+	})
+	if err != nil {
+		s.revokeSession(ctx, log, userID, sessionID)
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	token, err := s.manager.NewImpersonationToken(user, sessionID, actorID, grant, s.cfg.TokenTTL)
+	if err != nil {
+		log.Error("failed to generate jwt-token", sl.Err(err))
+		s.revokeSession(ctx, log, userID, sessionID)
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("impersonation started", slog.String("session_id", sessionID))
+
+	return token, nil
+}
+
+func (s *ImpersonationService) revokeSession(ctx context.Context, log *slog.Logger, userID int64, sessionID string) {
+	if err := s.sessions.RevokeSession(ctx, userID, sessionID); err != nil {
+		log.Error("failed to revoke impersonation session", sl.Err(err), slog.String("session_id", sessionID))
+	}
+}
@@ -0,0 +1,91 @@
+package impersonation
+
+import (
+	"context"
+	"errors"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/clients"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/jwt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services/servicetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+const (
+	adminID      = 1
+	otherAdminID = 2
+	userID       = 3
+)
+
+func newTestService() (*ImpersonationService, *servicetest.Repository, *jwt.Manager) {
+	repo := servicetest.NewRepository(
+		models.User{ID: adminID, Role: models.AdminRole},
+		models.User{ID: otherAdminID, Role: models.AdminRole},
+		models.User{ID: userID, Email: "user@example.com", Role: models.UserRole},
+	)
+	manager := servicetest.Manager()
+
+	registry, err := clients.NewRegistry(&config.TokenConfig{Audience: "sso", Scopes: []string{"profile:read"}}, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	return New(servicetest.Logger(), repo, repo, repo, manager, registry,
+		&config.ImpersonationConfig{TokenTTL: 15 * time.Minute}), repo, manager
+}
+
+func TestImpersonateUser(t *testing.T) {
+	s, repo, manager := newTestService()
+
+	token, err := s.ImpersonateUser(context.Background(), adminID, userID, "ticket 42")
+	require.NoError(t, err)
+
+	claims, err := manager.ParseToken(token)
+	require.NoError(t, err)
+
+	id, err := jwt.UserIDFromClaims(claims)
+	require.NoError(t, err)
+	assert.Equal(t, int64(userID), id)
+
+	actorID, impersonated, err := jwt.ActorIDFromClaims(claims)
+	require.NoError(t, err)
+	assert.True(t, impersonated)
+	assert.Equal(t, int64(adminID), actorID)
+	assert.True(t, jwt.HasAudience(claims, "sso"))
+	assert.Equal(t, []string{"profile:read"}, jwt.ScopesFromClaims(claims))
+
+	require.Len(t, repo.Sessions, 1)
+	assert.Equal(t, int64(adminID), repo.Sessions["session-1"].ActorID)
+
+	require.Len(t, repo.Events, 1)
+	assert.Equal(t, models.AuditImpersonationStarted, repo.Events[0].Action)
+	assert.Equal(t, "ticket 42", repo.Events[0].Reason)
+	assert.Equal(t, "session-1", repo.Events[0].SessionID)
+}
+
+func TestImpersonateUser_Rejected(t *testing.T) {
+	s, repo, _ := newTestService()
+	ctx := context.Background()
+
+	_, err := s.ImpersonateUser(ctx, adminID, otherAdminID, "")
+	assert.True(t, errors.Is(err, grpcerror.ErrImpersonateAdmin))
+
+	_, err = s.ImpersonateUser(ctx, userID, adminID, "")
+	assert.True(t, errors.Is(err, grpcerror.ErrForbidden))
+
+	assert.Empty(t, repo.Sessions)
+	assert.Empty(t, repo.Events)
+}
+
+func TestImpersonateUser_AuditFailure(t *testing.T) {
+	s, repo, _ := newTestService()
+	repo.AuditErr = errors.New("audit unavailable")
+
+	_, err := s.ImpersonateUser(context.Background(), adminID, userID, "")
+	require.Error(t, err)
+	assert.Equal(t, []string{"session-1"}, repo.Revoked)
+}
@@ -0,0 +1,207 @@
+package introspection
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/clients"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/jwt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/token"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/repository"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/repository/cache"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+const cacheKeyPrefix = "introspection:"
+
+type IntrospectionService struct {
+	log      *slog.Logger
+	users    repository.UserInfoRepository
+	sessions repository.SessionRepository
+	manager  *jwt.Manager
+	clients  *clients.Registry
+	cache    cache.Store
+	cacheTTL time.Duration
+	now      func() time.Time
+}
+
+// New creates and returns a new instance of the IntrospectionService
+func New(
+	log *slog.Logger,
+	users repository.UserInfoRepository,
+	sessions repository.SessionRepository,
+	manager *jwt.Manager,
+	clientRegistry *clients.Registry,
+	cfg *config.IntrospectionConfig,
+) *IntrospectionService {
+	return &IntrospectionService{
+		log:      log,
+		users:    users,
+		sessions: sessions,
+		manager:  manager,
+		clients:  clientRegistry,
+		cache:    cache.NewLRUStore(cfg.CacheSize),
+		cacheTTL: cfg.CacheTTL,
+		now:      time.Now,
+	}
+}
+
+// Introspect reports whether the provided token is active to the service
+// authenticated by the provided client credentials. A token is active if its
+// signature and issuer are valid, it has not expired, its session was not revoked and its user,
+// and the admin impersonating the user if any, exist and are active. Active tokens
+// are reported with their normalized claims. Results are cached by the hash of the
+// token for the cache TTL, but never beyond the expiration of the token.
+func (s *IntrospectionService) Introspect(
+	ctx context.Context,
+	clientID, clientSecret, accessToken string) (models.Introspection, error) {
+	const op = "introspection.service.Introspect"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("client_id", clientID),
+	)
+
+	if err := s.clients.Authenticate(clientID, clientSecret); err != nil {
+		log.Warn("client authentication failed")
+		return models.Introspection{}, err
+	}
+
+	key := cacheKeyPrefix + token.Hash(accessToken)
+
+	if raw, ok, _ := s.cache.Get(ctx, key); ok {
+		var result models.Introspection
+		if err := json.Unmarshal(raw, &result); err == nil {
+			return result, nil
+		}
+	}
+
+	result, err := s.inspect(ctx, accessToken)
+	if err != nil {
+		log.Error("failed to introspect token", sl.Err(err))
+		return models.Introspection{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	ttl := s.cacheTTL
+	if result.Active {
+		if untilExpiry := time.Unix(result.ExpiresAt, 0).Sub(s.now()); untilExpiry < ttl {
+			ttl = untilExpiry
+		}
+	}
+
+	if raw, err := json.Marshal(result); err == nil && ttl > 0 {
+		_ = s.cache.Set(ctx, key, raw, ttl)
+	}
+
+	return result, nil
+}
+
+// inspect checks the token against the stored state. Only failures to read the
+// state are returned as errors, every reason to reject the token yields an inactive
+// result.
+func (s *IntrospectionService) inspect(ctx context.Context, accessToken string) (models.Introspection, error) {
+	inactive := models.Introspection{}
+
+	claims, err := s.manager.ParseToken(accessToken)
+	if err != nil {
+		return inactive, nil
+	}
+
+	userID, err := jwt.UserIDFromClaims(claims)
+	if err != nil {
+		return inactive, nil
+	}
+
+	sessionID, err := jwt.SessionIDFromClaims(claims)
+	if err != nil {
+		return inactive, nil
+	}
+
+	expiresAt, err := jwt.ExpiresAtFromClaims(claims)
+	if err != nil {
+		return inactive, nil
+	}
+
+	issuedAt, err := jwt.IssuedAtFromClaims(claims)
+	if err != nil {
+		return inactive, nil
+	}
+
+	actorID, impersonated, err := jwt.ActorIDFromClaims(claims)
+	if err != nil {
+		return inactive, nil
+	}
+
+	audience, err := jwt.AudienceFromClaims(claims)
+	if err != nil {
+		return inactive, nil
+	}
+
+	attrs, err := jwt.AttributesFromClaims(claims)
+	if err != nil {
+		return inactive, nil
+	}
+
+	session, err := s.sessions.GetSession(ctx, sessionID)
+	if errors.Is(err, grpcerror.ErrSessionNotFound) {
+		return inactive, nil
+	}
+	if err != nil {
+		return inactive, err
+	}
+	if session.Revoked || session.UserID != userID {
+		return inactive, nil
+	}
+
+	user, active, err := s.activeUser(ctx, userID)
+	if err != nil || !active {
+		return inactive, err
+	}
+
+	if impersonated {
+		actor, active, err := s.activeUser(ctx, actorID)
+		if err != nil || !active || actor.Role != models.AdminRole {
+			return inactive, err
+		}
+	}
+
+	issuer, _ := claims["iss"].(string)
+	subject, _ := claims["sub"].(string)
+
+	return models.Introspection{
+		Active:     true,
+		Issuer:     issuer,
+		Subject:    subject,
+		Audience:   audience,
+		Scope:      strings.Join(jwt.ScopesFromClaims(claims), " "),
+		IssuedAt:   issuedAt,
+		UserID:     user.ID,
+		Email:      user.Email,
+		Role:       user.Role,
+		SessionID:  sessionID,
+		ActorID:    actorID,
+		ExpiresAt:  expiresAt,
+		Attributes: attrs,
+	}, nil
+}
+
+// activeUser returns the user with the provided user ID and whether the user exists
+// and is active.
+func (s *IntrospectionService) activeUser(ctx context.Context, userID int64) (models.User, bool, error) {
+	user, err := s.users.GetUserInfo(ctx, userID)
+	if errors.Is(err, grpcerror.ErrUserNotFound) {
+		return models.User{}, false, nil
+	}
+	if err != nil {
+		return models.User{}, false, err
+	}
+
+	return user, user.EffectiveStatus(s.now()) == models.StatusActive, nil
+}
@@ -0,0 +1,199 @@
+package introspection
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/clients"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/jwt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services/servicetest"
+	gojwt "github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+const (
+	clientID     = "family"
+	clientSecret = "family-secret"
+
+	userID  = 1
+	adminID = 2
+)
+
+var grant = models.Grant{Audience: "sso", Scopes: []string{"profile:read", "profile:write"}}
+
+func newTestService(t *testing.T) (*IntrospectionService, *servicetest.Repository, *jwt.Manager) {
+	t.Helper()
+
+	sum := sha256.Sum256([]byte(clientSecret))
+	registry, err := clients.NewRegistry(&config.TokenConfig{Audience: "sso"}, []config.ServiceClient{
+		{ID: clientID, SecretHash: hex.EncodeToString(sum[:])},
+	})
+	require.NoError(t, err)
+
+	repo := servicetest.NewRepository(
+		models.User{ID: userID, Email: "user@example.com", Role: models.UserRole},
+		models.User{ID: adminID, Email: "admin@example.com", Role: models.AdminRole},
+	)
+	repo.Sessions["session"] = models.Session{ID: "session", UserID: userID}
+	repo.Sessions["impersonation"] = models.Session{ID: "impersonation", UserID: userID, ActorID: adminID}
+
+	manager := servicetest.Manager()
+
+	s := New(servicetest.Logger(), repo, repo, manager, registry, &config.IntrospectionConfig{
+		CacheTTL:  time.Minute,
+		CacheSize: 100,
+	})
+
+	return s, repo, manager
+}
+
+func TestIntrospect_Active(t *testing.T) {
+	s, repo, manager := newTestService(t)
+	ctx := context.Background()
+
+	token, err := manager.NewToken(repo.Users[userID], "session", grant, map[string]interface{}{"profile.locale": "en"})
+	require.NoError(t, err)
+
+	result, err := s.Introspect(ctx, clientID, clientSecret, token)
+	require.NoError(t, err)
+	assert.True(t, result.Active)
+	assert.Equal(t, int64(userID), result.UserID)
+	assert.Equal(t, "user@example.com", result.Email)
+	assert.Equal(t, models.UserRole, result.Role)
+	assert.Equal(t, "session", result.SessionID)
+	assert.Equal(t, "sso", result.Issuer)
+	assert.Equal(t, "1", result.Subject)
+	assert.Equal(t, []string{"sso"}, result.Audience)
+	assert.Equal(t, "profile:read profile:write", result.Scope)
+	assert.InDelta(t, time.Now().Unix(), result.IssuedAt, 5)
+	assert.Zero(t, result.ActorID)
+	assert.Equal(t, "en", result.Attributes["profile.locale"])
+	assert.InDelta(t, time.Now().Add(time.Hour).Unix(), result.ExpiresAt, 5)
+
+	impersonation, err := manager.NewImpersonationToken(repo.Users[userID], "impersonation", adminID, grant, time.Minute)
+	require.NoError(t, err)
+
+	result, err = s.Introspect(ctx, clientID, clientSecret, impersonation)
+	require.NoError(t, err)
+	assert.True(t, result.Active)
+	assert.Equal(t, int64(adminID), result.ActorID)
+}
+
+// resign returns the provided token with the claims changed by update.
+func resign(t *testing.T, manager *jwt.Manager, token string, update func(claims gojwt.MapClaims)) string {
+	t.Helper()
+
+	claims, err := manager.ParseToken(token)
+	require.NoError(t, err)
+
+	update(claims)
+
+	signed, err := gojwt.NewWithClaims(gojwt.SigningMethodHS384, claims).SignedString([]byte("secret"))
+	require.NoError(t, err)
+
+	return signed
+}
+
+func TestIntrospect_Claims(t *testing.T) {
+	s, repo, manager := newTestService(t)
+	ctx := context.Background()
+
+	token, err := manager.NewToken(repo.Users[userID], "session", grant, nil)
+	require.NoError(t, err)
+
+	multiple := resign(t, manager, token, func(claims gojwt.MapClaims) {
+		claims["aud"] = []interface{}{"sso", "family"}
+	})
+
+	result, err := s.Introspect(ctx, clientID, clientSecret, multiple)
+	require.NoError(t, err)
+	assert.True(t, result.Active)
+	assert.Equal(t, []string{"sso", "family"}, result.Audience)
+
+	for name, update := range map[string]func(claims gojwt.MapClaims){
+		"invalid audience":   func(claims gojwt.MapClaims) { claims["aud"] = []interface{}{"sso", 1} },
+		"invalid attributes": func(claims gojwt.MapClaims) { claims["attrs"] = "profile.locale=en" },
+	} {
+		t.Run(name, func(t *testing.T) {
+			result, err := s.Introspect(ctx, clientID, clientSecret, resign(t, manager, token, update))
+			require.NoError(t, err)
+			assert.Equal(t, models.Introspection{}, result)
+		})
+	}
+}
+
+func TestIntrospect_Inactive(t *testing.T) {
+	s, repo, manager := newTestService(t)
+	ctx := context.Background()
+
+	revoked := repo.Sessions["session"]
+	revoked.ID, revoked.Revoked = "revoked", true
+	repo.Sessions["revoked"] = revoked
+
+	suspended := models.User{ID: 3, Role: models.UserRole, Status: models.StatusSuspended}
+	repo.Users[suspended.ID] = suspended
+	repo.Sessions["suspended"] = models.Session{ID: "suspended", UserID: suspended.ID}
+
+	expired, err := jwt.New([]byte(servicetest.SigningKey), -time.Minute, servicetest.Issuer).NewToken(repo.Users[userID], "session", grant, nil)
+	require.NoError(t, err)
+	forged, err := jwt.New([]byte("other"), time.Hour, servicetest.Issuer).NewToken(repo.Users[userID], "session", grant, nil)
+	require.NoError(t, err)
+	otherIssuer, err := jwt.New([]byte(servicetest.SigningKey), time.Hour, "other").NewToken(repo.Users[userID], "session", grant, nil)
+	require.NoError(t, err)
+	revokedToken, err := manager.NewToken(repo.Users[userID], "revoked", grant, nil)
+	require.NoError(t, err)
+	unknownSession, err := manager.NewToken(repo.Users[userID], "unknown", grant, nil)
+	require.NoError(t, err)
+	suspendedToken, err := manager.NewToken(suspended, "suspended", grant, nil)
+	require.NoError(t, err)
+
+	for name, token := range map[string]string{
+		"garbage":         "not a token",
+		"expired":         expired,
+		"forged":          forged,
+		"other issuer":    otherIssuer,
+		"revoked session": revokedToken,
+		"unknown session": unknownSession,
+		"suspended user":  suspendedToken,
+	} {
+		t.Run(name, func(t *testing.T) {
+			result, err := s.Introspect(ctx, clientID, clientSecret, token)
+			require.NoError(t, err)
+			assert.Equal(t, models.Introspection{}, result)
+		})
+	}
+}
+
+func TestIntrospect_Client(t *testing.T) {
+	s, repo, manager := newTestService(t)
+
+	token, err := manager.NewToken(repo.Users[userID], "session", grant, nil)
+	require.NoError(t, err)
+
+	_, err = s.Introspect(context.Background(), clientID, "wrong", token)
+	assert.True(t, errors.Is(err, grpcerror.ErrInvalidClient))
+	assert.Zero(t, repo.SessionLookups)
+}
+
+func TestIntrospect_Cached(t *testing.T) {
+	s, repo, manager := newTestService(t)
+	ctx := context.Background()
+
+	token, err := manager.NewToken(repo.Users[userID], "session", grant, nil)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		result, err := s.Introspect(ctx, clientID, clientSecret, token)
+		require.NoError(t, err)
+		assert.True(t, result.Active)
+	}
+
+	assert.Equal(t, 1, repo.SessionLookups)
+}
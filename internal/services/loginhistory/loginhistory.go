@@ -0,0 +1,63 @@
+package loginhistory
+
+import (
+	"context"
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/jwt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/repository"
+	"log/slog"
+)
+
+// MaxLimit is the maximum number of sign-in attempts returned at once.
+const MaxLimit = 100
+
+type LoginHistoryService struct {
+	log     *slog.Logger
+	repo    repository.LoginHistoryRepository
+	manager *jwt.Manager
+}
+
+// New creates and returns a new instance of the LoginHistoryService
+func New(
+	log *slog.Logger,
+	repo repository.LoginHistoryRepository,
+	manager *jwt.Manager,
+) *LoginHistoryService {
+	return &LoginHistoryService{
+		log:     log,
+		repo:    repo,
+		manager: manager,
+	}
+}
+
+// GetLoginHistory retrieves the most recent sign-in attempts of the authenticated
+// user making the request.
+func (s *LoginHistoryService) GetLoginHistory(ctx context.Context, limit int64) ([]models.LoginAttempt, error) {
+	userID, err := s.manager.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetUserLoginHistory(ctx, userID, limit)
+}
+
+// GetUserLoginHistory retrieves the most recent sign-in attempts of the user with
+// the provided user ID. The limit is capped at MaxLimit.
+func (s *LoginHistoryService) GetUserLoginHistory(
+	ctx context.Context,
+	userID int64,
+	limit int64) ([]models.LoginAttempt, error) {
+	const op = "loginhistory.service.GetUserLoginHistory"
+
+	if limit <= 0 || limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	attempts, err := s.repo.GetLoginHistory(ctx, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return attempts, nil
+}
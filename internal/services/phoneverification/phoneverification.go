@@ -0,0 +1,152 @@
+package phoneverification
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/jwt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/phone"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/token"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/repository"
+	"log/slog"
+	"time"
+)
+
+const codeText = "Your verification code is %s. It expires in %s."
+
+type PhoneVerificationService struct {
+	log           *slog.Logger
+	users         repository.UserInfoRepository
+	verifications repository.PhoneVerificationRepository
+	manager       *jwt.Manager
+	sender        phone.SMSSender
+	cfg           *config.PhoneConfig
+	now           func() time.Time
+}
+
+// New creates and returns a new instance of the PhoneVerificationService
+func New(
+	log *slog.Logger,
+	users repository.UserInfoRepository,
+	verifications repository.PhoneVerificationRepository,
+	manager *jwt.Manager,
+	sender phone.SMSSender,
+	cfg *config.PhoneConfig,
+) *PhoneVerificationService {
+	return &PhoneVerificationService{
+		log:           log,
+		users:         users,
+		verifications: verifications,
+		manager:       manager,
+		sender:        sender,
+		cfg:           cfg,
+		now:           time.Now,
+	}
+}
+
+// SendPhoneCode sends a verification code to the phone number of the authenticated
+// user making the request. It delegates to the SendUserPhoneCode method.
+func (s *PhoneVerificationService) SendPhoneCode(ctx context.Context) error {
+	userID, err := s.manager.GetUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.SendUserPhoneCode(ctx, userID)
+}
+
+// SendUserPhoneCode generates a one-time code, stores its hash as the phone
+// verification of the user with the provided user ID and sends the code to the
+// phone number of the user by SMS. A new code replaces the previous one, but can
+// be requested only once per resend interval.
+func (s *PhoneVerificationService) SendUserPhoneCode(ctx context.Context, userID int64) error {
+	const op = "phoneverification.service.SendUserPhoneCode"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	user, err := s.users.GetUserInfo(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if user.PhoneNumber == "" {
+		return grpcerror.ErrInvalidPhone
+	}
+	if user.PhoneVerified {
+		return grpcerror.ErrPhoneVerified
+	}
+
+	now := s.now().UTC()
+
+	if v := user.PhoneVerification; v != nil && now.Before(v.SentAt.Add(s.cfg.ResendInterval)) {
+		return grpcerror.ErrCodeRecentlySent
+	}
+
+	code, err := token.Digits(s.cfg.CodeLength)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	verification := &models.PhoneVerification{
+		CodeHash:  token.Hash(code),
+		SentAt:    now,
+		ExpiresAt: now.Add(s.cfg.CodeTTL),
+	}
+
+	if err = s.verifications.SetPhoneVerification(ctx, userID, verification); err != nil {
+		return err
+	}
+
+	if err = s.sender.Send(ctx, user.PhoneNumber, fmt.Sprintf(codeText, code, s.cfg.CodeTTL)); err != nil {
+		log.Error("failed to send verification code", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("verification code sent")
+
+	return nil
+}
+
+// VerifyPhone verifies the phone number of the authenticated user making the
+// request. It delegates to the VerifyUserPhone method.
+func (s *PhoneVerificationService) VerifyPhone(ctx context.Context, code string) error {
+	userID, err := s.manager.GetUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.VerifyUserPhone(ctx, userID, code)
+}
+
+// VerifyUserPhone marks the phone number of the user with the provided user ID as
+// verified if the code matches the one sent to it. Every call uses one of the
+// attempts of the verification, ErrVerificationNotFound is returned once they are
+// exhausted or the code has expired.
+func (s *PhoneVerificationService) VerifyUserPhone(ctx context.Context, userID int64, code string) error {
+	const op = "phoneverification.service.VerifyUserPhone"
+
+	verification, err := s.verifications.UsePhoneVerificationAttempt(
+		ctx, userID, s.now().UTC(), s.cfg.MaxAttempts)
+	if err != nil {
+		return err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(token.Hash(code)), []byte(verification.CodeHash)) != 1 {
+		return grpcerror.ErrInvalidCode
+	}
+
+	if err = s.verifications.ConfirmPhone(ctx, userID, verification.CodeHash); err != nil {
+		return err
+	}
+
+	s.log.Info("phone verified", slog.String("op", op), slog.Int64("user_id", userID))
+
+	return nil
+}
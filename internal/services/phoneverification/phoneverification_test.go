@@ -0,0 +1,123 @@
+package phoneverification
+
+import (
+	"context"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services/servicetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+const userID = 1
+
+type fakeRepo struct {
+	*servicetest.Repository
+}
+
+func (r *fakeRepo) SetPhoneVerification(_ context.Context, userID int64, v *models.PhoneVerification) error {
+	user := r.Users[userID]
+	user.PhoneVerification = v
+	r.Users[userID] = user
+	return nil
+}
+
+func (r *fakeRepo) UsePhoneVerificationAttempt(
+	_ context.Context,
+	userID int64,
+	now time.Time,
+	maxAttempts int) (models.PhoneVerification, error) {
+	v := r.Users[userID].PhoneVerification
+	if v == nil || !now.Before(v.ExpiresAt) || v.Attempts >= maxAttempts {
+		return models.PhoneVerification{}, grpcerror.ErrVerificationNotFound
+	}
+	v.Attempts++
+	return *v, nil
+}
+
+func (r *fakeRepo) ConfirmPhone(_ context.Context, userID int64, codeHash string) error {
+	user := r.Users[userID]
+	if user.PhoneVerification == nil || user.PhoneVerification.CodeHash != codeHash {
+		return grpcerror.ErrVerificationNotFound
+	}
+	user.PhoneVerified = true
+	user.PhoneVerification = nil
+	r.Users[userID] = user
+	return nil
+}
+
+type fakeSender struct {
+	to, text string
+}
+
+func (s *fakeSender) Send(_ context.Context, to, text string) error {
+	s.to, s.text = to, text
+	return nil
+}
+
+func newTestService(t *testing.T) (*PhoneVerificationService, *fakeRepo, *fakeSender) {
+	t.Helper()
+
+	repo := &fakeRepo{servicetest.NewRepository(models.User{ID: userID, PhoneNumber: "+375291234567"})}
+	sender := &fakeSender{}
+
+	cfg := &config.PhoneConfig{
+		CodeLength:     6,
+		CodeTTL:        10 * time.Minute,
+		MaxAttempts:    3,
+		ResendInterval: time.Minute,
+	}
+
+	return New(servicetest.Logger(), repo, repo, nil, sender, cfg), repo, sender
+}
+
+// codeFrom extracts the code from the text of the message.
+func codeFrom(text string) string {
+	return strings.TrimSuffix(strings.Fields(text)[4], ".")
+}
+
+func TestVerifyPhone_HappyPath(t *testing.T) {
+	ctx := context.Background()
+	s, repo, sender := newTestService(t)
+
+	require.NoError(t, s.SendUserPhoneCode(ctx, userID))
+	assert.Equal(t, "+375291234567", sender.to)
+
+	code := codeFrom(sender.text)
+	require.Len(t, code, 6)
+
+	require.NoError(t, s.VerifyUserPhone(ctx, userID, code))
+	assert.True(t, repo.Users[userID].PhoneVerified)
+
+	require.ErrorIs(t, s.SendUserPhoneCode(ctx, userID), grpcerror.ErrPhoneVerified)
+}
+
+func TestVerifyPhone_AttemptsExhausted(t *testing.T) {
+	ctx := context.Background()
+	s, repo, sender := newTestService(t)
+
+	require.NoError(t, s.SendUserPhoneCode(ctx, userID))
+	code := codeFrom(sender.text)
+
+	for i := 0; i < s.cfg.MaxAttempts; i++ {
+		require.ErrorIs(t, s.VerifyUserPhone(ctx, userID, "wrong"), grpcerror.ErrInvalidCode)
+	}
+
+	require.ErrorIs(t, s.VerifyUserPhone(ctx, userID, code), grpcerror.ErrVerificationNotFound)
+	assert.False(t, repo.Users[userID].PhoneVerified)
+}
+
+func TestSendPhoneCode_ResendInterval(t *testing.T) {
+	ctx := context.Background()
+	s, _, _ := newTestService(t)
+
+	require.NoError(t, s.SendUserPhoneCode(ctx, userID))
+	require.ErrorIs(t, s.SendUserPhoneCode(ctx, userID), grpcerror.ErrCodeRecentlySent)
+
+	s.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	require.NoError(t, s.SendUserPhoneCode(ctx, userID))
+}
@@ -2,6 +2,8 @@ package services
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
 )
@@ -10,10 +12,11 @@ type Services interface {
 	Auth
 	Permissions
 	UserInfo
+	Sessions
 }
 
 type Auth interface {
-	SignIn(ctx context.Context, email, password string) (string, error)
+	SignIn(ctx context.Context, identifier, password string, grant models.Grant) (string, error)
 	SignUp(ctx context.Context, user *models.User) (int64, error)
 }
 
@@ -24,13 +27,73 @@ type Permissions interface {
 type UserInfo interface {
 	GetUserInfo(ctx context.Context) (models.User, error)
 	GetUserInfoByID(ctx context.Context, userID int64) (models.User, error)
-	UpdateUserInfo(ctx context.Context, updatedUser *models.User) error
+	UpdateUserInfo(ctx context.Context, updatedUser *models.User, fields []string, expectedVersion *int64) error
 	ChangePassword(ctx context.Context, oldPassword, newPasswordHash string) error
 	DeleteUser(ctx context.Context, userID int64) error
+	RestoreUser(ctx context.Context, userID int64) error
+	SetUserStatus(ctx context.Context, userID int64, status models.AccountStatus, reason string, until *time.Time) error
 	AddFamily(ctx context.Context, familyID int64, userID int64) error
 	DeleteFamily(ctx context.Context, familyID int64, userID int64) error
 }
 
+type EmailChange interface {
+	RequestEmailChange(ctx context.Context, newEmail string) error
+	ConfirmEmailChange(ctx context.Context, confirmToken string) error
+	CancelEmailChange(ctx context.Context, cancelToken string) error
+}
+
+type PhoneVerification interface {
+	SendPhoneCode(ctx context.Context) error
+	VerifyPhone(ctx context.Context, code string) error
+}
+
+type Attributes interface {
+	GetAttributes(ctx context.Context, userID int64) ([]models.Attribute, error)
+	SetAttributes(ctx context.Context, userID int64, attrs []models.Attribute) error
+}
+
+type Avatars interface {
+	UploadAvatar(ctx context.Context, r io.Reader) error
+	GetAvatar(ctx context.Context, userID int64, size int) (models.AvatarImage, error)
+	DeleteAvatar(ctx context.Context) error
+}
+
+type Impersonation interface {
+	Impersonate(ctx context.Context, userID int64, reason string) (string, error)
+}
+
+type Introspection interface {
+	Introspect(ctx context.Context, clientID, clientSecret, accessToken string) (models.Introspection, error)
+}
+
+type AccessTokens interface {
+	CreateToken(
+		ctx context.Context,
+		userID int64,
+		name string,
+		scopes []string,
+		ttl time.Duration) (string, models.AccessToken, error)
+	ListTokens(ctx context.Context, userID int64) ([]models.AccessToken, error)
+	RevokeToken(ctx context.Context, tokenID string) error
+	Authenticate(ctx context.Context, secret string) (models.AccessToken, error)
+}
+
+type Sessions interface {
+	ListSessions(ctx context.Context) ([]models.Session, error)
+	RevokeSession(ctx context.Context, sessionID string) error
+	RevokeAllOtherSessions(ctx context.Context) error
+}
+
+type LoginHistory interface {
+	GetLoginHistory(ctx context.Context, limit int64) ([]models.LoginAttempt, error)
+	GetUserLoginHistory(ctx context.Context, userID int64, limit int64) ([]models.LoginAttempt, error)
+}
+
+type Export interface {
+	ExportMyData(ctx context.Context, send func(chunk []byte) error) error
+	ExportUserData(ctx context.Context, userID int64, send func(chunk []byte) error) error
+}
+
 type Family interface {
 	DeleteUserFromFamilies(ctx context.Context, userID int64, familyIDs []int64) error
 	DeleteUserInvites(ctx context.Context, userID int64) error
@@ -0,0 +1,96 @@
+// Package servicetest provides the fakes shared by the unit tests of the services:
+// an in-memory repository of users, sessions and audit events, a discarding logger
+// and a JWT manager. Tests embed the Repository to fake the other methods they need.
+package servicetest
+
+import (
+	"context"
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/jwt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/repository"
+	"io"
+	"log/slog"
+	"time"
+)
+
+const (
+	SigningKey = "secret"
+	Issuer     = "sso"
+)
+
+// Repository keeps users, sessions and audit events in memory. Methods it does
+// not implement panic through the embedded nil repository.
+type Repository struct {
+	repository.Repository
+	Users          map[int64]models.User
+	Sessions       map[string]models.Session
+	Revoked        []string
+	Events         []models.AuditEvent
+	AuditErr       error
+	SessionLookups int
+}
+
+// NewRepository creates and returns a new instance of the Repository holding the
+// provided users.
+func NewRepository(users ...models.User) *Repository {
+	r := &Repository{
+		Users:    make(map[int64]models.User, len(users)),
+		Sessions: make(map[string]models.Session),
+	}
+
+	for _, user := range users {
+		r.Users[user.ID] = user
+	}
+
+	return r
+}
+
+func (r *Repository) GetUserInfo(_ context.Context, userID int64) (models.User, error) {
+	user, ok := r.Users[userID]
+	if !ok {
+		return models.User{}, grpcerror.ErrUserNotFound
+	}
+	return user, nil
+}
+
+// CreateSession stores the session with the ID "session-N", N being the number
+// of stored sessions.
+func (r *Repository) CreateSession(_ context.Context, session *models.Session) (string, error) {
+	session.ID = fmt.Sprintf("session-%d", len(r.Sessions)+1)
+	r.Sessions[session.ID] = *session
+	return session.ID, nil
+}
+
+func (r *Repository) GetSession(_ context.Context, sessionID string) (models.Session, error) {
+	r.SessionLookups++
+	session, ok := r.Sessions[sessionID]
+	if !ok {
+		return models.Session{}, grpcerror.ErrSessionNotFound
+	}
+	return session, nil
+}
+
+func (r *Repository) RevokeSession(_ context.Context, _ int64, sessionID string) error {
+	r.Revoked = append(r.Revoked, sessionID)
+	return nil
+}
+
+func (r *Repository) RecordAuditEvent(_ context.Context, event *models.AuditEvent) error {
+	if r.AuditErr != nil {
+		return r.AuditErr
+	}
+	r.Events = append(r.Events, *event)
+	return nil
+}
+
+// Logger returns a logger discarding every record.
+func Logger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// Manager returns a JWT manager issuing tokens for an hour.
+func Manager() *jwt.Manager {
+	return jwt.New([]byte(SigningKey), time.Hour, Issuer)
+}
@@ -0,0 +1,98 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/jwt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/repository"
+	"log/slog"
+)
+
+type SessionService struct {
+	log     *slog.Logger
+	repo    repository.SessionRepository
+	manager *jwt.Manager
+}
+
+// New creates and returns a new instance of the SessionService
+func New(
+	log *slog.Logger,
+	repo repository.SessionRepository,
+	manager *jwt.Manager,
+) *SessionService {
+	return &SessionService{
+		log:     log,
+		repo:    repo,
+		manager: manager,
+	}
+}
+
+// ListSessions retrieves the active sessions of the authenticated user making the request.
+func (s *SessionService) ListSessions(ctx context.Context) ([]models.Session, error) {
+	const op = "session.service.ListSessions"
+
+	userID, err := s.manager.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.repo.GetSessions(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession revokes the session with the provided ID if it belongs to the
+// authenticated user making the request.
+func (s *SessionService) RevokeSession(ctx context.Context, sessionID string) error {
+	const op = "session.service.RevokeSession"
+
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	userID, err := s.manager.GetUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = s.repo.RevokeSession(ctx, userID, sessionID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("session revoked",
+		slog.Int64("user_id", userID), slog.String("session_id", sessionID))
+
+	return nil
+}
+
+// RevokeAllOtherSessions revokes every session of the authenticated user making
+// the request except for the one the request was made with.
+func (s *SessionService) RevokeAllOtherSessions(ctx context.Context) error {
+	const op = "session.service.RevokeAllOtherSessions"
+
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	userID, err := s.manager.GetUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	sessionID, err := s.manager.GetSessionIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = s.repo.RevokeAllOtherSessions(ctx, userID, sessionID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("all other sessions revoked", slog.Int64("user_id", userID))
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package userinfo
+
+import (
+	"fmt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/phone"
+)
+
+// nonEmptyFields returns the profile fields of the user that have a value. It keeps
+// the behaviour of updates without a field mask, where empty values mean "keep".
+func nonEmptyFields(user *models.User) []string {
+	values := []struct {
+		field string
+		value string
+	}{
+		{models.FieldEmail, user.Email},
+		{models.FieldPhoneNumber, user.PhoneNumber},
+		{models.FieldName, user.Name},
+		{models.FieldSurname, user.Surname},
+	}
+
+	fields := make([]string, 0, len(values))
+	for _, v := range values {
+		if v.value != "" {
+			fields = append(fields, v.field)
+		}
+	}
+
+	return fields
+}
+
+// validateUpdate checks that the field mask contains only known and distinct
+// profile fields other than the email and formats the updated phone number in
+// E.164. The phone number can be cleared.
+func validateUpdate(user *models.User, fields []string, phones *phone.Normalizer) error {
+	seen := make(map[string]bool, len(fields))
+
+	for _, field := range fields {
+		if seen[field] {
+			return fmt.Errorf("%w: duplicate path %s", grpcerror.ErrInvalidFieldMask, field)
+		}
+		seen[field] = true
+
+		switch field {
+		case models.FieldEmail:
+			return grpcerror.ErrEmailChangeRequired
+		case models.FieldPhoneNumber:
+			if user.PhoneNumber == "" {
+				user.PhoneNormalized = ""
+				continue
+			}
+			phoneNumber, err := phones.Normalize(user.PhoneNumber)
+			if err != nil {
+				return err
+			}
+			user.PhoneNumber = phoneNumber
+			user.PhoneNormalized = phoneNumber
+		case models.FieldName, models.FieldSurname:
+		default:
+			return fmt.Errorf("%w: unknown path %s", grpcerror.ErrInvalidFieldMask, field)
+		}
+	}
+
+	return nil
+}
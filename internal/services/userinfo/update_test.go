@@ -0,0 +1,79 @@
+package userinfo
+
+import (
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/phone"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestNonEmptyFields(t *testing.T) {
+	fields := nonEmptyFields(&models.User{Email: "john@gmail.com", Surname: "Doe"})
+	require.Equal(t, []string{models.FieldEmail, models.FieldSurname}, fields)
+
+	require.Empty(t, nonEmptyFields(&models.User{}))
+}
+
+func TestValidateUpdate(t *testing.T) {
+	tests := []struct {
+		name   string
+		user   models.User
+		fields []string
+		err    error
+	}{
+		{
+			name:   "clear name and phone",
+			user:   models.User{},
+			fields: []string{models.FieldName, models.FieldPhoneNumber},
+		},
+		{
+			name:   "valid phone",
+			user:   models.User{PhoneNumber: "+375 (29) 123-45-67"},
+			fields: []string{models.FieldPhoneNumber},
+		},
+		{
+			name:   "email",
+			user:   models.User{Email: "john@gmail.com"},
+			fields: []string{models.FieldEmail},
+			err:    grpcerror.ErrEmailChangeRequired,
+		},
+		{
+			name:   "invalid phone",
+			user:   models.User{PhoneNumber: "call me"},
+			fields: []string{models.FieldPhoneNumber},
+			err:    grpcerror.ErrInvalidPhone,
+		},
+		{
+			name:   "unknown path",
+			user:   models.User{},
+			fields: []string{"role"},
+			err:    grpcerror.ErrInvalidFieldMask,
+		},
+		{
+			name:   "duplicate path",
+			user:   models.User{Name: "John"},
+			fields: []string{models.FieldName, models.FieldName},
+			err:    grpcerror.ErrInvalidFieldMask,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateUpdate(&tt.user, tt.fields, phone.NewNormalizer("BY"))
+			if tt.err == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestValidateUpdate_FormatsPhone(t *testing.T) {
+	user := models.User{PhoneNumber: "8 029 123 45 67"}
+
+	require.NoError(t, validateUpdate(&user, []string{models.FieldPhoneNumber}, phone.NewNormalizer("BY")))
+	require.Equal(t, "+375291234567", user.PhoneNumber)
+	require.Equal(t, "+375291234567", user.PhoneNormalized)
+}
@@ -2,35 +2,55 @@ package userinfo
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
 	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/hasher"
 	jwtmanager "github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/jwt"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/password"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/pepper"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/phone"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/lib/sl"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/repository"
-	"golang.org/x/crypto/bcrypt"
 	"log/slog"
+	"time"
 )
 
 type UserInfoService struct {
-	log      *slog.Logger
-	repo     repository.UserInfoRepository
-	manager  *jwtmanager.Manager
-	hashSalt string
+	log     *slog.Logger
+	repo    repository.UserInfoRepository
+	tokens  repository.AccessTokenRepository
+	manager *jwtmanager.Manager
+	hasher  hasher.PasswordHasher
+	peppers *pepper.Peppers
+	policy  *password.Policy
+	phones  *phone.Normalizer
+	grace   time.Duration
 }
 
 // New creates and returns a new instance of the UserInfoService
 func New(
 	log *slog.Logger,
 	repo repository.UserInfoRepository,
+	tokens repository.AccessTokenRepository,
 	manager *jwtmanager.Manager,
-	hashSalt string,
+	passwordHasher hasher.PasswordHasher,
+	peppers *pepper.Peppers,
+	policy *password.Policy,
+	phones *phone.Normalizer,
+	restoreGracePeriod time.Duration,
 ) *UserInfoService {
 	return &UserInfoService{
-		log:      log,
-		repo:     repo,
-		manager:  manager,
-		hashSalt: hashSalt,
+		log:     log,
+		repo:    repo,
+		tokens:  tokens,
+		manager: manager,
+		hasher:  passwordHasher,
+		peppers: peppers,
+		policy:  policy,
+		phones:  phones,
+		grace:   restoreGracePeriod,
 	}
 }
 
@@ -51,23 +71,41 @@ func (s *UserInfoService) GetUserInfoByID(ctx context.Context, userID int64) (mo
 }
 
 // UpdateUserInfo updates user information for the authenticated user making the request.
-// It extracts the user ID from the context, then delegates the update operation to the
-// UpdateUserInfo method of the underlying repository.
+// Only the fields listed in fields are updated, so a listed field with an empty value
+// is cleared. If fields is nil, every non-empty field of updatedUser is updated. If
+// expectedVersion is provided, ErrVersionConflict is returned when the user was
+// modified since that version was read. The email cannot be updated this way, it is
+// changed with a confirmation by the EmailChange service. It extracts the user ID
+// from the context, validates the updated fields, then delegates the update
+// operation to the UpdateUserInfo method of the underlying repository.
 func (s *UserInfoService) UpdateUserInfo(
 	ctx context.Context,
-	updatedUser *models.User) error {
+	updatedUser *models.User,
+	fields []string,
+	expectedVersion *int64) error {
 	userID, err := s.manager.GetUserIDFromContext(ctx)
 	if err != nil {
 		return err
 	}
 
-	return s.repo.UpdateUserInfo(ctx, userID, updatedUser)
+	if fields == nil {
+		fields = nonEmptyFields(updatedUser)
+	}
+
+	if err = validateUpdate(updatedUser, fields, s.phones); err != nil {
+		return err
+	}
+
+	return s.repo.UpdateUserInfo(ctx, userID, updatedUser, fields, expectedVersion)
 }
 
 // ChangePassword updates the password for the authenticated user making the request.
-// It extracts the user ID from the context, combines the old and new passwords with
-// the salt, generates a new password hash, and then delegates the password change
-// operation to the ChangePassword method of the underlying repository.
+// It extracts the user ID from the context, validates the new password against the
+// password policy, verifies the old password against the stored hash and its pepper
+// version, rejects recently used passwords, generates a new password hash with the
+// current pepper, revokes the personal access tokens of the user, and then
+// delegates the password change operation to the ChangePassword method of the
+// underlying repository.
 func (s *UserInfoService) ChangePassword(
 	ctx context.Context,
 	oldPassword, newPassword string) error {
@@ -77,71 +115,213 @@ func (s *UserInfoService) ChangePassword(
 		slog.String("op", op),
 	)
 
-	newPasswordSalted := newPassword + s.hashSalt
-	oldPasswordSalted := oldPassword + s.hashSalt
+	userID, err := s.manager.GetUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
 
-	passHash, err := bcrypt.GenerateFromPassword([]byte(newPasswordSalted), bcrypt.DefaultCost)
+	user, err := s.repo.GetUserInfo(ctx, userID)
 	if err != nil {
-		log.Error("failed to generate password hash", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	userID, err := s.manager.GetUserIDFromContext(ctx)
+	if err = s.policy.Validate("new_password", newPassword, &user); err != nil {
+		return err
+	}
+
+	oldPassHash, pepperVersion, err := s.repo.GetPassHash(ctx, userID)
 	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	current := models.PasswordHash{Hash: oldPassHash, PepperVersion: pepperVersion}
+
+	ok, err := s.matchesHash(current, oldPassword)
+	if err != nil {
+		log.Error("failed to verify password", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !ok {
+		log.Info(grpcerror.ErrInvalidPassword.Error(), slog.Int64("user_id", userID))
+		return grpcerror.ErrInvalidPassword
+	}
+
+	if err = s.checkReuse(ctx, userID, current, newPassword); err != nil {
 		return err
 	}
 
-	return s.repo.ChangePassword(ctx, userID, oldPasswordSalted, string(passHash))
+	passHash, err := s.hasher.Hash(s.peppers.ApplyCurrent(newPassword))
+	if err != nil {
+		log.Error("failed to generate password hash", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = s.tokens.RevokeAllAccessTokens(ctx, userID); err != nil {
+		log.Error("failed to revoke access tokens", slog.Int64("user_id", userID), sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return s.repo.ChangePassword(ctx, userID, passHash, s.peppers.Current(), s.policy.HistorySize())
 }
 
+// checkReuse returns a policy error if the new password matches the current
+// password or one of the previous passwords kept in the history.
+func (s *UserInfoService) checkReuse(
+	ctx context.Context,
+	userID int64,
+	current models.PasswordHash,
+	newPassword string) error {
+	const op = "userinfo.service.checkReuse"
+
+	history, err := s.repo.GetPasswordHistory(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(history) > s.policy.HistorySize() {
+		history = history[:s.policy.HistorySize()]
+	}
+
+	for _, entry := range append([]models.PasswordHash{current}, history...) {
+		used, err := s.matchesHash(entry, newPassword)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if used {
+			return password.ReuseError("new_password")
+		}
+	}
+
+	return nil
+}
+
+// matchesHash reports whether the password matches the hash created with the
+// recorded pepper version.
+func (s *UserInfoService) matchesHash(entry models.PasswordHash, plain string) (bool, error) {
+	salted, err := s.peppers.Apply(plain, entry.PepperVersion)
+	if err != nil {
+		return false, err
+	}
+
+	return s.hasher.Verify(entry.Hash, salted)
+}
+
+// DeleteUser soft-deletes the user with the provided user ID. The user can be restored
+// with RestoreUser until the grace period is over and is purged afterwards.
 func (s *UserInfoService) DeleteUser(ctx context.Context, userID int64) error {
 	return s.repo.DeleteUser(ctx, userID)
 }
 
-func (s *UserInfoService) AddFamily(ctx context.Context, familyID int64, userID int64) error {
-	const op = "userinfo.service.AddFamily"
+// RestoreUser restores the soft-deleted user with the provided user ID if it was
+// deleted within the grace period.
+func (s *UserInfoService) RestoreUser(ctx context.Context, userID int64) error {
+	const op = "userinfo.service.RestoreUser"
 
 	log := s.log.With(
 		slog.String("op", op),
 	)
 
-	user, err := s.repo.GetUserInfo(ctx, userID)
+	err := s.repo.RestoreUser(ctx, userID, time.Now().Add(-s.grace))
+	if errors.Is(err, grpcerror.ErrUserNotFound) ||
+		errors.Is(err, grpcerror.ErrUserNotDeleted) ||
+		errors.Is(err, grpcerror.ErrRestoreExpired) {
+		log.Info("failed to restore user", sl.Err(err), slog.Int64("user_id", userID))
+		return err
+	}
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	if isUserInFamily(user.FamilyIDs, familyID) {
-		log.Warn(grpcerror.ErrUserInFamily.Error())
-		return grpcerror.ErrUserInFamily
+	log.Info("user restored", slog.Int64("user_id", userID))
+
+	return nil
+}
+
+// SetUserStatus changes the account status of the user with the provided user ID.
+// A suspension must expire in the future, while disabling is indefinite; the
+// reason is kept for the admins and is not shown to the user. Suspending or
+// disabling the user revokes its personal access tokens.
+func (s *UserInfoService) SetUserStatus(
+	ctx context.Context,
+	userID int64,
+	status models.AccountStatus,
+	reason string,
+	until *time.Time) error {
+	const op = "userinfo.service.SetUserStatus"
+
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	switch status {
+	case models.StatusActive:
+		reason, until = "", nil
+	case models.StatusSuspended:
+		if until == nil || !until.After(time.Now()) {
+			return fmt.Errorf("%w: suspension must expire in the future", grpcerror.ErrInvalidStatus)
+		}
+	case models.StatusDisabled:
+		until = nil
+	default:
+		return fmt.Errorf("%w: %q", grpcerror.ErrInvalidStatus, status)
+	}
+
+	if err := s.repo.SetStatus(ctx, userID, status, reason, until); err != nil {
+		if errors.Is(err, grpcerror.ErrUserNotFound) {
+			return err
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if status != models.StatusActive {
+		if err := s.tokens.RevokeAllAccessTokens(ctx, userID); err != nil {
+			log.Error("failed to revoke access tokens", slog.Int64("user_id", userID), sl.Err(err))
+			return fmt.Errorf("%s: %w", op, err)
+		}
 	}
 
-	return s.repo.AddFamily(ctx, &user, familyID)
+	log.Info("user status changed",
+		slog.Int64("user_id", userID), slog.String("status", string(status)))
+
+	return nil
 }
 
-func (s *UserInfoService) DeleteFamily(ctx context.Context, familyID int64, userID int64) error {
-	const op = "userinfo.service.DeleteFamily"
+// AddFamily adds the family to the family list of the user with the provided user ID.
+func (s *UserInfoService) AddFamily(ctx context.Context, familyID int64, userID int64) error {
+	const op = "userinfo.service.AddFamily"
 
 	log := s.log.With(
 		slog.String("op", op),
 	)
 
-	user, err := s.repo.GetUserInfo(ctx, userID)
+	err := s.repo.AddFamily(ctx, userID, familyID)
+	if errors.Is(err, grpcerror.ErrUserInFamily) {
+		log.Warn(grpcerror.ErrUserInFamily.Error())
+		return grpcerror.ErrUserInFamily
+	}
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	if !isUserInFamily(user.FamilyIDs, familyID) {
+	return nil
+}
+
+// DeleteFamily removes the family from the family list of the user with the provided user ID.
+func (s *UserInfoService) DeleteFamily(ctx context.Context, familyID int64, userID int64) error {
+	const op = "userinfo.service.DeleteFamily"
+
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	err := s.repo.DeleteFamily(ctx, userID, familyID)
+	if errors.Is(err, grpcerror.ErrUserNotInFamily) {
 		log.Warn(grpcerror.ErrUserNotInFamily.Error())
 		return grpcerror.ErrUserNotInFamily
 	}
-
-	return s.repo.DeleteFamily(ctx, &user, familyID)
-}
-
-func isUserInFamily(families []int64, familyID int64) bool {
-	for _, f := range families {
-		if f == familyID {
-			return true
-		}
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
 	}
-	return false
+
+	return nil
 }
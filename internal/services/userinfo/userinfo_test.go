@@ -0,0 +1,45 @@
+package userinfo
+
+import (
+	"context"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/repository"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/services/servicetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type fakeRepo struct {
+	repository.Repository
+	status  models.AccountStatus
+	revoked int
+}
+
+func (r *fakeRepo) SetStatus(_ context.Context, _ int64, status models.AccountStatus, _ string, _ *time.Time) error {
+	r.status = status
+	return nil
+}
+
+func (r *fakeRepo) RevokeAllAccessTokens(_ context.Context, _ int64) error {
+	r.revoked++
+	return nil
+}
+
+func TestSetUserStatus_RevokesAccessTokens(t *testing.T) {
+	repo := &fakeRepo{}
+	s := New(servicetest.Logger(), repo, repo, nil, nil, nil, nil, nil, time.Hour)
+	ctx := context.Background()
+
+	until := time.Now().Add(time.Hour)
+	require.NoError(t, s.SetUserStatus(ctx, 1, models.StatusSuspended, "abuse", &until))
+	assert.Equal(t, 1, repo.revoked)
+
+	require.NoError(t, s.SetUserStatus(ctx, 1, models.StatusActive, "", nil))
+	assert.Equal(t, 1, repo.revoked, "reactivation keeps the tokens revoked")
+
+	require.NoError(t, s.SetUserStatus(ctx, 1, models.StatusDisabled, "", nil))
+	assert.Equal(t, 2, repo.revoked)
+	assert.Equal(t, models.StatusDisabled, repo.status)
+}
@@ -0,0 +1,143 @@
+package tests
+
+import (
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/tests/suite"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAccessToken_HappyPath(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	user := st.SignUpRandomUser(ctx, t)
+	userCtx := st.SignInAndGetContext(user, ctx, t)
+
+	created, err := st.AccessTokensClient.CreateToken(userCtx, &ssov1.CreateTokenRequest{
+		Name:   "backup script",
+		Scopes: []string{"profile:read"},
+		Ttl:    durationpb.New(time.Hour),
+	})
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(created.GetToken(), models.AccessTokenPrefix))
+	assert.Equal(t, user.ID, created.GetInfo().GetUserId())
+	assert.Equal(t, []string{"profile:read"}, created.GetInfo().GetScopes())
+
+	patCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+created.GetToken())
+
+	info, err := st.UserInfoClient.GetUserInfo(patCtx, &ssov1.GetUserInfoRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, user.Email, info.GetEmail())
+
+	_, err = st.UserInfoClient.UpdateUserInfo(patCtx, &ssov1.UpdateUserInfoRequest{NewName: "Scripted"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err), "the token lacks profile:write")
+
+	_, err = st.AccessTokensClient.CreateToken(patCtx, &ssov1.CreateTokenRequest{Name: "another"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	list, err := st.AccessTokensClient.ListTokens(userCtx, &ssov1.ListTokensRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.GetTokens(), 1)
+	assert.Equal(t, created.GetInfo().GetId(), list.GetTokens()[0].GetId())
+	assert.Equal(t, "backup script", list.GetTokens()[0].GetName())
+
+	revoked, err := st.AccessTokensClient.RevokeToken(userCtx, &ssov1.RevokeTokenRequest{
+		TokenId: created.GetInfo().GetId(),
+	})
+	require.NoError(t, err)
+	require.True(t, revoked.GetSucceed())
+
+	_, err = st.UserInfoClient.GetUserInfo(patCtx, &ssov1.GetUserInfoRequest{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	list, err = st.AccessTokensClient.ListTokens(userCtx, &ssov1.ListTokensRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, list.GetTokens())
+}
+
+func TestAccessToken_Admin(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	admin := models.User{
+		Email:    "admin@gmail.com",
+		PassHash: "123",
+	}
+
+	user := st.SignUpRandomUser(ctx, t)
+	adminCtx := st.SignInAndGetContext(admin, ctx, t)
+
+	created, err := st.AccessTokensClient.CreateToken(adminCtx, &ssov1.CreateTokenRequest{
+		UserId: user.ID,
+		Name:   "service account",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, created.GetInfo().GetUserId())
+	assert.NotEqual(t, user.ID, created.GetInfo().GetCreatedBy())
+
+	userCtx := st.SignInAndGetContext(user, ctx, t)
+
+	list, err := st.AccessTokensClient.ListTokens(userCtx, &ssov1.ListTokensRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.GetTokens(), 1)
+	assert.Equal(t, created.GetInfo().GetId(), list.GetTokens()[0].GetId())
+}
+
+func TestAccessToken_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	user := st.SignUpRandomUser(ctx, t)
+	userCtx := st.SignInAndGetContext(user, ctx, t)
+
+	other := st.SignUpRandomUser(ctx, t)
+	otherCtx := st.SignInAndGetContext(other, ctx, t)
+
+	tests := []struct {
+		name string
+		req  *ssov1.CreateTokenRequest
+		code codes.Code
+	}{
+		{
+			name: "no name",
+			req:  &ssov1.CreateTokenRequest{},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "unknown scope",
+			req:  &ssov1.CreateTokenRequest{Name: "script", Scopes: []string{"everything"}},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "negative ttl",
+			req:  &ssov1.CreateTokenRequest{Name: "script", Ttl: durationpb.New(-time.Hour)},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "other user",
+			req:  &ssov1.CreateTokenRequest{UserId: other.ID, Name: "script"},
+			code: codes.PermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.AccessTokensClient.CreateToken(userCtx, tt.req)
+			require.Equal(t, tt.code, status.Code(err))
+		})
+	}
+
+	created, err := st.AccessTokensClient.CreateToken(userCtx, &ssov1.CreateTokenRequest{Name: "script"})
+	require.NoError(t, err)
+
+	_, err = st.AccessTokensClient.RevokeToken(otherCtx, &ssov1.RevokeTokenRequest{
+		TokenId: created.GetInfo().GetId(),
+	})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
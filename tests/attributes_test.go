@@ -0,0 +1,130 @@
+package tests
+
+import (
+	"context"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/tests/suite"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"strings"
+	"testing"
+)
+
+func TestSetAttributes_HappyPath(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	user := st.SignUpRandomUser(ctx, t)
+	ctx = st.SignInAndGetContext(user, ctx, t)
+
+	setLocale(ctx, t, st, 0, structpb.NewStringValue("en-US"))
+
+	resp, err := st.AttributesClient.GetAttributes(ctx, &ssov1.GetAttributesRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.GetAttributes(), 1)
+	assert.Equal(t, "profile", resp.GetAttributes()[0].GetNamespace())
+	assert.Equal(t, "locale", resp.GetAttributes()[0].GetName())
+	assert.Equal(t, "en-US", resp.GetAttributes()[0].GetValue().GetStringValue())
+
+	setLocale(ctx, t, st, 0, structpb.NewNullValue())
+
+	resp, err = st.AttributesClient.GetAttributes(ctx, &ssov1.GetAttributesRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, resp.GetAttributes())
+}
+
+func TestSetAttributes_Admin(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	admin := models.User{
+		Email:    "admin@gmail.com",
+		PassHash: "123",
+	}
+
+	user := st.SignUpRandomUser(ctx, t)
+
+	adminCtx := st.SignInAndGetContext(admin, ctx, t)
+	setLocale(adminCtx, t, st, user.ID, structpb.NewStringValue("be-BY"))
+
+	resp, err := st.AttributesClient.GetAttributes(adminCtx, &ssov1.GetAttributesRequest{UserId: user.ID})
+	require.NoError(t, err)
+	require.Len(t, resp.GetAttributes(), 1)
+	assert.Equal(t, "be-BY", resp.GetAttributes()[0].GetValue().GetStringValue())
+}
+
+func TestSetAttributes_OtherUser(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	owner := st.SignUpRandomUser(ctx, t)
+	ownerCtx := st.SignInAndGetContext(owner, ctx, t)
+	setLocale(ownerCtx, t, st, 0, structpb.NewStringValue("en-US"))
+
+	other := st.SignUpRandomUser(ctx, t)
+	otherCtx := st.SignInAndGetContext(other, ctx, t)
+
+	resp, err := st.AttributesClient.GetAttributes(otherCtx, &ssov1.GetAttributesRequest{UserId: owner.ID})
+	require.NoError(t, err)
+	assert.Empty(t, resp.GetAttributes())
+
+	_, err = st.AttributesClient.SetAttributes(otherCtx, &ssov1.SetAttributesRequest{
+		UserId: owner.ID,
+		Attributes: []*ssov1.Attribute{
+			{Namespace: "profile", Name: "locale", Value: structpb.NewStringValue("fr-FR")},
+		},
+	})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestSetAttributes_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	user := st.SignUpRandomUser(ctx, t)
+	ctx = st.SignInAndGetContext(user, ctx, t)
+
+	tests := []struct {
+		name string
+		attr *ssov1.Attribute
+	}{
+		{
+			name: "unknown attribute",
+			attr: &ssov1.Attribute{Namespace: "profile", Name: "shoe_size", Value: structpb.NewStringValue("42")},
+		},
+		{
+			name: "wrong type",
+			attr: &ssov1.Attribute{Namespace: "profile", Name: "locale", Value: structpb.NewBoolValue(true)},
+		},
+		{
+			name: "too long",
+			attr: &ssov1.Attribute{Namespace: "profile", Name: "locale", Value: structpb.NewStringValue(strings.Repeat("a", 36))},
+		},
+		{
+			name: "no value",
+			attr: &ssov1.Attribute{Namespace: "profile", Name: "locale"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.AttributesClient.SetAttributes(ctx, &ssov1.SetAttributesRequest{
+				Attributes: []*ssov1.Attribute{tt.attr},
+			})
+			require.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+}
+
+func setLocale(ctx context.Context, t *testing.T, st *suite.Suite, userID int64, value *structpb.Value) {
+	t.Helper()
+
+	resp, err := st.AttributesClient.SetAttributes(ctx, &ssov1.SetAttributesRequest{
+		UserId: userID,
+		Attributes: []*ssov1.Attribute{
+			{Namespace: "profile", Name: "locale", Value: value},
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, resp.GetSucceed())
+}
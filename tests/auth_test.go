@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"encoding/json"
 	"fmt"
 	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/tests/suite"
@@ -9,6 +10,7 @@ import (
 	"github.com/golang-jwt/jwt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"strings"
 	"testing"
 	"time"
 )
@@ -26,7 +28,9 @@ func TestSignInSignUp_HappyPath(t *testing.T) {
 
 	fmt.Println(st.Cfg.SigningKey)
 
-	parsedToken, err := jwt.Parse(token, func(tkn *jwt.Token) (interface{}, error) {
+	parser := jwt.Parser{UseJSONNumber: true}
+
+	parsedToken, err := parser.Parse(token, func(tkn *jwt.Token) (interface{}, error) {
 		if _, ok := tkn.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", tkn.Header["alg"]) //nolint
 		}
@@ -37,12 +41,19 @@ func TestSignInSignUp_HappyPath(t *testing.T) {
 	claims, ok := parsedToken.Claims.(jwt.MapClaims)
 	require.True(t, ok)
 
-	assert.Equal(t, user.ID, int64(claims["user_id"].(float64)))
+	userID, err := claims["user_id"].(json.Number).Int64()
+	require.NoError(t, err)
+
+	assert.Equal(t, user.ID, userID)
 	assert.Equal(t, user.Email, claims["email"].(string))
+	assert.NotEmpty(t, claims["session_id"])
 
 	const deltaSeconds = 1
 
-	assert.InDelta(t, loginTime.Add(st.Cfg.TokenTTL).Unix(), claims["exp"].(float64), deltaSeconds)
+	exp, err := claims["exp"].(json.Number).Int64()
+	require.NoError(t, err)
+
+	assert.InDelta(t, loginTime.Add(st.Cfg.TokenTTL).Unix(), exp, deltaSeconds)
 }
 
 func TestSignUp_DuplicatedSignUp(t *testing.T) {
@@ -56,6 +67,39 @@ func TestSignUp_DuplicatedSignUp(t *testing.T) {
 	assert.ErrorContains(t, err, grpcerror.ErrUserExists.Error())
 }
 
+func TestSignUp_DuplicatedSignUpDifferentCase(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	user := st.SignUpRandomUser(ctx, t)
+
+	duplicate := user
+	duplicate.Email = "  " + strings.ToUpper(user.Email) + " "
+
+	respSignUp, err := st.AuthClient.SignUp(ctx, suite.SignUpRequestFromUser(duplicate))
+	require.Error(t, err)
+	assert.Empty(t, respSignUp)
+	assert.ErrorContains(t, err, grpcerror.ErrUserExists.Error())
+
+	respSignIn, err := st.AuthClient.SignIn(ctx, &ssov1.SignInRequest{
+		Email:    duplicate.Email,
+		Password: user.PassHash,
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, respSignIn.GetToken())
+}
+
+func TestSignUp_WeakPassword(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	user := suite.CreateRandomUser()
+	user.PassHash = "password"
+
+	respSignUp, err := st.AuthClient.SignUp(ctx, suite.SignUpRequestFromUser(user))
+	require.Error(t, err)
+	assert.Empty(t, respSignUp)
+	assert.ErrorContains(t, err, grpcerror.ErrWeakPassword.Error())
+}
+
 func TestSignUp_FailCases(t *testing.T) {
 	ctx, st := suite.New(t)
 	table := []struct {
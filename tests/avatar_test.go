@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/tests/suite"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestUploadAvatar_HappyPath(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	user := st.SignUpRandomUser(ctx, t)
+	ctx = st.SignInAndGetContext(user, ctx, t)
+
+	_, err := st.AvatarsClient.GetAvatar(ctx, &ssov1.GetAvatarRequest{})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	require.NoError(t, uploadAvatar(ctx, st, gradientPNG(t, 300, 200)))
+
+	resp, err := st.AvatarsClient.GetAvatar(ctx, &ssov1.GetAvatarRequest{Size: 100})
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", resp.GetContentType())
+	assert.Equal(t, int32(128), resp.GetSize())
+
+	img, err := png.Decode(bytes.NewReader(resp.GetData()))
+	require.NoError(t, err)
+	assert.Equal(t, 128, img.Bounds().Dx())
+	assert.Equal(t, 128, img.Bounds().Dy())
+
+	other := st.SignUpRandomUser(ctx, t)
+	otherCtx := st.SignInAndGetContext(other, ctx, t)
+
+	resp, err = st.AvatarsClient.GetAvatar(otherCtx, &ssov1.GetAvatarRequest{UserId: user.ID})
+	require.NoError(t, err)
+	assert.Equal(t, int32(256), resp.GetSize())
+
+	delResp, err := st.AvatarsClient.DeleteAvatar(ctx, &ssov1.DeleteAvatarRequest{})
+	require.NoError(t, err)
+	require.True(t, delResp.GetSucceed())
+
+	_, err = st.AvatarsClient.GetAvatar(ctx, &ssov1.GetAvatarRequest{})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestUploadAvatar_NotAnImage(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	user := st.SignUpRandomUser(ctx, t)
+	ctx = st.SignInAndGetContext(user, ctx, t)
+
+	err := uploadAvatar(ctx, st, []byte("definitely not an image"))
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// uploadAvatar streams the image to UploadAvatar in small chunks.
+func uploadAvatar(ctx context.Context, st *suite.Suite, data []byte) error {
+	const chunkSize = 1024
+
+	stream, err := st.AvatarsClient.UploadAvatar(ctx)
+	if err != nil {
+		return err
+	}
+
+	for len(data) > 0 {
+		n := min(chunkSize, len(data))
+		if err = stream.Send(&ssov1.AvatarChunk{Data: data[:n]}); err != nil {
+			break
+		}
+		data = data[n:]
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+	if !resp.GetSucceed() {
+		return status.Error(codes.Unknown, "avatar upload did not succeed")
+	}
+
+	return nil
+}
+
+func gradientPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: uint8(x + y), A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+
+	return buf.Bytes()
+}
@@ -12,7 +12,7 @@ func TestChangePassword_HappyPath(t *testing.T) {
 
 	user := st.SignUpRandomUser(ctx, t)
 
-	newPass := suite.RandomFakePassword()
+	newPass := suite.RandomPolicyPassword()
 
 	ctx = st.SignInAndGetContext(user, ctx, t)
 
@@ -30,3 +30,44 @@ func TestChangePassword_HappyPath(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEmpty(t, respSignIn.GetToken())
 }
+
+func TestChangePassword_PolicyViolations(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	user := st.SignUpRandomUser(ctx, t)
+
+	ctx = st.SignInAndGetContext(user, ctx, t)
+
+	table := []struct {
+		name        string
+		newPassword string
+		expectedErr string
+	}{
+		{
+			name:        "Too short password",
+			newPassword: "aB1",
+			expectedErr: "password must be at least",
+		},
+		{
+			name:        "Password without digits",
+			newPassword: "abcdefGHIJKL",
+			expectedErr: "password must contain a digit",
+		},
+		{
+			name:        "Reuse of the current password",
+			newPassword: user.PassHash,
+			expectedErr: "password was used recently",
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.UserInfoClient.ChangePassword(ctx, &ssov1.ChangePasswordRequest{
+				OldPassword: user.PassHash,
+				NewPassword: tt.newPassword,
+			})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package tests
 
 import (
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/tests/suite"
 	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
 	"github.com/stretchr/testify/require"
@@ -26,3 +27,38 @@ func TestDeleteUser_HappyPath(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, resp.GetSucceed())
 }
+
+func TestDeleteUser_DeletedUserIsHidden(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	admin := models.User{
+		Email:    "admin@gmail.com",
+		PassHash: "123",
+	}
+
+	user := st.SignUpRandomUser(ctx, t)
+
+	adminCtx := st.SignInAndGetContext(admin, ctx, t)
+
+	resp, err := st.UserInfoClient.DeleteUser(adminCtx, &ssov1.DeleteUserRequest{
+		UserId: user.ID,
+	})
+	require.NoError(t, err)
+	require.True(t, resp.GetSucceed())
+
+	_, err = st.AuthClient.SignIn(ctx, &ssov1.SignInRequest{
+		Email:    user.Email,
+		Password: user.PassHash,
+	})
+	require.Error(t, err)
+
+	_, err = st.UserInfoClient.GetUserInfoByID(adminCtx, &ssov1.GetUserInfoByIDRequest{
+		UserId: user.ID,
+	})
+	require.ErrorContains(t, err, grpcerror.ErrUserNotFound.Error())
+
+	_, err = st.UserInfoClient.DeleteUser(adminCtx, &ssov1.DeleteUserRequest{
+		UserId: user.ID,
+	})
+	require.ErrorContains(t, err, grpcerror.ErrUserNotFound.Error())
+}
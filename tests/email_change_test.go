@@ -0,0 +1,48 @@
+package tests
+
+import (
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/tests/suite"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestEmailChange_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	user := st.SignUpRandomUser(ctx, t)
+
+	userCtx := st.SignInAndGetContext(user, ctx, t)
+
+	_, err := st.UserInfoClient.UpdateUserInfo(userCtx, &ssov1.UpdateUserInfoRequest{
+		NewEmail: suite.CreateRandomUser().Email,
+	})
+	require.NoError(t, err)
+
+	t.Run("Confirm with unknown token", func(t *testing.T) {
+		_, err := st.EmailChangeClient.ConfirmEmailChange(ctx, &ssov1.ConfirmEmailChangeRequest{
+			Token: "unknown",
+		})
+		require.Equal(t, codes.NotFound, status.Code(err))
+		require.ErrorContains(t, err, grpcerror.ErrEmailChangeNotFound.Error())
+	})
+
+	t.Run("Cancel with unknown token", func(t *testing.T) {
+		_, err := st.EmailChangeClient.CancelEmailChange(ctx, &ssov1.CancelEmailChangeRequest{
+			Token: "unknown",
+		})
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("Confirm without token", func(t *testing.T) {
+		_, err := st.EmailChangeClient.ConfirmEmailChange(ctx, &ssov1.ConfirmEmailChangeRequest{})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	respInfo, err := st.UserInfoClient.GetUserInfo(userCtx, &ssov1.GetUserInfoRequest{})
+	require.NoError(t, err)
+	require.Equal(t, user.Email, respInfo.GetEmail())
+}
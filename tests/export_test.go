@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/tests/suite"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"testing"
+)
+
+type exportArchive struct {
+	FormatVersion int `json:"format_version"`
+	Profile       struct {
+		UserID int64  `json:"user_id"`
+		Email  string `json:"email"`
+		Name   string `json:"name"`
+	} `json:"profile"`
+	Sessions     []json.RawMessage `json:"sessions"`
+	LoginHistory []json.RawMessage `json:"login_history"`
+}
+
+func TestExportMyData_HappyPath(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	user := st.SignUpRandomUser(ctx, t)
+
+	ctx = st.SignInAndGetContext(user, ctx, t)
+
+	stream, err := st.ExportClient.ExportMyData(ctx, &ssov1.ExportMyDataRequest{})
+	require.NoError(t, err)
+
+	archive := readExportArchive(t, stream)
+	assert.Equal(t, 1, archive.FormatVersion)
+	assert.Equal(t, user.ID, archive.Profile.UserID)
+	assert.Equal(t, user.Email, archive.Profile.Email)
+	assert.Equal(t, user.Name, archive.Profile.Name)
+	assert.NotEmpty(t, archive.Sessions)
+	assert.NotEmpty(t, archive.LoginHistory)
+}
+
+func TestExportUserData_HappyPath(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	admin := models.User{
+		Email:    "admin@gmail.com",
+		PassHash: "123",
+	}
+
+	user := st.SignUpRandomUser(ctx, t)
+
+	ctx = st.SignInAndGetContext(admin, ctx, t)
+
+	stream, err := st.ExportClient.ExportUserData(ctx, &ssov1.ExportUserDataRequest{
+		UserId: user.ID,
+	})
+	require.NoError(t, err)
+
+	archive := readExportArchive(t, stream)
+	assert.Equal(t, user.ID, archive.Profile.UserID)
+	assert.Equal(t, user.Email, archive.Profile.Email)
+}
+
+func TestExportUserData_NotAdmin(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	user := st.SignUpRandomUser(ctx, t)
+
+	ctx = st.SignInAndGetContext(user, ctx, t)
+
+	stream, err := st.ExportClient.ExportUserData(ctx, &ssov1.ExportUserDataRequest{
+		UserId: user.ID,
+	})
+	require.NoError(t, err)
+
+	_, err = stream.Recv()
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func readExportArchive(t *testing.T, stream grpc.ClientStream) exportArchive {
+	t.Helper()
+
+	var data []byte
+	for {
+		chunk := &ssov1.ExportChunk{}
+		err := stream.RecvMsg(chunk)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		data = append(data, chunk.GetData()...)
+	}
+
+	var archive exportArchive
+	require.NoError(t, json.Unmarshal(data, &archive))
+
+	return archive
+}
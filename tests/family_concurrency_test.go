@@ -0,0 +1,151 @@
+package tests
+
+import (
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/tests/suite"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+const concurrentRequests = 10
+
+func TestAddFamily_ConcurrentDistinctFamilies(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	admin := models.User{
+		Email:    "admin@gmail.com",
+		PassHash: "123",
+	}
+
+	user := st.SignUpRandomUser(ctx, t)
+
+	ctx = st.SignInAndGetContext(admin, ctx, t)
+
+	famIDs := make([]int64, concurrentRequests)
+	for i := range famIDs {
+		famIDs[i] = rand.Int63() + 1
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(famIDs))
+
+	for _, famID := range famIDs {
+		wg.Add(1)
+		go func(famID int64) {
+			defer wg.Done()
+			_, err := st.UserInfoClient.AddFamily(ctx, &ssov1.AddFamilyRequest{
+				UserId:   user.ID,
+				FamilyId: famID,
+			})
+			errs <- err
+		}(famID)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	// every family must still be present: a lost update would make its removal fail
+	for _, famID := range famIDs {
+		respDelete, err := st.UserInfoClient.DeleteFamily(ctx, &ssov1.DeleteFamilyRequest{
+			UserId:   user.ID,
+			FamilyId: famID,
+		})
+		require.NoError(t, err)
+		require.True(t, respDelete.GetSucceed())
+	}
+}
+
+func TestAddFamily_ConcurrentSameFamily(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	admin := models.User{
+		Email:    "admin@gmail.com",
+		PassHash: "123",
+	}
+
+	user := st.SignUpRandomUser(ctx, t)
+
+	ctx = st.SignInAndGetContext(admin, ctx, t)
+
+	famID := rand.Int63() + 1
+
+	var (
+		wg        sync.WaitGroup
+		succeeded atomic.Int32
+	)
+
+	for i := 0; i < concurrentRequests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := st.UserInfoClient.AddFamily(ctx, &ssov1.AddFamilyRequest{
+				UserId:   user.ID,
+				FamilyId: famID,
+			})
+			if err == nil {
+				succeeded.Add(1)
+				return
+			}
+			require.ErrorContains(t, err, grpcerror.ErrUserInFamily.Error())
+		}()
+	}
+
+	wg.Wait()
+
+	require.Equal(t, int32(1), succeeded.Load())
+}
+
+func TestDeleteFamily_ConcurrentSameFamily(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	admin := models.User{
+		Email:    "admin@gmail.com",
+		PassHash: "123",
+	}
+
+	user := st.SignUpRandomUser(ctx, t)
+
+	ctx = st.SignInAndGetContext(admin, ctx, t)
+
+	famID := rand.Int63() + 1
+
+	_, err := st.UserInfoClient.AddFamily(ctx, &ssov1.AddFamilyRequest{
+		UserId:   user.ID,
+		FamilyId: famID,
+	})
+	require.NoError(t, err)
+
+	var (
+		wg        sync.WaitGroup
+		succeeded atomic.Int32
+	)
+
+	for i := 0; i < concurrentRequests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := st.UserInfoClient.DeleteFamily(ctx, &ssov1.DeleteFamilyRequest{
+				UserId:   user.ID,
+				FamilyId: famID,
+			})
+			if err == nil {
+				succeeded.Add(1)
+				return
+			}
+			require.ErrorContains(t, err, grpcerror.ErrUserNotInFamily.Error())
+		}()
+	}
+
+	wg.Wait()
+
+	require.Equal(t, int32(1), succeeded.Load())
+}
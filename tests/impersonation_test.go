@@ -0,0 +1,112 @@
+package tests
+
+import (
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/tests/suite"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestImpersonate_HappyPath(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	admin := models.User{
+		Email:    "admin@gmail.com",
+		PassHash: "123",
+	}
+
+	user := st.SignUpRandomUser(ctx, t)
+
+	adminCtx := st.SignInAndGetContext(admin, ctx, t)
+
+	resp, err := st.ImpersonationClient.Impersonate(adminCtx, &ssov1.ImpersonateRequest{
+		UserId: user.ID,
+		Reason: "support ticket",
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.GetToken())
+
+	impersonatedCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+resp.GetToken())
+
+	info, err := st.UserInfoClient.GetUserInfo(impersonatedCtx, &ssov1.GetUserInfoRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, user.Email, info.GetEmail())
+
+	_, err = st.UserInfoClient.ChangePassword(impersonatedCtx, &ssov1.ChangePasswordRequest{
+		OldPassword: user.PassHash,
+		NewPassword: suite.RandomPolicyPassword(),
+	})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	require.ErrorContains(t, err, grpcerror.ErrImpersonationDenied.Error())
+
+	_, err = st.ImpersonationClient.Impersonate(impersonatedCtx, &ssov1.ImpersonateRequest{
+		UserId: user.ID,
+		Reason: "support ticket",
+	})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// The password is unchanged.
+	st.SignInAndGetToken(user, ctx, t)
+}
+
+func TestImpersonate_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	admin := models.User{
+		Email:    "admin@gmail.com",
+		PassHash: "123",
+	}
+
+	user := st.SignUpRandomUser(ctx, t)
+	other := st.SignUpRandomUser(ctx, t)
+
+	adminCtx := st.SignInAndGetContext(admin, ctx, t)
+	userCtx := st.SignInAndGetContext(user, ctx, t)
+
+	tests := []struct {
+		name   string
+		asUser bool
+		req    *ssov1.ImpersonateRequest
+		code   codes.Code
+	}{
+		{
+			name:   "not an admin",
+			asUser: true,
+			req:    &ssov1.ImpersonateRequest{UserId: other.ID, Reason: "curiosity"},
+			code:   codes.PermissionDenied,
+		},
+		{
+			name: "no reason",
+			req:  &ssov1.ImpersonateRequest{UserId: other.ID},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "no user",
+			req:  &ssov1.ImpersonateRequest{Reason: "support ticket"},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "unknown user",
+			req:  &ssov1.ImpersonateRequest{UserId: 1 << 62, Reason: "support ticket"},
+			code: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			callCtx := adminCtx
+			if tt.asUser {
+				callCtx = userCtx
+			}
+
+			_, err := st.ImpersonationClient.Impersonate(callCtx, tt.req)
+			require.Equal(t, tt.code, status.Code(err))
+		})
+	}
+}
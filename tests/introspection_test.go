@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"github.com/Stanislau-Senkevich/GRPC_SSO/tests/suite"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+const (
+	introspectionClientID     = "family"
+	introspectionClientSecret = "dev-family-secret"
+)
+
+func TestIntrospect_HappyPath(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	user := st.SignUpRandomUser(ctx, t)
+	token := st.SignInAndGetToken(user, ctx, t)
+
+	resp, err := st.IntrospectionClient.Introspect(ctx, &ssov1.IntrospectRequest{
+		ClientId:     introspectionClientID,
+		ClientSecret: introspectionClientSecret,
+		Token:        token,
+	})
+	require.NoError(t, err)
+	require.True(t, resp.GetActive())
+	assert.Equal(t, user.ID, resp.GetUserId())
+	assert.Equal(t, user.Email, resp.GetEmail())
+	assert.Equal(t, "user", resp.GetRole())
+	assert.NotEmpty(t, resp.GetSessionId())
+	assert.Greater(t, resp.GetExp(), resp.GetIat())
+}
+
+func TestIntrospect_RevokedSession(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	user := st.SignUpRandomUser(ctx, t)
+	revokedToken := st.SignInAndGetToken(user, ctx, t)
+	currentCtx := st.SignInAndGetContext(user, ctx, t)
+
+	_, err := st.SessionsClient.RevokeAllOtherSessions(currentCtx, &ssov1.RevokeAllOtherSessionsRequest{})
+	require.NoError(t, err)
+
+	resp, err := st.IntrospectionClient.Introspect(ctx, &ssov1.IntrospectRequest{
+		ClientId:     introspectionClientID,
+		ClientSecret: introspectionClientSecret,
+		Token:        revokedToken,
+	})
+	require.NoError(t, err)
+	assert.False(t, resp.GetActive())
+	assert.Zero(t, resp.GetUserId())
+	assert.Empty(t, resp.GetEmail())
+}
+
+func TestIntrospect_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	user := st.SignUpRandomUser(ctx, t)
+	token := st.SignInAndGetToken(user, ctx, t)
+
+	resp, err := st.IntrospectionClient.Introspect(ctx, &ssov1.IntrospectRequest{
+		ClientId:     introspectionClientID,
+		ClientSecret: introspectionClientSecret,
+		Token:        "not-a-token",
+	})
+	require.NoError(t, err)
+	assert.False(t, resp.GetActive())
+
+	_, err = st.IntrospectionClient.Introspect(ctx, &ssov1.IntrospectRequest{
+		ClientId:     introspectionClientID,
+		ClientSecret: "wrong-secret",
+		Token:        token,
+	})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// A user token does not stand in for the client credentials.
+	userCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+	_, err = st.IntrospectionClient.Introspect(userCtx, &ssov1.IntrospectRequest{Token: token})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/tests/suite"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestGetLoginHistory_HappyPath(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	user := st.SignUpRandomUser(ctx, t)
+
+	_, err := st.AuthClient.SignIn(ctx, &ssov1.SignInRequest{
+		Email:    user.Email,
+		Password: "wrong" + user.PassHash,
+	})
+	require.Error(t, err)
+
+	ctx = st.SignInAndGetContext(user, ctx, t)
+
+	resp, err := st.LoginHistoryClient.GetLoginHistory(ctx, &ssov1.GetLoginHistoryRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.GetAttempts(), 2)
+
+	latest, failed := resp.GetAttempts()[0], resp.GetAttempts()[1]
+	assert.True(t, latest.GetSuccess())
+	assert.NotEmpty(t, latest.GetSessionId())
+	assert.False(t, failed.GetSuccess())
+	assert.NotEmpty(t, failed.GetFailureReason())
+	assert.False(t, latest.GetCreatedAt().AsTime().Before(failed.GetCreatedAt().AsTime()))
+
+	resp, err = st.LoginHistoryClient.GetLoginHistory(ctx, &ssov1.GetLoginHistoryRequest{Limit: 1})
+	require.NoError(t, err)
+	assert.Len(t, resp.GetAttempts(), 1)
+}
+
+func TestGetUserLoginHistory_HappyPath(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	admin := models.User{
+		Email:    "admin@gmail.com",
+		PassHash: "123",
+	}
+
+	user := st.SignUpRandomUser(ctx, t)
+	st.SignInAndGetToken(user, ctx, t)
+
+	ctx = st.SignInAndGetContext(admin, ctx, t)
+
+	resp, err := st.LoginHistoryClient.GetUserLoginHistory(ctx, &ssov1.GetUserLoginHistoryRequest{
+		UserId: user.ID,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.GetAttempts(), 1)
+	assert.True(t, resp.GetAttempts()[0].GetSuccess())
+}
+
+func TestGetUserLoginHistory_NotAdmin(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	user := st.SignUpRandomUser(ctx, t)
+
+	ctx = st.SignInAndGetContext(user, ctx, t)
+
+	_, err := st.LoginHistoryClient.GetUserLoginHistory(ctx, &ssov1.GetUserLoginHistoryRequest{
+		UserId: user.ID,
+	})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+}
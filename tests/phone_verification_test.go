@@ -0,0 +1,67 @@
+package tests
+
+import (
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/tests/suite"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"testing"
+)
+
+func TestPhoneVerification_SendAndVerify(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	user := st.SignUpRandomUser(ctx, t)
+
+	ctx = st.SignInAndGetContext(user, ctx, t)
+
+	_, err := st.PhoneClient.VerifyPhone(ctx, &ssov1.VerifyPhoneRequest{Code: "000000"})
+	require.Equal(t, codes.NotFound, status.Code(err), "no code was sent yet")
+
+	resp, err := st.PhoneClient.SendPhoneCode(ctx, &ssov1.SendPhoneCodeRequest{})
+	require.NoError(t, err)
+	require.True(t, resp.GetSucceed())
+
+	_, err = st.PhoneClient.SendPhoneCode(ctx, &ssov1.SendPhoneCodeRequest{})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	require.ErrorContains(t, err, grpcerror.ErrCodeRecentlySent.Error())
+
+	_, err = st.PhoneClient.VerifyPhone(ctx, &ssov1.VerifyPhoneRequest{Code: "not a code"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.ErrorContains(t, err, grpcerror.ErrInvalidCode.Error())
+
+	_, err = st.PhoneClient.VerifyPhone(ctx, &ssov1.VerifyPhoneRequest{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestPhoneVerification_NoPhoneNumber(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	user := st.SignUpRandomUser(ctx, t)
+
+	ctx = st.SignInAndGetContext(user, ctx, t)
+
+	_, err := st.UserInfoClient.UpdateUserInfo(ctx, &ssov1.UpdateUserInfoRequest{
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"new_phone_number"}},
+	})
+	require.NoError(t, err)
+
+	_, err = st.PhoneClient.SendPhoneCode(ctx, &ssov1.SendPhoneCodeRequest{})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestSignIn_UnverifiedPhone(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	user := st.SignUpRandomUser(ctx, t)
+
+	_, err := st.AuthClient.SignIn(ctx, &ssov1.SignInRequest{
+		Email:    user.PhoneNumber,
+		Password: user.PassHash,
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.ErrorContains(t, err, grpcerror.ErrUserNotFound.Error())
+}
@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/tests/suite"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestRestoreUser_HappyPath(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	admin := models.User{
+		Email:    "admin@gmail.com",
+		PassHash: "123",
+	}
+
+	user := st.SignUpRandomUser(ctx, t)
+
+	adminCtx := st.SignInAndGetContext(admin, ctx, t)
+
+	_, err := st.UserInfoClient.DeleteUser(adminCtx, &ssov1.DeleteUserRequest{
+		UserId: user.ID,
+	})
+	require.NoError(t, err)
+
+	resp, err := st.UserInfoClient.RestoreUser(adminCtx, &ssov1.RestoreUserRequest{
+		UserId: user.ID,
+	})
+	require.NoError(t, err)
+	require.True(t, resp.GetSucceed())
+
+	respSignIn, err := st.AuthClient.SignIn(ctx, &ssov1.SignInRequest{
+		Email:    user.Email,
+		Password: user.PassHash,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, respSignIn.GetToken())
+}
+
+func TestRestoreUser_NotDeleted(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	admin := models.User{
+		Email:    "admin@gmail.com",
+		PassHash: "123",
+	}
+
+	user := st.SignUpRandomUser(ctx, t)
+
+	adminCtx := st.SignInAndGetContext(admin, ctx, t)
+
+	_, err := st.UserInfoClient.RestoreUser(adminCtx, &ssov1.RestoreUserRequest{
+		UserId: user.ID,
+	})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	require.ErrorContains(t, err, grpcerror.ErrUserNotDeleted.Error())
+}
+
+func TestRestoreUser_NotAdmin(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	user := st.SignUpRandomUser(ctx, t)
+
+	ctx = st.SignInAndGetContext(user, ctx, t)
+
+	_, err := st.UserInfoClient.RestoreUser(ctx, &ssov1.RestoreUserRequest{
+		UserId: user.ID,
+	})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+}
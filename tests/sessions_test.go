@@ -0,0 +1,46 @@
+package tests
+
+import (
+	"github.com/Stanislau-Senkevich/GRPC_SSO/tests/suite"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestSessions_RevokeAllOtherSessions(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	user := st.SignUpRandomUser(ctx, t)
+
+	otherCtx := st.SignInAndGetContext(user, ctx, t)
+	currentCtx := st.SignInAndGetContext(user, ctx, t)
+
+	respList, err := st.SessionsClient.ListSessions(currentCtx, &ssov1.ListSessionsRequest{})
+	require.NoError(t, err)
+	require.Len(t, respList.GetSessions(), 2)
+
+	respRevoke, err := st.SessionsClient.RevokeAllOtherSessions(currentCtx, &ssov1.RevokeAllOtherSessionsRequest{})
+	require.NoError(t, err)
+	require.True(t, respRevoke.GetSucceed())
+
+	_, err = st.UserInfoClient.GetUserInfo(otherCtx, &ssov1.GetUserInfoRequest{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = st.UserInfoClient.GetUserInfo(currentCtx, &ssov1.GetUserInfoRequest{})
+	require.NoError(t, err)
+}
+
+func TestSessions_RevokeUnknownSession(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	user := st.SignUpRandomUser(ctx, t)
+
+	ctx = st.SignInAndGetContext(user, ctx, t)
+
+	_, err := st.SessionsClient.RevokeSession(ctx, &ssov1.RevokeSessionRequest{
+		SessionId: "unknown",
+	})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
+	grpcerror "github.com/Stanislau-Senkevich/GRPC_SSO/internal/error"
+	"github.com/Stanislau-Senkevich/GRPC_SSO/tests/suite"
+	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"testing"
+	"time"
+)
+
+func TestSetUserStatus_SuspendAndReactivate(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	admin := models.User{
+		Email:    "admin@gmail.com",
+		PassHash: "123",
+	}
+
+	user := st.SignUpRandomUser(ctx, t)
+
+	userCtx := st.SignInAndGetContext(user, ctx, t)
+	adminCtx := st.SignInAndGetContext(admin, ctx, t)
+
+	resp, err := st.UserInfoClient.SetUserStatus(adminCtx, &ssov1.SetUserStatusRequest{
+		UserId: user.ID,
+		Status: ssov1.AccountStatus_ACCOUNT_STATUS_SUSPENDED,
+		Reason: "suspicious activity",
+		Until:  timestamppb.New(time.Now().Add(time.Hour)),
+	})
+	require.NoError(t, err)
+	require.True(t, resp.GetSucceed())
+
+	_, err = st.AuthClient.SignIn(ctx, &ssov1.SignInRequest{
+		Email:    user.Email,
+		Password: user.PassHash,
+	})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	require.ErrorContains(t, err, grpcerror.ErrUserSuspended.Error())
+
+	_, err = st.UserInfoClient.GetUserInfo(userCtx, &ssov1.GetUserInfoRequest{})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = st.UserInfoClient.SetUserStatus(adminCtx, &ssov1.SetUserStatusRequest{
+		UserId: user.ID,
+		Status: ssov1.AccountStatus_ACCOUNT_STATUS_ACTIVE,
+	})
+	require.NoError(t, err)
+
+	st.SignInAndGetToken(user, ctx, t)
+}
+
+func TestSetUserStatus_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	admin := models.User{
+		Email:    "admin@gmail.com",
+		PassHash: "123",
+	}
+
+	user := st.SignUpRandomUser(ctx, t)
+
+	ctx = st.SignInAndGetContext(admin, ctx, t)
+
+	table := []struct {
+		name         string
+		req          *ssov1.SetUserStatusRequest
+		expectedCode codes.Code
+	}{
+		{
+			name:         "Unspecified status",
+			req:          &ssov1.SetUserStatusRequest{UserId: user.ID},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "Suspension without expiry",
+			req: &ssov1.SetUserStatusRequest{
+				UserId: user.ID,
+				Status: ssov1.AccountStatus_ACCOUNT_STATUS_SUSPENDED,
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "Unknown user",
+			req: &ssov1.SetUserStatusRequest{
+				UserId: -1,
+				Status: ssov1.AccountStatus_ACCOUNT_STATUS_DISABLED,
+			},
+			expectedCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.UserInfoClient.SetUserStatus(ctx, tt.req)
+			require.Equal(t, tt.expectedCode, status.Code(err))
+		})
+	}
+}
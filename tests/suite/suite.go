@@ -2,6 +2,7 @@ package suite
 
 import (
 	"context"
+	"fmt"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/config"
 	"github.com/Stanislau-Senkevich/GRPC_SSO/internal/domain/models"
 	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
@@ -22,10 +23,20 @@ const (
 
 type Suite struct {
 	*testing.T
-	Cfg               *config.Config
-	AuthClient        ssov1.AuthClient
-	PermissionsClient ssov1.PermissionsClient
-	UserInfoClient    ssov1.UserInfoClient
+	Cfg                 *config.Config
+	AuthClient          ssov1.AuthClient
+	PermissionsClient   ssov1.PermissionsClient
+	UserInfoClient      ssov1.UserInfoClient
+	SessionsClient      ssov1.SessionsClient
+	ExportClient        ssov1.ExportClient
+	LoginHistoryClient  ssov1.LoginHistoryClient
+	EmailChangeClient   ssov1.EmailChangeClient
+	PhoneClient         ssov1.PhoneVerificationClient
+	AttributesClient    ssov1.AttributesClient
+	AvatarsClient       ssov1.AvatarsClient
+	ImpersonationClient ssov1.ImpersonationClient
+	IntrospectionClient ssov1.IntrospectionClient
+	AccessTokensClient  ssov1.AccessTokensClient
 }
 
 func New(t *testing.T) (context.Context, *Suite) {
@@ -50,11 +61,21 @@ func New(t *testing.T) (context.Context, *Suite) {
 	}
 
 	return ctx, &Suite{
-		T:                 t,
-		Cfg:               cfg,
-		AuthClient:        ssov1.NewAuthClient(cc),
-		PermissionsClient: ssov1.NewPermissionsClient(cc),
-		UserInfoClient:    ssov1.NewUserInfoClient(cc),
+		T:                   t,
+		Cfg:                 cfg,
+		AuthClient:          ssov1.NewAuthClient(cc),
+		PermissionsClient:   ssov1.NewPermissionsClient(cc),
+		UserInfoClient:      ssov1.NewUserInfoClient(cc),
+		SessionsClient:      ssov1.NewSessionsClient(cc),
+		ExportClient:        ssov1.NewExportClient(cc),
+		LoginHistoryClient:  ssov1.NewLoginHistoryClient(cc),
+		EmailChangeClient:   ssov1.NewEmailChangeClient(cc),
+		PhoneClient:         ssov1.NewPhoneVerificationClient(cc),
+		AttributesClient:    ssov1.NewAttributesClient(cc),
+		AvatarsClient:       ssov1.NewAvatarsClient(cc),
+		ImpersonationClient: ssov1.NewImpersonationClient(cc),
+		IntrospectionClient: ssov1.NewIntrospectionClient(cc),
+		AccessTokensClient:  ssov1.NewAccessTokensClient(cc),
 	}
 }
 
@@ -94,13 +115,25 @@ func RandomFakePassword() string {
 	return gofakeit.Password(true, true, true, true, true, rand.Intn(20)+1)
 }
 
+// RandomPolicyPassword returns a random password satisfying the default password
+// policy: long enough and containing a lowercase letter, an uppercase letter and a digit.
+func RandomPolicyPassword() string {
+	return gofakeit.Password(true, true, true, true, true, rand.Intn(20)+12)
+}
+
+// RandomPhoneNumber returns a random Belarusian mobile number in E.164, valid in
+// any default region of the server.
+func RandomPhoneNumber() string {
+	return fmt.Sprintf("+37529%07d", rand.Intn(9000000)+1000000)
+}
+
 func CreateRandomUser() models.User {
 	return models.User{
 		Email:       gofakeit.Email(),
-		PhoneNumber: gofakeit.Phone(),
+		PhoneNumber: RandomPhoneNumber(),
 		Name:        gofakeit.Name(),
 		Surname:     gofakeit.Name(),
-		PassHash:    RandomFakePassword(),
+		PassHash:    RandomPolicyPassword(),
 	}
 }
 
@@ -5,6 +5,10 @@ import (
 	ssov1 "github.com/Stanislau-Senkevich/protocols/gen/go/sso"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 	"testing"
 )
 
@@ -27,3 +31,82 @@ func TestUpdateUserInfo_HappyPath(t *testing.T) {
 	require.NotEmpty(t, respUpdate.GetSucceed())
 	assert.True(t, respUpdate.GetSucceed())
 }
+
+func TestUpdateUserInfo_EmailRequiresConfirmation(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	user := st.SignUpRandomUser(ctx, t)
+
+	updatedUser := suite.CreateRandomUser()
+
+	ctx = st.SignInAndGetContext(user, ctx, t)
+
+	respUpdate, err := st.UserInfoClient.UpdateUserInfo(ctx, &ssov1.UpdateUserInfoRequest{
+		NewEmail: updatedUser.Email,
+		NewName:  updatedUser.Name,
+	})
+	require.NoError(t, err)
+	assert.True(t, respUpdate.GetSucceed())
+
+	respInfo, err := st.UserInfoClient.GetUserInfo(ctx, &ssov1.GetUserInfoRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, user.Email, respInfo.GetEmail())
+	assert.Equal(t, updatedUser.Name, respInfo.GetName())
+}
+
+func TestUpdateUserInfo_FieldMask(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	user := st.SignUpRandomUser(ctx, t)
+
+	updatedUser := suite.CreateRandomUser()
+
+	ctx = st.SignInAndGetContext(user, ctx, t)
+
+	_, err := st.UserInfoClient.UpdateUserInfo(ctx, &ssov1.UpdateUserInfoRequest{
+		NewName:    updatedUser.Name,
+		NewSurname: updatedUser.Surname,
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"new_name", "new_phone_number"}},
+	})
+	require.NoError(t, err)
+
+	respInfo, err := st.UserInfoClient.GetUserInfo(ctx, &ssov1.GetUserInfoRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, updatedUser.Name, respInfo.GetName())
+	assert.Equal(t, user.Surname, respInfo.GetSurname(), "fields outside the mask are kept")
+	assert.Empty(t, respInfo.GetPhoneNumber(), "masked empty fields are cleared")
+
+	_, err = st.UserInfoClient.UpdateUserInfo(ctx, &ssov1.UpdateUserInfoRequest{
+		NewName:    updatedUser.Name,
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"role"}},
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestUpdateUserInfo_VersionConflict(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	user := st.SignUpRandomUser(ctx, t)
+
+	ctx = st.SignInAndGetContext(user, ctx, t)
+
+	respInfo, err := st.UserInfoClient.GetUserInfo(ctx, &ssov1.GetUserInfoRequest{})
+	require.NoError(t, err)
+	version := respInfo.GetVersion()
+
+	_, err = st.UserInfoClient.UpdateUserInfo(ctx, &ssov1.UpdateUserInfoRequest{
+		NewName:         suite.CreateRandomUser().Name,
+		ExpectedVersion: wrapperspb.Int64(version),
+	})
+	require.NoError(t, err)
+
+	_, err = st.UserInfoClient.UpdateUserInfo(ctx, &ssov1.UpdateUserInfoRequest{
+		NewName:         suite.CreateRandomUser().Name,
+		ExpectedVersion: wrapperspb.Int64(version),
+	})
+	require.Equal(t, codes.Aborted, status.Code(err))
+
+	respInfo, err = st.UserInfoClient.GetUserInfo(ctx, &ssov1.GetUserInfoRequest{})
+	require.NoError(t, err)
+	assert.Greater(t, respInfo.GetVersion(), version)
+}
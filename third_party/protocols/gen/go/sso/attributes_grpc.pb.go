@@ -0,0 +1,141 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.12.4
+// source: sso/attributes.proto
+
+package ssov1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// AttributesClient is the client API for Attributes service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AttributesClient interface {
+	GetAttributes(ctx context.Context, in *GetAttributesRequest, opts ...grpc.CallOption) (*GetAttributesResponse, error)
+	SetAttributes(ctx context.Context, in *SetAttributesRequest, opts ...grpc.CallOption) (*SetAttributesResponse, error)
+}
+
+type attributesClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAttributesClient(cc grpc.ClientConnInterface) AttributesClient {
+	return &attributesClient{cc}
+}
+
+func (c *attributesClient) GetAttributes(ctx context.Context, in *GetAttributesRequest, opts ...grpc.CallOption) (*GetAttributesResponse, error) {
+	out := new(GetAttributesResponse)
+	err := c.cc.Invoke(ctx, "/attributes.Attributes/GetAttributes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *attributesClient) SetAttributes(ctx context.Context, in *SetAttributesRequest, opts ...grpc.CallOption) (*SetAttributesResponse, error) {
+	out := new(SetAttributesResponse)
+	err := c.cc.Invoke(ctx, "/attributes.Attributes/SetAttributes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AttributesServer is the server API for Attributes service.
+// All implementations must embed UnimplementedAttributesServer
+// for forward compatibility
+type AttributesServer interface {
+	GetAttributes(context.Context, *GetAttributesRequest) (*GetAttributesResponse, error)
+	SetAttributes(context.Context, *SetAttributesRequest) (*SetAttributesResponse, error)
+	mustEmbedUnimplementedAttributesServer()
+}
+
+// UnimplementedAttributesServer must be embedded to have forward compatible implementations.
+type UnimplementedAttributesServer struct {
+}
+
+func (UnimplementedAttributesServer) GetAttributes(context.Context, *GetAttributesRequest) (*GetAttributesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAttributes not implemented")
+}
+func (UnimplementedAttributesServer) SetAttributes(context.Context, *SetAttributesRequest) (*SetAttributesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetAttributes not implemented")
+}
+func (UnimplementedAttributesServer) mustEmbedUnimplementedAttributesServer() {}
+
+// UnsafeAttributesServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AttributesServer will
+// result in compilation errors.
+type UnsafeAttributesServer interface {
+	mustEmbedUnimplementedAttributesServer()
+}
+
+func RegisterAttributesServer(s grpc.ServiceRegistrar, srv AttributesServer) {
+	s.RegisterService(&Attributes_ServiceDesc, srv)
+}
+
+func _Attributes_GetAttributes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAttributesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AttributesServer).GetAttributes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/attributes.Attributes/GetAttributes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AttributesServer).GetAttributes(ctx, req.(*GetAttributesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Attributes_SetAttributes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetAttributesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AttributesServer).SetAttributes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/attributes.Attributes/SetAttributes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AttributesServer).SetAttributes(ctx, req.(*SetAttributesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Attributes_ServiceDesc is the grpc.ServiceDesc for Attributes service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Attributes_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "attributes.Attributes",
+	HandlerType: (*AttributesServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetAttributes",
+			Handler:    _Attributes_GetAttributes_Handler,
+		},
+		{
+			MethodName: "SetAttributes",
+			Handler:    _Attributes_SetAttributes_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "sso/attributes.proto",
+}
@@ -0,0 +1,212 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.12.4
+// source: sso/avatar.proto
+
+package ssov1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// AvatarsClient is the client API for Avatars service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AvatarsClient interface {
+	UploadAvatar(ctx context.Context, opts ...grpc.CallOption) (Avatars_UploadAvatarClient, error)
+	GetAvatar(ctx context.Context, in *GetAvatarRequest, opts ...grpc.CallOption) (*GetAvatarResponse, error)
+	DeleteAvatar(ctx context.Context, in *DeleteAvatarRequest, opts ...grpc.CallOption) (*DeleteAvatarResponse, error)
+}
+
+type avatarsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAvatarsClient(cc grpc.ClientConnInterface) AvatarsClient {
+	return &avatarsClient{cc}
+}
+
+func (c *avatarsClient) UploadAvatar(ctx context.Context, opts ...grpc.CallOption) (Avatars_UploadAvatarClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Avatars_ServiceDesc.Streams[0], "/avatar.Avatars/UploadAvatar", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &avatarsUploadAvatarClient{stream}
+	return x, nil
+}
+
+type Avatars_UploadAvatarClient interface {
+	Send(*AvatarChunk) error
+	CloseAndRecv() (*UploadAvatarResponse, error)
+	grpc.ClientStream
+}
+
+type avatarsUploadAvatarClient struct {
+	grpc.ClientStream
+}
+
+func (x *avatarsUploadAvatarClient) Send(m *AvatarChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *avatarsUploadAvatarClient) CloseAndRecv() (*UploadAvatarResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(UploadAvatarResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *avatarsClient) GetAvatar(ctx context.Context, in *GetAvatarRequest, opts ...grpc.CallOption) (*GetAvatarResponse, error) {
+	out := new(GetAvatarResponse)
+	err := c.cc.Invoke(ctx, "/avatar.Avatars/GetAvatar", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *avatarsClient) DeleteAvatar(ctx context.Context, in *DeleteAvatarRequest, opts ...grpc.CallOption) (*DeleteAvatarResponse, error) {
+	out := new(DeleteAvatarResponse)
+	err := c.cc.Invoke(ctx, "/avatar.Avatars/DeleteAvatar", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AvatarsServer is the server API for Avatars service.
+// All implementations must embed UnimplementedAvatarsServer
+// for forward compatibility
+type AvatarsServer interface {
+	UploadAvatar(Avatars_UploadAvatarServer) error
+	GetAvatar(context.Context, *GetAvatarRequest) (*GetAvatarResponse, error)
+	DeleteAvatar(context.Context, *DeleteAvatarRequest) (*DeleteAvatarResponse, error)
+	mustEmbedUnimplementedAvatarsServer()
+}
+
+// UnimplementedAvatarsServer must be embedded to have forward compatible implementations.
+type UnimplementedAvatarsServer struct {
+}
+
+func (UnimplementedAvatarsServer) UploadAvatar(Avatars_UploadAvatarServer) error {
+	return status.Errorf(codes.Unimplemented, "method UploadAvatar not implemented")
+}
+func (UnimplementedAvatarsServer) GetAvatar(context.Context, *GetAvatarRequest) (*GetAvatarResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAvatar not implemented")
+}
+func (UnimplementedAvatarsServer) DeleteAvatar(context.Context, *DeleteAvatarRequest) (*DeleteAvatarResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAvatar not implemented")
+}
+func (UnimplementedAvatarsServer) mustEmbedUnimplementedAvatarsServer() {}
+
+// UnsafeAvatarsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AvatarsServer will
+// result in compilation errors.
+type UnsafeAvatarsServer interface {
+	mustEmbedUnimplementedAvatarsServer()
+}
+
+func RegisterAvatarsServer(s grpc.ServiceRegistrar, srv AvatarsServer) {
+	s.RegisterService(&Avatars_ServiceDesc, srv)
+}
+
+func _Avatars_UploadAvatar_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AvatarsServer).UploadAvatar(&avatarsUploadAvatarServer{stream})
+}
+
+type Avatars_UploadAvatarServer interface {
+	SendAndClose(*UploadAvatarResponse) error
+	Recv() (*AvatarChunk, error)
+	grpc.ServerStream
+}
+
+type avatarsUploadAvatarServer struct {
+	grpc.ServerStream
+}
+
+func (x *avatarsUploadAvatarServer) SendAndClose(m *UploadAvatarResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *avatarsUploadAvatarServer) Recv() (*AvatarChunk, error) {
+	m := new(AvatarChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Avatars_GetAvatar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAvatarRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AvatarsServer).GetAvatar(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/avatar.Avatars/GetAvatar",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AvatarsServer).GetAvatar(ctx, req.(*GetAvatarRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Avatars_DeleteAvatar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAvatarRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AvatarsServer).DeleteAvatar(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/avatar.Avatars/DeleteAvatar",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AvatarsServer).DeleteAvatar(ctx, req.(*DeleteAvatarRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Avatars_ServiceDesc is the grpc.ServiceDesc for Avatars service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Avatars_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "avatar.Avatars",
+	HandlerType: (*AvatarsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetAvatar",
+			Handler:    _Avatars_GetAvatar_Handler,
+		},
+		{
+			MethodName: "DeleteAvatar",
+			Handler:    _Avatars_DeleteAvatar_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "UploadAvatar",
+			Handler:       _Avatars_UploadAvatar_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "sso/avatar.proto",
+}
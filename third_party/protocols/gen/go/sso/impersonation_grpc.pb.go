@@ -0,0 +1,105 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.12.4
+// source: sso/impersonation.proto
+
+package ssov1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// ImpersonationClient is the client API for Impersonation service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ImpersonationClient interface {
+	Impersonate(ctx context.Context, in *ImpersonateRequest, opts ...grpc.CallOption) (*ImpersonateResponse, error)
+}
+
+type impersonationClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewImpersonationClient(cc grpc.ClientConnInterface) ImpersonationClient {
+	return &impersonationClient{cc}
+}
+
+func (c *impersonationClient) Impersonate(ctx context.Context, in *ImpersonateRequest, opts ...grpc.CallOption) (*ImpersonateResponse, error) {
+	out := new(ImpersonateResponse)
+	err := c.cc.Invoke(ctx, "/impersonation.Impersonation/Impersonate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ImpersonationServer is the server API for Impersonation service.
+// All implementations must embed UnimplementedImpersonationServer
+// for forward compatibility
+type ImpersonationServer interface {
+	Impersonate(context.Context, *ImpersonateRequest) (*ImpersonateResponse, error)
+	mustEmbedUnimplementedImpersonationServer()
+}
+
+// UnimplementedImpersonationServer must be embedded to have forward compatible implementations.
+type UnimplementedImpersonationServer struct {
+}
+
+func (UnimplementedImpersonationServer) Impersonate(context.Context, *ImpersonateRequest) (*ImpersonateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Impersonate not implemented")
+}
+func (UnimplementedImpersonationServer) mustEmbedUnimplementedImpersonationServer() {}
+
+// UnsafeImpersonationServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ImpersonationServer will
+// result in compilation errors.
+type UnsafeImpersonationServer interface {
+	mustEmbedUnimplementedImpersonationServer()
+}
+
+func RegisterImpersonationServer(s grpc.ServiceRegistrar, srv ImpersonationServer) {
+	s.RegisterService(&Impersonation_ServiceDesc, srv)
+}
+
+func _Impersonation_Impersonate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImpersonateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImpersonationServer).Impersonate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/impersonation.Impersonation/Impersonate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImpersonationServer).Impersonate(ctx, req.(*ImpersonateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Impersonation_ServiceDesc is the grpc.ServiceDesc for Impersonation service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Impersonation_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "impersonation.Impersonation",
+	HandlerType: (*ImpersonationServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Impersonate",
+			Handler:    _Impersonation_Impersonate_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "sso/impersonation.proto",
+}
@@ -0,0 +1,141 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.12.4
+// source: sso/login_history.proto
+
+package ssov1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// LoginHistoryClient is the client API for LoginHistory service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type LoginHistoryClient interface {
+	GetLoginHistory(ctx context.Context, in *GetLoginHistoryRequest, opts ...grpc.CallOption) (*GetLoginHistoryResponse, error)
+	GetUserLoginHistory(ctx context.Context, in *GetUserLoginHistoryRequest, opts ...grpc.CallOption) (*GetUserLoginHistoryResponse, error)
+}
+
+type loginHistoryClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLoginHistoryClient(cc grpc.ClientConnInterface) LoginHistoryClient {
+	return &loginHistoryClient{cc}
+}
+
+func (c *loginHistoryClient) GetLoginHistory(ctx context.Context, in *GetLoginHistoryRequest, opts ...grpc.CallOption) (*GetLoginHistoryResponse, error) {
+	out := new(GetLoginHistoryResponse)
+	err := c.cc.Invoke(ctx, "/loginhistory.LoginHistory/GetLoginHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginHistoryClient) GetUserLoginHistory(ctx context.Context, in *GetUserLoginHistoryRequest, opts ...grpc.CallOption) (*GetUserLoginHistoryResponse, error) {
+	out := new(GetUserLoginHistoryResponse)
+	err := c.cc.Invoke(ctx, "/loginhistory.LoginHistory/GetUserLoginHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LoginHistoryServer is the server API for LoginHistory service.
+// All implementations must embed UnimplementedLoginHistoryServer
+// for forward compatibility
+type LoginHistoryServer interface {
+	GetLoginHistory(context.Context, *GetLoginHistoryRequest) (*GetLoginHistoryResponse, error)
+	GetUserLoginHistory(context.Context, *GetUserLoginHistoryRequest) (*GetUserLoginHistoryResponse, error)
+	mustEmbedUnimplementedLoginHistoryServer()
+}
+
+// UnimplementedLoginHistoryServer must be embedded to have forward compatible implementations.
+type UnimplementedLoginHistoryServer struct {
+}
+
+func (UnimplementedLoginHistoryServer) GetLoginHistory(context.Context, *GetLoginHistoryRequest) (*GetLoginHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLoginHistory not implemented")
+}
+func (UnimplementedLoginHistoryServer) GetUserLoginHistory(context.Context, *GetUserLoginHistoryRequest) (*GetUserLoginHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserLoginHistory not implemented")
+}
+func (UnimplementedLoginHistoryServer) mustEmbedUnimplementedLoginHistoryServer() {}
+
+// UnsafeLoginHistoryServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LoginHistoryServer will
+// result in compilation errors.
+type UnsafeLoginHistoryServer interface {
+	mustEmbedUnimplementedLoginHistoryServer()
+}
+
+func RegisterLoginHistoryServer(s grpc.ServiceRegistrar, srv LoginHistoryServer) {
+	s.RegisterService(&LoginHistory_ServiceDesc, srv)
+}
+
+func _LoginHistory_GetLoginHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLoginHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginHistoryServer).GetLoginHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/loginhistory.LoginHistory/GetLoginHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginHistoryServer).GetLoginHistory(ctx, req.(*GetLoginHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginHistory_GetUserLoginHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserLoginHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginHistoryServer).GetUserLoginHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/loginhistory.LoginHistory/GetUserLoginHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginHistoryServer).GetUserLoginHistory(ctx, req.(*GetUserLoginHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// LoginHistory_ServiceDesc is the grpc.ServiceDesc for LoginHistory service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LoginHistory_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "loginhistory.LoginHistory",
+	HandlerType: (*LoginHistoryServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetLoginHistory",
+			Handler:    _LoginHistory_GetLoginHistory_Handler,
+		},
+		{
+			MethodName: "GetUserLoginHistory",
+			Handler:    _LoginHistory_GetUserLoginHistory_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "sso/login_history.proto",
+}
@@ -0,0 +1,141 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.12.4
+// source: sso/phone_verification.proto
+
+package ssov1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// PhoneVerificationClient is the client API for PhoneVerification service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PhoneVerificationClient interface {
+	SendPhoneCode(ctx context.Context, in *SendPhoneCodeRequest, opts ...grpc.CallOption) (*SendPhoneCodeResponse, error)
+	VerifyPhone(ctx context.Context, in *VerifyPhoneRequest, opts ...grpc.CallOption) (*VerifyPhoneResponse, error)
+}
+
+type phoneVerificationClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPhoneVerificationClient(cc grpc.ClientConnInterface) PhoneVerificationClient {
+	return &phoneVerificationClient{cc}
+}
+
+func (c *phoneVerificationClient) SendPhoneCode(ctx context.Context, in *SendPhoneCodeRequest, opts ...grpc.CallOption) (*SendPhoneCodeResponse, error) {
+	out := new(SendPhoneCodeResponse)
+	err := c.cc.Invoke(ctx, "/phoneverification.PhoneVerification/SendPhoneCode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *phoneVerificationClient) VerifyPhone(ctx context.Context, in *VerifyPhoneRequest, opts ...grpc.CallOption) (*VerifyPhoneResponse, error) {
+	out := new(VerifyPhoneResponse)
+	err := c.cc.Invoke(ctx, "/phoneverification.PhoneVerification/VerifyPhone", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PhoneVerificationServer is the server API for PhoneVerification service.
+// All implementations must embed UnimplementedPhoneVerificationServer
+// for forward compatibility
+type PhoneVerificationServer interface {
+	SendPhoneCode(context.Context, *SendPhoneCodeRequest) (*SendPhoneCodeResponse, error)
+	VerifyPhone(context.Context, *VerifyPhoneRequest) (*VerifyPhoneResponse, error)
+	mustEmbedUnimplementedPhoneVerificationServer()
+}
+
+// UnimplementedPhoneVerificationServer must be embedded to have forward compatible implementations.
+type UnimplementedPhoneVerificationServer struct {
+}
+
+func (UnimplementedPhoneVerificationServer) SendPhoneCode(context.Context, *SendPhoneCodeRequest) (*SendPhoneCodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendPhoneCode not implemented")
+}
+func (UnimplementedPhoneVerificationServer) VerifyPhone(context.Context, *VerifyPhoneRequest) (*VerifyPhoneResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyPhone not implemented")
+}
+func (UnimplementedPhoneVerificationServer) mustEmbedUnimplementedPhoneVerificationServer() {}
+
+// UnsafePhoneVerificationServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PhoneVerificationServer will
+// result in compilation errors.
+type UnsafePhoneVerificationServer interface {
+	mustEmbedUnimplementedPhoneVerificationServer()
+}
+
+func RegisterPhoneVerificationServer(s grpc.ServiceRegistrar, srv PhoneVerificationServer) {
+	s.RegisterService(&PhoneVerification_ServiceDesc, srv)
+}
+
+func _PhoneVerification_SendPhoneCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendPhoneCodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PhoneVerificationServer).SendPhoneCode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/phoneverification.PhoneVerification/SendPhoneCode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PhoneVerificationServer).SendPhoneCode(ctx, req.(*SendPhoneCodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PhoneVerification_VerifyPhone_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyPhoneRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PhoneVerificationServer).VerifyPhone(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/phoneverification.PhoneVerification/VerifyPhone",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PhoneVerificationServer).VerifyPhone(ctx, req.(*VerifyPhoneRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PhoneVerification_ServiceDesc is the grpc.ServiceDesc for PhoneVerification service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PhoneVerification_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "phoneverification.PhoneVerification",
+	HandlerType: (*PhoneVerificationServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SendPhoneCode",
+			Handler:    _PhoneVerification_SendPhoneCode_Handler,
+		},
+		{
+			MethodName: "VerifyPhone",
+			Handler:    _PhoneVerification_VerifyPhone_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "sso/phone_verification.proto",
+}
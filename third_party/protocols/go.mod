@@ -0,0 +1,14 @@
+module github.com/Stanislau-Senkevich/protocols
+
+go 1.21.4
+
+require (
+	github.com/golang/protobuf v1.5.3 // indirect
+	github.com/google/grpc v1.60.0 // indirect
+	golang.org/x/net v0.16.0 // indirect
+	golang.org/x/sys v0.13.0 // indirect
+	golang.org/x/text v0.13.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20231002182017-d307bd883b97 // indirect
+	google.golang.org/grpc v1.60.1 // indirect
+	google.golang.org/protobuf v1.31.0 // indirect
+)